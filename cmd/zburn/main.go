@@ -40,14 +40,14 @@ func main() {
 	}
 }
 
-func runCLI(_ context.Context, cmd string) {
+func runCLI(ctx context.Context, cmd string) {
 	switch cmd {
 	case "version":
 		fmt.Printf("zburn %s\n", version)
 	case "email":
 		cli.CmdEmail()
 	case "identity":
-		cli.CmdIdentity(os.Args[2:])
+		cli.CmdIdentity(ctx, os.Args[2:])
 	case "list":
 		cli.CmdList(os.Args[2:])
 	case "forget":
@@ -56,6 +56,32 @@ func runCLI(_ context.Context, cmd string) {
 			os.Exit(1)
 		}
 		cli.CmdForget(os.Args[2])
+	case "burn":
+		cli.CmdBurn(ctx, os.Args[2:])
+	case "reconcile":
+		cli.CmdReconcile(ctx, os.Args[2:])
+	case "codes":
+		cli.CmdCodes(ctx, os.Args[2:])
+	case "cred":
+		cli.CmdCred(os.Args[2:])
+	case "totp":
+		cli.CmdTOTP(os.Args[2:])
+	case "template":
+		cli.CmdTemplate(os.Args[2:])
+	case "password":
+		cli.CmdPassword(os.Args[2:])
+	case "preset":
+		cli.CmdPreset(os.Args[2:])
+	case "backup":
+		cli.CmdBackup(os.Args[2:])
+	case "restore":
+		cli.CmdRestore(os.Args[2:])
+	case "import":
+		cli.CmdImport(ctx, os.Args[2:])
+	case "export":
+		cli.CmdExport(os.Args[2:])
+	case "passwd":
+		cli.CmdPasswd(os.Args[2:])
 	default:
 		fmt.Fprintf(os.Stderr, "zburn: unknown command %q\n", cmd)
 		os.Exit(1)
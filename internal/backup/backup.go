@@ -0,0 +1,297 @@
+// Package backup writes and restores passphrase-encrypted archives of a
+// whole zburn vault.
+//
+// An archive is an age (scrypt) encrypted JSON document holding the
+// decrypted entries of every collection, keyed as they are in the store,
+// with a SHA-256 checksum of the entries so truncation or tampering inside
+// the encryption layer is caught before anything is written.
+package backup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zarlcorp/core/pkg/zcrypto"
+	"github.com/zarlcorp/core/pkg/zfilesystem"
+	"github.com/zarlcorp/core/pkg/zstore"
+	"github.com/zarlcorp/zburn/internal/config"
+)
+
+// Format identifies a zburn backup; Version is bumped when the layout of
+// the archive changes.
+const (
+	Format  = "zburn-backup"
+	Version = 1
+)
+
+// Collections are the store collections included in a backup, in the order
+// they are restored.
+var Collections = []string{
+	"identities",
+	"credentials",
+	config.Collection,
+	"phones",
+	"templates",
+	"presets",
+}
+
+// ErrChecksum is returned when an archive's entries do not match its
+// checksum.
+var ErrChecksum = errors.New("backup checksum mismatch")
+
+// Snapshot holds the raw JSON entries of each collection by store key.
+type Snapshot map[string]map[string]json.RawMessage
+
+// Count returns the number of entries in a collection.
+func (s Snapshot) Count(collection string) int {
+	return len(s[collection])
+}
+
+// header is the decrypted archive.
+type header struct {
+	Format    string          `json:"format"`
+	Version   int             `json:"version"`
+	CreatedAt time.Time       `json:"created_at"`
+	Checksum  string          `json:"checksum"` // hex SHA-256 of Data
+	Data      json.RawMessage `json:"data"`
+}
+
+// Info describes a decrypted archive.
+type Info struct {
+	Version   int
+	CreatedAt time.Time
+}
+
+// Take reads every entry of the backed up collections. fsys must be the
+// filesystem s was opened on; it is used to list the keys of each
+// collection, which zstore does not expose.
+func Take(s *zstore.Store, fsys zfilesystem.ReadWriteFileFS) (Snapshot, error) {
+	snap := make(Snapshot, len(Collections))
+	for _, name := range Collections {
+		col, err := zstore.NewCollection[json.RawMessage](s, name)
+		if err != nil {
+			return nil, fmt.Errorf("open %s: %w", name, err)
+		}
+		keys, err := listKeys(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("list %s: %w", name, err)
+		}
+		entries := make(map[string]json.RawMessage, len(keys))
+		for _, k := range keys {
+			v, err := col.Get(k)
+			if err != nil {
+				return nil, fmt.Errorf("read %s/%s: %w", name, k, err)
+			}
+			entries[k] = v
+		}
+		snap[name] = entries
+	}
+	return snap, nil
+}
+
+// listKeys returns the store keys of a collection. Paths outside the
+// collection's directory are ignored, as not every filesystem limits the
+// walk to root.
+func listKeys(fsys zfilesystem.ReadWriteFileFS, name string) ([]string, error) {
+	var keys []string
+	err := fsys.WalkDir(name, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && filepath.Dir(path) == name && strings.HasSuffix(d.Name(), ".enc") {
+			keys = append(keys, strings.TrimSuffix(d.Name(), ".enc"))
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	slices.Sort(keys)
+	return keys, err
+}
+
+// Write encrypts snap with passphrase and writes the archive to w.
+func Write(w io.Writer, snap Snapshot, passphrase string, now time.Time) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("encode backup: %w", err)
+	}
+	sum := sha256.Sum256(data)
+
+	doc, err := json.Marshal(header{
+		Format:    Format,
+		Version:   Version,
+		CreatedAt: now.UTC(),
+		Checksum:  hex.EncodeToString(sum[:]),
+		Data:      data,
+	})
+	if err != nil {
+		return fmt.Errorf("encode backup: %w", err)
+	}
+
+	return zcrypto.EncryptAge(passphrase, bytes.NewReader(doc), w)
+}
+
+// Read decrypts an archive and verifies its format, version and checksum.
+func Read(r io.Reader, passphrase string) (Snapshot, Info, error) {
+	var buf bytes.Buffer
+	if err := zcrypto.DecryptAge(passphrase, r, &buf); err != nil {
+		return nil, Info{}, fmt.Errorf("decrypt backup (wrong passphrase or not a zburn backup): %w", err)
+	}
+
+	var h header
+	if err := json.Unmarshal(buf.Bytes(), &h); err != nil {
+		return nil, Info{}, fmt.Errorf("decode backup: %w", err)
+	}
+	if h.Format != Format {
+		return nil, Info{}, fmt.Errorf("not a zburn backup")
+	}
+	if h.Version < 1 || h.Version > Version {
+		return nil, Info{}, fmt.Errorf("backup version %d is not supported (this zburn reads up to %d)", h.Version, Version)
+	}
+
+	sum := sha256.Sum256(h.Data)
+	if hex.EncodeToString(sum[:]) != h.Checksum {
+		return nil, Info{}, ErrChecksum
+	}
+
+	var snap Snapshot
+	if err := json.Unmarshal(h.Data, &snap); err != nil {
+		return nil, Info{}, fmt.Errorf("decode backup: %w", err)
+	}
+	if err := snap.validate(); err != nil {
+		return nil, Info{}, err
+	}
+
+	return snap, Info{Version: h.Version, CreatedAt: h.CreatedAt}, nil
+}
+
+// validate rejects collections this version does not know and keys that
+// are not plain file names.
+func (s Snapshot) validate() error {
+	for name, entries := range s {
+		if !slices.Contains(Collections, name) {
+			return fmt.Errorf("backup has unknown collection %q", name)
+		}
+		for k := range entries {
+			if k == "" || k == "." || k == ".." || strings.ContainsAny(k, `/\`) {
+				return fmt.Errorf("backup has invalid key %q in %s", k, name)
+			}
+		}
+	}
+	return nil
+}
+
+// Mode selects how Restore treats entries already in the vault.
+type Mode int
+
+const (
+	// Merge adds entries missing from the vault and keeps existing ones.
+	Merge Mode = iota
+	// Replace makes each collection an exact copy of the backup.
+	Replace
+)
+
+// Stat counts what Restore did to one collection.
+type Stat struct {
+	Collection string `json:"collection"`
+	Added      int    `json:"added"`
+	Replaced   int    `json:"replaced"`
+	Skipped    int    `json:"skipped"`
+	Removed    int    `json:"removed"`
+}
+
+// Restore writes snap into the store. If any write fails, the entries
+// already written are rolled back to their previous values so the vault is
+// left as it was.
+func Restore(s *zstore.Store, fsys zfilesystem.ReadWriteFileFS, snap Snapshot, mode Mode) ([]Stat, error) {
+	current, err := Take(s, fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	cols := make(map[string]*zstore.Collection[json.RawMessage], len(Collections))
+	for _, name := range Collections {
+		col, err := zstore.NewCollection[json.RawMessage](s, name)
+		if err != nil {
+			return nil, fmt.Errorf("open %s: %w", name, err)
+		}
+		cols[name] = col
+	}
+
+	type change struct{ collection, key string }
+	var done []change
+
+	rollback := func() {
+		for i := len(done) - 1; i >= 0; i-- {
+			c := done[i]
+			if old, ok := current[c.collection][c.key]; ok {
+				_ = cols[c.collection].Put(c.key, old)
+			} else {
+				_ = cols[c.collection].Delete(c.key)
+			}
+		}
+	}
+
+	stats := make([]Stat, 0, len(Collections))
+	for _, name := range Collections {
+		st := Stat{Collection: name}
+		have := current[name]
+		col := cols[name]
+
+		for _, k := range sortedKeys(snap[name]) {
+			_, exists := have[k]
+			if exists && mode == Merge {
+				st.Skipped++
+				continue
+			}
+			if err := col.Put(k, snap[name][k]); err != nil {
+				rollback()
+				return nil, fmt.Errorf("restore %s/%s: %w", name, k, err)
+			}
+			done = append(done, change{name, k})
+			if exists {
+				st.Replaced++
+			} else {
+				st.Added++
+			}
+		}
+
+		if mode == Replace {
+			for _, k := range sortedKeys(have) {
+				if _, keep := snap[name][k]; keep {
+					continue
+				}
+				if err := col.Delete(k); err != nil {
+					rollback()
+					return nil, fmt.Errorf("remove %s/%s: %w", name, k, err)
+				}
+				done = append(done, change{name, k})
+				st.Removed++
+			}
+		}
+
+		stats = append(stats, st)
+	}
+
+	return stats, nil
+}
+
+func sortedKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
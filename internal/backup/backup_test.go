@@ -0,0 +1,294 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zarlcorp/core/pkg/zcrypto"
+	"github.com/zarlcorp/core/pkg/zfilesystem"
+	"github.com/zarlcorp/core/pkg/zstore"
+	"github.com/zarlcorp/zburn/internal/config"
+	"github.com/zarlcorp/zburn/internal/credential"
+	"github.com/zarlcorp/zburn/internal/identity"
+)
+
+func openStore(t *testing.T, fsys zfilesystem.ReadWriteFileFS) *zstore.Store {
+	t.Helper()
+	s, err := zstore.Open(fsys, []byte("testpass"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func put[V any](t *testing.T, s *zstore.Store, collection, key string, v V) {
+	t.Helper()
+	col, err := zstore.NewCollection[V](s, collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := col.Put(key, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func get[V any](t *testing.T, s *zstore.Store, collection, key string) (V, error) {
+	t.Helper()
+	col, err := zstore.NewCollection[V](s, collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return col.Get(key)
+}
+
+// seed fills a store with one entry of each kind.
+func seed(t *testing.T, s *zstore.Store) {
+	t.Helper()
+	put(t, s, "identities", "id1", identity.Identity{ID: "id1", FirstName: "Jane", Email: "jane@zburn.id"})
+	put(t, s, "credentials", "c1", credential.Credential{ID: "c1", IdentityID: "id1", Label: "GitHub", Password: "pw"})
+	put(t, s, config.Collection, config.KeyTwilio, config.Envelope{Data: json.RawMessage(`{"account_sid":"AC1"}`)})
+	put(t, s, "presets", identity.PresetKey("example.com"), identity.PasswordPreset{Site: "example.com", Policy: identity.PassphrasePolicy(4)})
+}
+
+func TestRoundTrip(t *testing.T) {
+	srcFS := zfilesystem.NewMemFS()
+	src := openStore(t, srcFS)
+	seed(t, src)
+
+	snap, err := Take(src, srcFS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.Count("identities") != 1 || snap.Count("credentials") != 1 || snap.Count(config.Collection) != 1 {
+		t.Fatalf("snapshot counts wrong: %v", snap)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, snap, "backup-pass", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte("GitHub")) {
+		t.Fatal("archive should be encrypted")
+	}
+
+	got, info, err := Read(bytes.NewReader(buf.Bytes()), "backup-pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Version != Version {
+		t.Errorf("version = %d, want %d", info.Version, Version)
+	}
+
+	// restore into a fresh vault with a different master password
+	dstFS := zfilesystem.NewMemFS()
+	dst, err := zstore.Open(dstFS, []byte("other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+
+	stats, err := Restore(dst, dstFS, got, Merge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats[0].Collection != "identities" || stats[0].Added != 1 {
+		t.Errorf("identities stat = %+v, want 1 added", stats[0])
+	}
+
+	c, err := get[credential.Credential](t, dst, "credentials", "c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Label != "GitHub" || c.Password != "pw" {
+		t.Errorf("restored credential = %+v", c)
+	}
+	p, err := get[identity.PasswordPreset](t, dst, "presets", identity.PresetKey("example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.Policy.Passphrase || p.Policy.Words != 4 {
+		t.Errorf("restored preset = %+v", p)
+	}
+}
+
+func TestReadWrongPassphrase(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, Snapshot{}, "right", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Read(&buf, "wrong"); err == nil || !strings.Contains(err.Error(), "passphrase") {
+		t.Errorf("err = %v, want passphrase error", err)
+	}
+}
+
+// encryptHeader writes an archive with an arbitrary header.
+func encryptHeader(t *testing.T, h header) *bytes.Buffer {
+	t.Helper()
+	doc, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := zcrypto.EncryptAge("pass", bytes.NewReader(doc), &buf); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestReadRejects(t *testing.T) {
+	checksum := func(data string) string {
+		sum := sha256.Sum256([]byte(data))
+		return hex.EncodeToString(sum[:])
+	}
+	data := `{"identities":{"id1":{"id":"id1"}}}`
+
+	tests := []struct {
+		name string
+		h    header
+		want string
+	}{
+		{"format", header{Format: "other", Version: 1, Checksum: checksum(data), Data: json.RawMessage(data)}, "not a zburn backup"},
+		{"newer version", header{Format: Format, Version: Version + 1, Checksum: checksum(data), Data: json.RawMessage(data)}, "not supported"},
+		{"checksum", header{Format: Format, Version: 1, Checksum: checksum("{}"), Data: json.RawMessage(data)}, "checksum"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Read(encryptHeader(t, tt.h), "pass")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnapshotValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		snap Snapshot
+		want string
+	}{
+		{"ok", Snapshot{"identities": {"id1": nil}, config.Collection: {config.KeyGmail: nil}}, ""},
+		{"unknown collection", Snapshot{"x": {}}, "unknown collection"},
+		{"path key", Snapshot{"identities": {"../salt": nil}}, "invalid key"},
+		{"empty key", Snapshot{"credentials": {"": nil}}, "invalid key"},
+	}
+	for _, tt := range tests {
+		err := tt.snap.validate()
+		if tt.want == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want containing %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestRestoreModes(t *testing.T) {
+	snap := Snapshot{
+		"identities": {
+			"id1": json.RawMessage(`{"id":"id1","first_name":"Backup"}`),
+			"id2": json.RawMessage(`{"id":"id2","first_name":"Second"}`),
+		},
+	}
+
+	tests := []struct {
+		name      string
+		mode      Mode
+		wantFirst string
+		wantStat  Stat
+		keepLocal bool
+	}{
+		{"merge", Merge, "Local", Stat{Collection: "identities", Added: 1, Skipped: 1}, true},
+		{"replace", Replace, "Backup", Stat{Collection: "identities", Added: 1, Replaced: 1, Removed: 1}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := zfilesystem.NewMemFS()
+			s := openStore(t, fsys)
+			put(t, s, "identities", "id1", identity.Identity{ID: "id1", FirstName: "Local"})
+			put(t, s, "identities", "local", identity.Identity{ID: "local"})
+
+			stats, err := Restore(s, fsys, snap, tt.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stats[0] != tt.wantStat {
+				t.Errorf("stat = %+v, want %+v", stats[0], tt.wantStat)
+			}
+
+			id, err := get[identity.Identity](t, s, "identities", "id1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if id.FirstName != tt.wantFirst {
+				t.Errorf("id1 first name = %q, want %q", id.FirstName, tt.wantFirst)
+			}
+			if _, err := get[identity.Identity](t, s, "identities", "id2"); err != nil {
+				t.Errorf("id2 should be restored: %v", err)
+			}
+			_, err = get[identity.Identity](t, s, "identities", "local")
+			if tt.keepLocal != (err == nil) {
+				t.Errorf("local entry present = %v, want %v", err == nil, tt.keepLocal)
+			}
+		})
+	}
+}
+
+// failingFS fails the write numbered failOn, counting from one.
+type failingFS struct {
+	*zfilesystem.MemFS
+	writes, failOn int
+}
+
+func (f *failingFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	f.writes++
+	if f.writes == f.failOn {
+		return errors.New("disk full")
+	}
+	return f.MemFS.WriteFile(name, data, perm)
+}
+
+func TestRestoreRollsBack(t *testing.T) {
+	fsys := &failingFS{MemFS: zfilesystem.NewMemFS()}
+	s := openStore(t, fsys)
+	put(t, s, "identities", "a", identity.Identity{ID: "a", FirstName: "Local"})
+	put(t, s, "identities", "b", identity.Identity{ID: "b", FirstName: "Local"})
+
+	snap := Snapshot{
+		"identities": {
+			"a": json.RawMessage(`{"id":"a","first_name":"Backup"}`),
+			"b": json.RawMessage(`{"id":"b","first_name":"Backup"}`),
+			"c": json.RawMessage(`{"id":"c","first_name":"Backup"}`),
+		},
+	}
+
+	// the third write fails, after a and b were overwritten
+	fsys.writes, fsys.failOn = 0, 3
+	if _, err := Restore(s, fsys, snap, Replace); err == nil {
+		t.Fatal("expected error")
+	}
+
+	for _, k := range []string{"a", "b"} {
+		id, err := get[identity.Identity](t, s, "identities", k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id.FirstName != "Local" {
+			t.Errorf("%s first name = %q, want rolled back to Local", k, id.FirstName)
+		}
+	}
+	if _, err := get[identity.Identity](t, s, "identities", "c"); !errors.Is(err, zstore.ErrNotFound) {
+		t.Errorf("c should not exist after rollback, err = %v", err)
+	}
+}
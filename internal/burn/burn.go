@@ -3,11 +3,14 @@ package burn
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/zarlcorp/zburn/internal/credential"
 	"github.com/zarlcorp/zburn/internal/identity"
+	"github.com/zarlcorp/zburn/internal/namecheap"
 )
 
 // CredentialStore lists and deletes credentials.
@@ -26,10 +29,30 @@ type PhoneReleaser interface {
 	ReleaseNumber(ctx context.Context, numberSID string) error
 }
 
+// ForwardingRemover removes mailbox forwarding rules.
+type ForwardingRemover interface {
+	RemoveForwarding(ctx context.Context, domain, mailbox string) error
+}
+
+// ForwardingLedger forgets the forwarding rules zburn created once they
+// are removed.
+type ForwardingLedger interface {
+	Forget(domain, mailbox string) error
+}
+
+// PhoneStore deletes the phone mapping for an identity.
+type PhoneStore interface {
+	Delete(identityID string) error
+}
+
+// ErrPhoneNotReleased is the error of the identity step when the identity
+// was kept because its provisioned phone number could not be released.
+var ErrPhoneNotReleased = errors.New("kept while its phone number is still provisioned")
+
 // PhoneConfig holds provisioned phone details for an identity.
 type PhoneConfig struct {
-	NumberSID   string // Twilio SID for the provisioned number
-	PhoneNumber string // display number e.g. "+447123456789"
+	NumberSID   string `json:"number_sid"`   // Twilio SID for the provisioned number
+	PhoneNumber string `json:"phone_number"` // display number e.g. "+447123456789"
 }
 
 // Request describes what to burn.
@@ -37,8 +60,11 @@ type Request struct {
 	Identity    identity.Identity
 	Credentials CredentialStore
 	Identities  IdentityStore
-	Phone       *PhoneConfig  // nil if no provisioned phone
-	Releaser    PhoneReleaser // nil if twilio not configured
+	Phone       *PhoneConfig      // nil if no provisioned phone
+	Releaser    PhoneReleaser     // nil if twilio not configured
+	Phones      PhoneStore        // nil if phone mappings are not persisted
+	Forwarding  ForwardingRemover // nil unless zburn created the email's forwarding rule
+	Ledger      ForwardingLedger  // nil if created rules are not recorded
 }
 
 // StepStatus records the outcome of one cascade step.
@@ -47,11 +73,25 @@ type StepStatus struct {
 	Err         error
 }
 
+// MarshalJSON renders the step with its error as a string, since error
+// values do not encode.
+func (s StepStatus) MarshalJSON() ([]byte, error) {
+	out := struct {
+		Description string `json:"description"`
+		OK          bool   `json:"ok"`
+		Error       string `json:"error,omitempty"`
+	}{Description: s.Description, OK: s.Err == nil}
+	if s.Err != nil {
+		out.Error = s.Err.Error()
+	}
+	return json.Marshal(out)
+}
+
 // Result summarizes a completed burn.
 type Result struct {
-	Name             string
-	CredentialsCount int
-	Steps            []StepStatus
+	Name             string       `json:"name"`
+	CredentialsCount int          `json:"credentials_count"`
+	Steps            []StepStatus `json:"steps"`
 }
 
 // HasErrors returns true if any step failed.
@@ -100,16 +140,24 @@ func Plan(req Request) []string {
 		steps = append(steps, "delete all credentials")
 	}
 
-	if req.Releaser != nil && req.Phone != nil {
+	switch {
+	case req.Phone != nil && req.Releaser != nil:
 		steps = append(steps, fmt.Sprintf("release phone number %s", req.Phone.PhoneNumber))
+	case req.Phone != nil:
+		steps = append(steps, fmt.Sprintf("keep the identity: phone number %s cannot be released without twilio", req.Phone.PhoneNumber))
+	}
+
+	if req.Forwarding != nil && req.Identity.Email != "" {
+		steps = append(steps, fmt.Sprintf("remove forwarding for %s", req.Identity.Email))
 	}
 
 	return steps
 }
 
 // Execute runs the burn cascade. It is best-effort: each step is attempted
-// regardless of whether previous steps failed. The identity itself is always
-// deleted last.
+// regardless of whether previous steps failed. The identity itself is
+// deleted last, and only once its phone number is released, so a number
+// that is still billed stays attached to something the user can see.
 func Execute(ctx context.Context, req Request) Result {
 	name := req.Identity.FirstName + " " + req.Identity.LastName
 	result := Result{Name: name}
@@ -118,11 +166,24 @@ func Execute(ctx context.Context, req Request) Result {
 	result.deleteCredentials(req)
 
 	// 2. release phone number
-	if req.Releaser != nil && req.Phone != nil {
-		result.releasePhone(ctx, req)
+	released := true
+	if req.Phone != nil {
+		released = result.releasePhone(ctx, req)
+	}
+
+	// 3. remove mailbox forwarding
+	if req.Forwarding != nil && req.Identity.Email != "" {
+		result.removeForwarding(ctx, req)
 	}
 
-	// 3. delete identity
+	// 4. delete identity
+	if !released {
+		result.Steps = append(result.Steps, StepStatus{
+			Description: "delete identity",
+			Err:         ErrPhoneNotReleased,
+		})
+		return result
+	}
 	result.deleteIdentity(req)
 
 	return result
@@ -170,18 +231,77 @@ func (r *Result) deleteCredentials(req Request) {
 	})
 }
 
-func (r *Result) releasePhone(ctx context.Context, req Request) {
-	err := req.Releaser.ReleaseNumber(ctx, req.Phone.NumberSID)
-	if err != nil {
+// releasePhone releases the identity's number and reports whether it is
+// gone. On failure the mapping is kept so the number is not forgotten while
+// still billed.
+func (r *Result) releasePhone(ctx context.Context, req Request) bool {
+	desc := fmt.Sprintf("release phone number %s (sid %s)", req.Phone.PhoneNumber, req.Phone.NumberSID)
+	if req.Releaser == nil {
 		r.Steps = append(r.Steps, StepStatus{
-			Description: fmt.Sprintf("release phone number %s", req.Phone.PhoneNumber),
-			Err:         err,
+			Description: desc,
+			Err:         errors.New("twilio not configured"),
 		})
-		return
+		return false
+	}
+
+	if err := req.Releaser.ReleaseNumber(ctx, req.Phone.NumberSID); err != nil {
+		r.Steps = append(r.Steps, StepStatus{Description: desc, Err: err})
+		return false
 	}
 	r.Steps = append(r.Steps, StepStatus{
 		Description: fmt.Sprintf("released phone number %s", req.Phone.PhoneNumber),
 	})
+
+	if req.Phones == nil {
+		return true
+	}
+	if err := req.Phones.Delete(req.Identity.ID); err != nil {
+		r.Steps = append(r.Steps, StepStatus{
+			Description: "delete phone mapping",
+			Err:         err,
+		})
+	}
+	return true
+}
+
+func (r *Result) removeForwarding(ctx context.Context, req Request) {
+	email := req.Identity.Email
+	mailbox, domain, ok := strings.Cut(email, "@")
+	if !ok || mailbox == "" || domain == "" {
+		r.Steps = append(r.Steps, StepStatus{
+			Description: fmt.Sprintf("remove forwarding for %s", email),
+			Err:         fmt.Errorf("invalid email address"),
+		})
+		return
+	}
+
+	err := req.Forwarding.RemoveForwarding(ctx, domain, mailbox)
+	switch {
+	case errors.Is(err, namecheap.ErrMailboxNotFound):
+		r.Steps = append(r.Steps, StepStatus{
+			Description: fmt.Sprintf("no forwarding rule for %s", email),
+		})
+	case err != nil:
+		r.Steps = append(r.Steps, StepStatus{
+			Description: fmt.Sprintf("remove forwarding for %s", email),
+			Err:         err,
+		})
+		return
+	default:
+		r.Steps = append(r.Steps, StepStatus{
+			Description: fmt.Sprintf("removed forwarding for %s", email),
+		})
+	}
+
+	if req.Ledger == nil {
+		return
+	}
+	if err := req.Ledger.Forget(domain, mailbox); err != nil {
+		r.Steps = append(r.Steps, StepStatus{
+			Description: fmt.Sprintf("forget forwarding rule for %s", email),
+			Err:         err,
+		})
+	}
 }
 
 func (r *Result) deleteIdentity(req Request) {
@@ -2,6 +2,8 @@ package burn
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -9,6 +11,7 @@ import (
 
 	"github.com/zarlcorp/zburn/internal/credential"
 	"github.com/zarlcorp/zburn/internal/identity"
+	"github.com/zarlcorp/zburn/internal/namecheap"
 )
 
 // fakes
@@ -58,6 +61,41 @@ func (f *fakeReleaser) ReleaseNumber(_ context.Context, numberSID string) error
 	return f.err
 }
 
+type fakeForwardingRemover struct {
+	domain  string
+	mailbox string
+	err     error
+}
+
+func (f *fakeForwardingRemover) RemoveForwarding(_ context.Context, domain, mailbox string) error {
+	f.domain = domain
+	f.mailbox = mailbox
+	return f.err
+}
+
+type fakeLedger struct {
+	forgot []string
+	err    error
+}
+
+func (f *fakeLedger) Forget(domain, mailbox string) error {
+	f.forgot = append(f.forgot, mailbox+"@"+domain)
+	return f.err
+}
+
+type fakePhoneStore struct {
+	deleted []string
+	delErr  error
+}
+
+func (f *fakePhoneStore) Delete(id string) error {
+	if f.delErr != nil {
+		return f.delErr
+	}
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
 // helpers
 
 func testIdentity() identity.Identity {
@@ -193,9 +231,13 @@ func TestExecutePhoneFailureContinues(t *testing.T) {
 		t.Error("should have errors when phone release fails")
 	}
 
-	// identity should still be deleted
-	if len(is.deleted) != 1 {
-		t.Errorf("identity deletes = %d, want 1", len(is.deleted))
+	// the identity is kept so the still-billed number is not forgotten
+	if len(is.deleted) != 0 {
+		t.Errorf("identity deletes = %d, want 0", len(is.deleted))
+	}
+	last := result.Steps[len(result.Steps)-1]
+	if !errors.Is(last.Err, ErrPhoneNotReleased) {
+		t.Errorf("last step = %+v, want identity kept", last)
 	}
 
 	if !strings.Contains(result.Summary(), "twilio api error") {
@@ -203,6 +245,217 @@ func TestExecutePhoneFailureContinues(t *testing.T) {
 	}
 }
 
+func TestExecuteNoReleaserKeepsIdentity(t *testing.T) {
+	cs := &fakeCredentialStore{creds: testCreds("id-001", 2)}
+	is := &fakeIdentityStore{}
+	ps := &fakePhoneStore{}
+	fwd := &fakeForwardingRemover{}
+
+	req := Request{
+		Identity:    testIdentity(),
+		Credentials: cs,
+		Identities:  is,
+		Phone:       &PhoneConfig{NumberSID: "PN_abc", PhoneNumber: "+447123456789"},
+		Phones:      ps,
+		Forwarding:  fwd,
+	}
+
+	result := Execute(context.Background(), req)
+
+	if len(is.deleted) != 0 || len(ps.deleted) != 0 {
+		t.Errorf("identity deletes = %v, phone mapping deletes = %v, want none", is.deleted, ps.deleted)
+	}
+	// the other steps still run
+	if len(cs.deleted) != 2 || fwd.mailbox != "swiftwolf1234" {
+		t.Errorf("credentials deleted = %d, forwarding removed for %q", len(cs.deleted), fwd.mailbox)
+	}
+
+	summary := result.Summary()
+	for _, want := range []string{"PN_abc", "twilio not configured", ErrPhoneNotReleased.Error()} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary should contain %q: %s", want, summary)
+		}
+	}
+}
+
+func TestExecuteReleaseDeletesPhoneMapping(t *testing.T) {
+	ps := &fakePhoneStore{}
+
+	req := Request{
+		Identity:    testIdentity(),
+		Credentials: &fakeCredentialStore{},
+		Identities:  &fakeIdentityStore{},
+		Phone:       &PhoneConfig{NumberSID: "PN_abc", PhoneNumber: "+447123456789"},
+		Releaser:    &fakeReleaser{},
+		Phones:      ps,
+	}
+
+	result := Execute(context.Background(), req)
+
+	if result.HasErrors() {
+		t.Errorf("unexpected errors: %s", result.Summary())
+	}
+	if len(ps.deleted) != 1 || ps.deleted[0] != "id-001" {
+		t.Errorf("phone mapping deletes = %v, want [id-001]", ps.deleted)
+	}
+}
+
+func TestExecuteReleaseFailureKeepsPhoneMapping(t *testing.T) {
+	ps := &fakePhoneStore{}
+
+	req := Request{
+		Identity:    testIdentity(),
+		Credentials: &fakeCredentialStore{},
+		Identities:  &fakeIdentityStore{},
+		Phone:       &PhoneConfig{NumberSID: "PN_abc", PhoneNumber: "+447123456789"},
+		Releaser:    &fakeReleaser{err: fmt.Errorf("twilio api error")},
+		Phones:      ps,
+	}
+
+	result := Execute(context.Background(), req)
+
+	if len(ps.deleted) != 0 {
+		t.Errorf("phone mapping should be kept, deleted %v", ps.deleted)
+	}
+	if !strings.Contains(result.Summary(), "PN_abc") {
+		t.Errorf("summary should include the number SID: %s", result.Summary())
+	}
+}
+
+func TestExecutePhoneMappingDeleteError(t *testing.T) {
+	req := Request{
+		Identity:    testIdentity(),
+		Credentials: &fakeCredentialStore{},
+		Identities:  &fakeIdentityStore{},
+		Phone:       &PhoneConfig{NumberSID: "PN_abc", PhoneNumber: "+447123456789"},
+		Releaser:    &fakeReleaser{},
+		Phones:      &fakePhoneStore{delErr: fmt.Errorf("disk full")},
+	}
+
+	result := Execute(context.Background(), req)
+
+	if !strings.Contains(result.Summary(), "disk full") {
+		t.Errorf("summary should contain error: %s", result.Summary())
+	}
+}
+
+func TestExecuteRemovesForwarding(t *testing.T) {
+	fwd := &fakeForwardingRemover{}
+
+	req := Request{
+		Identity:    testIdentity(),
+		Credentials: &fakeCredentialStore{},
+		Identities:  &fakeIdentityStore{},
+		Forwarding:  fwd,
+	}
+
+	result := Execute(context.Background(), req)
+
+	if result.HasErrors() {
+		t.Errorf("unexpected errors: %s", result.Summary())
+	}
+	if fwd.domain != "zburn.id" || fwd.mailbox != "swiftwolf1234" {
+		t.Errorf("removed %s@%s, want swiftwolf1234@zburn.id", fwd.mailbox, fwd.domain)
+	}
+	if !strings.Contains(result.Summary(), "removed forwarding for swiftwolf1234@zburn.id") {
+		t.Errorf("summary should record forwarding removal: %s", result.Summary())
+	}
+}
+
+func TestExecuteForwardingNotFound(t *testing.T) {
+	fwd := &fakeForwardingRemover{err: fmt.Errorf("remove forwarding: mailbox %q %w", "swiftwolf1234", namecheap.ErrMailboxNotFound)}
+
+	req := Request{
+		Identity:    testIdentity(),
+		Credentials: &fakeCredentialStore{},
+		Identities:  &fakeIdentityStore{},
+		Forwarding:  fwd,
+	}
+
+	result := Execute(context.Background(), req)
+
+	if result.HasErrors() {
+		t.Errorf("missing rule should not be an error: %s", result.Summary())
+	}
+	if !strings.Contains(result.Summary(), "no forwarding rule") {
+		t.Errorf("summary should note missing rule: %s", result.Summary())
+	}
+}
+
+func TestExecuteForwardingFailureContinues(t *testing.T) {
+	is := &fakeIdentityStore{}
+
+	req := Request{
+		Identity:    testIdentity(),
+		Credentials: &fakeCredentialStore{},
+		Identities:  is,
+		Forwarding:  &fakeForwardingRemover{err: fmt.Errorf("api: rate limited")},
+	}
+
+	result := Execute(context.Background(), req)
+
+	if !strings.Contains(result.Summary(), "rate limited") {
+		t.Errorf("summary should contain error: %s", result.Summary())
+	}
+	if len(is.deleted) != 1 {
+		t.Errorf("identity deletes = %d, want 1", len(is.deleted))
+	}
+}
+
+func TestExecuteForwardingLedger(t *testing.T) {
+	tests := []struct {
+		name       string
+		removeErr  error
+		forgetErr  error
+		wantForgot bool
+		wantErrors bool
+	}{
+		{"removed", nil, nil, true, false},
+		{"already gone", namecheap.ErrMailboxNotFound, nil, true, false},
+		{"remove failed", fmt.Errorf("api: rate limited"), nil, false, true},
+		{"forget failed", nil, fmt.Errorf("store closed"), true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ledger := &fakeLedger{err: tt.forgetErr}
+			req := Request{
+				Identity:    testIdentity(),
+				Credentials: &fakeCredentialStore{},
+				Identities:  &fakeIdentityStore{},
+				Forwarding:  &fakeForwardingRemover{err: tt.removeErr},
+				Ledger:      ledger,
+			}
+
+			result := Execute(context.Background(), req)
+
+			if forgot := len(ledger.forgot) == 1; forgot != tt.wantForgot {
+				t.Errorf("forgot = %v, want %v", ledger.forgot, tt.wantForgot)
+			}
+			if result.HasErrors() != tt.wantErrors {
+				t.Errorf("errors = %v, want %v: %s", result.HasErrors(), tt.wantErrors, result.Summary())
+			}
+		})
+	}
+}
+
+func TestPlanForwarding(t *testing.T) {
+	req := Request{
+		Identity:    testIdentity(),
+		Credentials: &fakeCredentialStore{},
+		Forwarding:  &fakeForwardingRemover{},
+	}
+
+	steps := Plan(req)
+
+	if len(steps) != 2 {
+		t.Fatalf("plan steps = %d, want 2", len(steps))
+	}
+	if !strings.Contains(steps[1], "swiftwolf1234@zburn.id") {
+		t.Errorf("step 1 = %q, want forwarding removal", steps[1])
+	}
+}
+
 func TestExecuteCredentialListError(t *testing.T) {
 	cs := &fakeCredentialStore{listErr: fmt.Errorf("store corrupt")}
 	is := &fakeIdentityStore{}
@@ -338,6 +591,20 @@ func TestPlanFullConfig(t *testing.T) {
 	}
 }
 
+func TestPlanPhoneWithoutReleaser(t *testing.T) {
+	req := Request{
+		Identity:    testIdentity(),
+		Credentials: &fakeCredentialStore{},
+		Phone:       &PhoneConfig{NumberSID: "PN_abc", PhoneNumber: "+447123456789"},
+	}
+
+	steps := Plan(req)
+
+	if len(steps) != 2 || !strings.Contains(steps[1], "keep the identity") {
+		t.Errorf("steps = %q, want the identity kept", steps)
+	}
+}
+
 func TestPlanNoExternal(t *testing.T) {
 	cs := &fakeCredentialStore{}
 
@@ -452,3 +719,26 @@ func TestResultHasErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestResultJSON(t *testing.T) {
+	r := Result{
+		Name:             "Jane Doe",
+		CredentialsCount: 2,
+		Steps: []StepStatus{
+			{Description: "deleted 2 credentials"},
+			{Description: "release phone number +441234", Err: fmt.Errorf("timeout")},
+		},
+	}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"name":"Jane Doe","credentials_count":2,"steps":[` +
+		`{"description":"deleted 2 credentials","ok":true},` +
+		`{"description":"release phone number +441234","ok":false,"error":"timeout"}]}`
+	if string(b) != want {
+		t.Errorf("json = %s\nwant   %s", b, want)
+	}
+}
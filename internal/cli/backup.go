@@ -0,0 +1,233 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zarlcorp/core/pkg/zfilesystem"
+	"github.com/zarlcorp/zburn/internal/backup"
+)
+
+const backupUsage = `usage: zburn backup <file|-> [--force]
+       zburn restore <file|-> [--replace] [--yes] [--json]`
+
+// CmdBackup writes an encrypted archive of the whole vault. The archive
+// has its own passphrase so it can be kept apart from the master password.
+func CmdBackup(args []string) {
+	out := firstArg(args)
+	if out == "" {
+		fmt.Fprintln(os.Stderr, backupUsage)
+		os.Exit(1)
+	}
+	if out != "-" && !hasFlag(args, "--force") {
+		if _, err := os.Stat(out); err == nil {
+			fmt.Fprintf(os.Stderr, "zburn: backup: %s already exists (use --force to overwrite)\n", out)
+			os.Exit(1)
+		}
+	}
+
+	dir := DataDir()
+	s, _, err := OpenStore(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+		os.Exit(1)
+	}
+	defer s.Close()
+
+	snap, err := backup.Take(s, zfilesystem.NewOSFileSystem(dir))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: backup: %v\n", err)
+		os.Exit(1)
+	}
+
+	pass, err := readBackupPassphrase(true)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: backup: %v\n", err)
+		os.Exit(1)
+	}
+
+	write := func(w io.Writer) error { return backup.Write(w, snap, pass, time.Now()) }
+	info := os.Stdout
+	if out == "-" {
+		err = write(os.Stdout)
+		info = os.Stderr
+	} else {
+		err = writeFileAtomic(out, write)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: backup: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Fprintf(info, "backed up %s\n", snapshotSummary(snap))
+}
+
+// CmdRestore restores an archive written by CmdBackup. By default entries
+// already in the vault are kept; --replace makes the vault match the
+// archive exactly.
+func CmdRestore(args []string) {
+	in := firstArg(args)
+	if in == "" {
+		fmt.Fprintln(os.Stderr, backupUsage)
+		os.Exit(1)
+	}
+
+	mode := backup.Merge
+	if hasFlag(args, "--replace") {
+		mode = backup.Replace
+	}
+
+	r := io.Reader(os.Stdin)
+	if in != "-" {
+		f, err := os.Open(in)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "zburn: restore: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	pass, err := readBackupPassphrase(false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: restore: %v\n", err)
+		os.Exit(1)
+	}
+
+	// verify the whole archive before the vault is opened or touched
+	snap, info, err := backup.Read(r, pass)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: restore: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "backup from %s: %s\n", info.CreatedAt.Local().Format("2006-01-02 15:04"), snapshotSummary(snap))
+
+	if mode == backup.Replace && !hasFlag(args, "--yes") {
+		// stdin already held the archive, so it cannot answer the prompt
+		if in == "-" {
+			fmt.Fprintln(os.Stderr, "zburn: restore: --replace from stdin needs --yes")
+			os.Exit(1)
+		}
+		if !confirm(os.Stdin, os.Stderr, "replace the vault's contents with this backup? entries not in it are deleted. [y/N] ") {
+			fmt.Fprintln(os.Stderr, "aborted")
+			os.Exit(1)
+		}
+	}
+
+	dir := DataDir()
+	s, _, err := OpenStore(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+		os.Exit(1)
+	}
+	defer s.Close()
+
+	stats, err := backup.Restore(s, zfilesystem.NewOSFileSystem(dir), snap, mode)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: restore: %v (vault left unchanged)\n", err)
+		os.Exit(1)
+	}
+
+	if hasFlag(args, "--json") {
+		printJSON(stats)
+		return
+	}
+	printRestoreStats(os.Stdout, stats)
+}
+
+// readBackupPassphrase reads the archive passphrase, from
+// ZBURN_BACKUP_PASSWORD if set. New passphrases are confirmed.
+func readBackupPassphrase(isNew bool) (string, error) {
+	if p := os.Getenv("ZBURN_BACKUP_PASSWORD"); p != "" {
+		return p, nil
+	}
+	pass, err := promptPassword("backup passphrase: ", os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	if pass == "" {
+		return "", fmt.Errorf("backup passphrase must not be empty")
+	}
+	if isNew {
+		again, err := promptPassword("confirm passphrase: ", os.Stderr)
+		if err != nil {
+			return "", err
+		}
+		if pass != again {
+			return "", fmt.Errorf("passphrases do not match")
+		}
+	}
+	return pass, nil
+}
+
+// writeFileAtomic writes path through a temporary file in the same
+// directory, so a failed write never leaves a truncated file behind.
+func writeFileAtomic(path string, write func(io.Writer) error) error {
+	f, err := os.CreateTemp(filepath.Dir(path), ".zburn-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := f.Chmod(0o600); err != nil {
+		f.Close()
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// snapshotSummary counts the entries of a snapshot, e.g.
+// "3 identities, 5 credentials, 2 config".
+func snapshotSummary(snap backup.Snapshot) string {
+	var parts []string
+	for _, name := range backup.Collections {
+		if n := snap.Count(name); n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, name))
+		}
+	}
+	if len(parts) == 0 {
+		return "an empty vault"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func printRestoreStats(w io.Writer, stats []backup.Stat) {
+	if !slices.ContainsFunc(stats, func(st backup.Stat) bool {
+		return st.Added+st.Replaced+st.Skipped+st.Removed > 0
+	}) {
+		fmt.Fprintln(w, "nothing to restore")
+		return
+	}
+	for _, st := range stats {
+		if st.Added+st.Replaced+st.Skipped+st.Removed == 0 {
+			continue
+		}
+		var parts []string
+		for _, c := range []struct {
+			n    int
+			verb string
+		}{
+			{st.Added, "added"},
+			{st.Replaced, "replaced"},
+			{st.Skipped, "kept"},
+			{st.Removed, "removed"},
+		} {
+			if c.n > 0 {
+				parts = append(parts, fmt.Sprintf("%d %s", c.n, c.verb))
+			}
+		}
+		fmt.Fprintf(w, "%-12s %s\n", st.Collection+":", strings.Join(parts, ", "))
+	}
+}
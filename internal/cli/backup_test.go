@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zarlcorp/zburn/internal/backup"
+)
+
+func TestSnapshotSummary(t *testing.T) {
+	tests := []struct {
+		snap backup.Snapshot
+		want string
+	}{
+		{backup.Snapshot{}, "an empty vault"},
+		{backup.Snapshot{"identities": {"a": nil, "b": nil}, "credentials": {"c": nil}, "phones": {}}, "2 identities, 1 credentials"},
+	}
+	for _, tt := range tests {
+		if got := snapshotSummary(tt.snap); got != tt.want {
+			t.Errorf("snapshotSummary = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestPrintRestoreStats(t *testing.T) {
+	var buf bytes.Buffer
+	printRestoreStats(&buf, []backup.Stat{
+		{Collection: "identities", Added: 2, Skipped: 1},
+		{Collection: "credentials"},
+		{Collection: "config", Replaced: 1, Removed: 1},
+	})
+	want := "identities:  2 added, 1 kept\n" +
+		"config:      1 replaced, 1 removed\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "vault.zburn")
+
+	if err := writeFileAtomic(path, func(w io.Writer) error {
+		_, err := w.Write([]byte("archive"))
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	// a failed write leaves the previous archive and no temp file
+	err = writeFileAtomic(path, func(w io.Writer) error {
+		w.Write([]byte("partial"))
+		return errors.New("boom")
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if b, _ := os.ReadFile(path); string(b) != "archive" {
+		t.Errorf("archive = %q, want it untouched", b)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("dir has %d entries, want 1", len(entries))
+	}
+}
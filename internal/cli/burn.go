@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/zarlcorp/core/pkg/zstore"
+	"github.com/zarlcorp/zburn/internal/burn"
+	"github.com/zarlcorp/zburn/internal/config"
+	"github.com/zarlcorp/zburn/internal/credential"
+	"github.com/zarlcorp/zburn/internal/forwarding"
+	"github.com/zarlcorp/zburn/internal/identity"
+	"github.com/zarlcorp/zburn/internal/namecheap"
+	"github.com/zarlcorp/zburn/internal/twilio"
+)
+
+// BurnPlan is the JSON output of a dry run.
+type BurnPlan struct {
+	ID    string   `json:"id"`
+	Name  string   `json:"name"`
+	Email string   `json:"email"`
+	Steps []string `json:"steps"`
+}
+
+// CmdBurn runs the burn cascade for a saved identity: its credentials,
+// provisioned phone number and forwarding rule are removed before the
+// identity itself.
+func CmdBurn(ctx context.Context, args []string) {
+	asJSON := hasFlag(args, "--json")
+	dryRun := hasFlag(args, "--dry-run")
+
+	ref := firstArg(args)
+	if ref == "" {
+		fmt.Fprintln(os.Stderr, "usage: zburn burn <id|email> [--yes] [--dry-run] [--json]")
+		os.Exit(1)
+	}
+
+	dir := DataDir()
+	s, col, err := OpenStore(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+		os.Exit(1)
+	}
+	defer s.Close()
+
+	id, err := findIdentity(col, ref)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: burn: %v\n", err)
+		os.Exit(1)
+	}
+
+	req, err := burnRequest(s, col, id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: burn: %v\n", err)
+		os.Exit(1)
+	}
+
+	plan := burnPlan(req)
+
+	if dryRun {
+		if asJSON {
+			printJSON(plan)
+			return
+		}
+		printBurnPlan(os.Stdout, plan)
+		return
+	}
+
+	if !hasFlag(args, "--yes") {
+		printBurnPlan(os.Stderr, plan)
+		if !confirm(os.Stdin, os.Stderr, "this cannot be undone. continue? [y/N] ") {
+			fmt.Fprintln(os.Stderr, "aborted")
+			os.Exit(1)
+		}
+	}
+
+	result := burn.Execute(ctx, req)
+
+	if asJSON {
+		printJSON(result)
+	} else {
+		fmt.Println(result.Summary())
+	}
+
+	if result.HasErrors() {
+		os.Exit(1)
+	}
+}
+
+// burnRequest builds the cascade for id from the stored settings. Phone
+// release and forwarding removal are included only when the matching
+// service is configured; forwarding only in per-mailbox mode, since a
+// catch-all rule is shared by every identity on the domain, and only for a
+// rule zburn created, so hand-made aliases survive.
+func burnRequest(s *zstore.Store, ids *zstore.Collection[identity.Identity], id identity.Identity) (burn.Request, error) {
+	creds, err := zstore.NewCollection[credential.Credential](s, "credentials")
+	if err != nil {
+		return burn.Request{}, err
+	}
+	phones, err := zstore.NewCollection[burn.PhoneConfig](s, "phones")
+	if err != nil {
+		return burn.Request{}, err
+	}
+	cfgCol, err := zstore.NewCollection[config.Envelope](s, config.Collection)
+	if err != nil {
+		return burn.Request{}, err
+	}
+
+	req := burn.Request{
+		Identity:    id,
+		Credentials: creds,
+		Identities:  ids,
+	}
+
+	// a bought number is always part of the cascade: without Twilio it
+	// cannot be released, and the identity is kept so it is not forgotten
+	if phone, err := phones.Get(id.ID); err == nil {
+		req.Phone = &phone
+		req.Phones = phones
+		if tw := config.Load[config.Twilio](cfgCol, config.KeyTwilio); tw.Configured() {
+			req.Releaser = twilio.NewClient(tw.TwilioConfig())
+		}
+	}
+
+	nc := config.Load[config.Namecheap](cfgCol, config.KeyNamecheap)
+	created := config.Load[forwarding.Ledger](cfgCol, config.KeyForwarded)
+	if mailbox, domain, ok := forwarding.Managed(id.Email, nc.CachedDomains); ok && nc.Configured() && nc.PerMailbox() && created.Has(domain, mailbox) {
+		req.Forwarding = namecheap.NewClient(nc.NamecheapConfig())
+		req.Ledger = config.Forwarded{Col: cfgCol}
+	}
+
+	return req, nil
+}
+
+// burnPlan lists the cascade steps, ending with the identity itself unless
+// its phone number cannot be released.
+func burnPlan(req burn.Request) BurnPlan {
+	id := req.Identity
+	steps := burn.Plan(req)
+	if req.Phone == nil || req.Releaser != nil {
+		steps = append(steps, "delete identity")
+	}
+	return BurnPlan{
+		ID:    id.ID,
+		Name:  id.FirstName + " " + id.LastName,
+		Email: id.Email,
+		Steps: steps,
+	}
+}
+
+func printBurnPlan(w io.Writer, p BurnPlan) {
+	fmt.Fprintf(w, "burn %s <%s> will:\n", p.Name, p.Email)
+	for _, step := range p.Steps {
+		fmt.Fprintf(w, "  - %s\n", step)
+	}
+}
+
+// confirm prints prompt and reports whether the answer read from r is yes.
+func confirm(r io.Reader, w io.Writer, prompt string) bool {
+	fmt.Fprint(w, prompt)
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
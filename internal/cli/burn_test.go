@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/zarlcorp/core/pkg/zfilesystem"
+	"github.com/zarlcorp/core/pkg/zstore"
+	"github.com/zarlcorp/zburn/internal/burn"
+	"github.com/zarlcorp/zburn/internal/config"
+	"github.com/zarlcorp/zburn/internal/forwarding"
+	"github.com/zarlcorp/zburn/internal/identity"
+)
+
+func openTestStore(t *testing.T) (*zstore.Store, *zstore.Collection[identity.Identity]) {
+	t.Helper()
+	s, err := zstore.Open(zfilesystem.NewOSFileSystem(t.TempDir()), []byte("testpass"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	col, err := zstore.NewCollection[identity.Identity](s, "identities")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, col
+}
+
+func TestBurnRequestLocalOnly(t *testing.T) {
+	s, col := openTestStore(t)
+	id := identity.Identity{ID: "abc123", FirstName: "Jane", LastName: "Doe", Email: "jane@zburn.id"}
+
+	req, err := burnRequest(s, col, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Releaser != nil || req.Phone != nil || req.Forwarding != nil {
+		t.Error("unconfigured services should not be part of the cascade")
+	}
+
+	plan := burnPlan(req)
+	want := []string{"delete all credentials (0)", "delete identity"}
+	if strings.Join(plan.Steps, "|") != strings.Join(want, "|") {
+		t.Errorf("steps = %q, want %q", plan.Steps, want)
+	}
+	if plan.Name != "Jane Doe" {
+		t.Errorf("name = %q, want Jane Doe", plan.Name)
+	}
+}
+
+func TestBurnRequestExternal(t *testing.T) {
+	s, col := openTestStore(t)
+	id := identity.Identity{ID: "abc123", Email: "jane@example.com"}
+
+	cfgCol, err := zstore.NewCollection[config.Envelope](s, config.Collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Save(cfgCol, config.KeyTwilio, config.Twilio{AccountSID: "AC1", AuthToken: "tok"}); err != nil {
+		t.Fatal(err)
+	}
+	nc := config.Namecheap{Username: "u", APIKey: "k", CachedDomains: []string{"example.com"}, ForwardingMode: config.ForwardMailbox}
+	if err := config.Save(cfgCol, config.KeyNamecheap, nc); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Save(cfgCol, config.KeyForwarded, forwarding.Ledger{}.Add("example.com", "jane")); err != nil {
+		t.Fatal(err)
+	}
+
+	phones, err := zstore.NewCollection[burn.PhoneConfig](s, "phones")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := phones.Put(id.ID, burn.PhoneConfig{NumberSID: "PN1", PhoneNumber: "+447700900001"}); err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := burnRequest(s, col, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Phone == nil || req.Phone.NumberSID != "PN1" || req.Releaser == nil || req.Phones == nil {
+		t.Errorf("phone release not planned: %+v", req)
+	}
+	if req.Forwarding == nil || req.Ledger == nil {
+		t.Error("a rule zburn created should be planned for removal")
+	}
+
+	steps := strings.Join(burnPlan(req).Steps, "\n")
+	for _, want := range []string{"release phone number +447700900001", "remove forwarding for jane@example.com"} {
+		if !strings.Contains(steps, want) {
+			t.Errorf("plan should contain %q:\n%s", want, steps)
+		}
+	}
+}
+
+func TestBurnRequestKeepsManualRule(t *testing.T) {
+	s, col := openTestStore(t)
+	id := identity.Identity{ID: "abc123", Email: "jane@example.com"}
+
+	cfgCol, err := zstore.NewCollection[config.Envelope](s, config.Collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nc := config.Namecheap{Username: "u", APIKey: "k", CachedDomains: []string{"example.com"}, ForwardingMode: config.ForwardMailbox}
+	if err := config.Save(cfgCol, config.KeyNamecheap, nc); err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := burnRequest(s, col, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Forwarding != nil {
+		t.Error("a rule zburn did not create should be left alone")
+	}
+}
+
+func TestBurnRequestCatchAllKeepsForwarding(t *testing.T) {
+	s, col := openTestStore(t)
+	id := identity.Identity{ID: "abc123", Email: "jane@example.com"}
+
+	cfgCol, err := zstore.NewCollection[config.Envelope](s, config.Collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nc := config.Namecheap{Username: "u", APIKey: "k", CachedDomains: []string{"example.com"}}
+	if err := config.Save(cfgCol, config.KeyNamecheap, nc); err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := burnRequest(s, col, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Forwarding != nil {
+		t.Error("catch-all mode has no per-identity rule to remove")
+	}
+}
+
+func TestBurnRequestPhoneWithoutTwilio(t *testing.T) {
+	s, col := openTestStore(t)
+	id := identity.Identity{ID: "abc123", Email: "jane@zburn.id"}
+
+	phones, err := zstore.NewCollection[burn.PhoneConfig](s, "phones")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := phones.Put(id.ID, burn.PhoneConfig{NumberSID: "PN1", PhoneNumber: "+447700900001"}); err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := burnRequest(s, col, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Phone == nil || req.Releaser != nil {
+		t.Fatalf("bought number should be planned without a releaser: %+v", req)
+	}
+
+	steps := strings.Join(burnPlan(req).Steps, "\n")
+	if !strings.Contains(steps, "keep the identity") || strings.Contains(steps, "delete identity") {
+		t.Errorf("plan should keep the identity:\n%s", steps)
+	}
+}
+
+func TestPrintBurnPlan(t *testing.T) {
+	var buf bytes.Buffer
+	printBurnPlan(&buf, BurnPlan{Name: "Jane Doe", Email: "jane@zburn.id", Steps: []string{"delete identity"}})
+
+	want := "burn Jane Doe <jane@zburn.id> will:\n  - delete identity\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{" y ", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if got := confirm(strings.NewReader(tt.input), &out, "continue? "); got != tt.want {
+			t.Errorf("confirm(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+		if out.String() != "continue? " {
+			t.Errorf("prompt = %q", out.String())
+		}
+	}
+}
@@ -2,16 +2,20 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 	"syscall"
 
 	"github.com/zarlcorp/core/pkg/zfilesystem"
 	"github.com/zarlcorp/core/pkg/zstore"
+	"github.com/zarlcorp/zburn/internal/burn"
+	"github.com/zarlcorp/zburn/internal/credential"
 	"github.com/zarlcorp/zburn/internal/identity"
 	"golang.org/x/term"
 )
@@ -34,6 +38,12 @@ func ReadPassword(prompt string, w io.Writer) (string, error) {
 	if p := os.Getenv("ZBURN_PASSWORD"); p != "" {
 		return p, nil
 	}
+	return promptPassword(prompt, w)
+}
+
+// promptPassword prompts on w and reads a password from the terminal
+// without echo.
+func promptPassword(prompt string, w io.Writer) (string, error) {
 	fmt.Fprint(w, prompt)
 	b, err := term.ReadPassword(int(syscall.Stdin))
 	fmt.Fprintln(w)
@@ -109,40 +119,10 @@ func CmdEmail() {
 	fmt.Println(g.Email(first, last, ""))
 }
 
-// CmdIdentity generates and prints a complete identity.
-func CmdIdentity(args []string) {
-	asJSON := hasFlag(args, "--json")
-	save := hasFlag(args, "--save")
-
-	g := identity.New()
-	id := g.Generate("")
-
-	if asJSON {
-		printJSON(id)
-	} else {
-		printIdentity(id)
-	}
-
-	if save {
-		dir := DataDir()
-		s, col, err := OpenStore(dir)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
-			os.Exit(1)
-		}
-		defer s.Close()
-
-		if err := col.Put(id.ID, id); err != nil {
-			fmt.Fprintf(os.Stderr, "zburn: save: %v\n", err)
-			os.Exit(1)
-		}
-		fmt.Fprintln(os.Stderr, "saved")
-	}
-}
-
 // CmdList lists all saved identities.
 func CmdList(args []string) {
 	asJSON := hasFlag(args, "--json")
+	tags := flagValues(args, "--tag")
 
 	dir := DataDir()
 	s, col, err := OpenStore(dir)
@@ -157,12 +137,17 @@ func CmdList(args []string) {
 		fmt.Fprintf(os.Stderr, "zburn: list: %v\n", err)
 		os.Exit(1)
 	}
+	ids = filterByTags(ids, tags)
 
 	sort.Slice(ids, func(i, j int) bool {
 		return ids[i].CreatedAt.After(ids[j].CreatedAt)
 	})
 
 	if len(ids) == 0 {
+		if len(tags) > 0 {
+			fmt.Printf("no identities tagged %s\n", strings.Join(tags, ", "))
+			return
+		}
 		fmt.Println("no saved identities")
 		return
 	}
@@ -173,16 +158,36 @@ func CmdList(args []string) {
 	}
 
 	for _, id := range ids {
-		fmt.Printf("  %-10s %-20s %-30s %s\n",
+		line := fmt.Sprintf("  %-10s %-20s %-30s %s",
 			id.ID,
 			id.FirstName+" "+id.LastName,
 			id.Email,
 			id.CreatedAt.Format("2006-01-02"),
 		)
+		if meta := id.Meta(); meta != "" {
+			line += "  " + meta
+		}
+		fmt.Println(line)
 	}
 }
 
-// CmdForget deletes a saved identity by ID.
+// filterByTags keeps the identities carrying every tag in tags.
+func filterByTags(ids []identity.Identity, tags []string) []identity.Identity {
+	if len(tags) == 0 {
+		return ids
+	}
+	var out []identity.Identity
+	for _, id := range ids {
+		if !slices.ContainsFunc(tags, func(t string) bool { return !id.HasTag(t) }) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// CmdForget deletes a saved identity and its credentials. Unlike burn it
+// leaves external resources such as forwarding rules alone, and it refuses
+// an identity whose phone number has not been released.
 func CmdForget(id string) {
 	dir := DataDir()
 	s, col, err := OpenStore(dir)
@@ -192,20 +197,43 @@ func CmdForget(id string) {
 	}
 	defer s.Close()
 
-	if err := col.Delete(id); err != nil {
+	ident, err := col.Get(id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: forget: %v\n", err)
+		os.Exit(1)
+	}
+
+	req, err := forgetRequest(s, col, ident)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "zburn: forget: %v\n", err)
 		os.Exit(1)
 	}
-	fmt.Printf("deleted %s\n", id)
+
+	result := burn.Execute(context.Background(), req)
+	if result.HasErrors() {
+		fmt.Fprintf(os.Stderr, "zburn: forget: %s\n", result.Summary())
+		os.Exit(1)
+	}
+	fmt.Printf("deleted %s (%d credentials)\n", id, result.CredentialsCount)
 }
 
-func printIdentity(id identity.Identity) {
-	fmt.Printf("  id:       %s\n", id.ID)
-	fmt.Printf("  name:     %s %s\n", id.FirstName, id.LastName)
-	fmt.Printf("  email:    %s\n", id.Email)
-	fmt.Printf("  phone:    %s\n", id.Phone)
-	fmt.Printf("  address:  %s, %s, %s %s\n", id.Street, id.City, id.State, id.Zip)
-	fmt.Printf("  dob:      %s\n", id.DOB.Format("2006-01-02"))
+// forgetRequest builds the local-only cascade for ident. An identity with a
+// provisioned phone number is refused: forgetting it would leave the number
+// billed with nothing in zburn pointing at it.
+func forgetRequest(s *zstore.Store, col *zstore.Collection[identity.Identity], ident identity.Identity) (burn.Request, error) {
+	phones, err := zstore.NewCollection[burn.PhoneConfig](s, "phones")
+	if err != nil {
+		return burn.Request{}, err
+	}
+	if phone, err := phones.Get(ident.ID); err == nil {
+		return burn.Request{}, fmt.Errorf("%s still has phone number %s; use zburn burn to release it", ident.ID, phone.PhoneNumber)
+	}
+
+	creds, err := zstore.NewCollection[credential.Credential](s, "credentials")
+	if err != nil {
+		return burn.Request{}, err
+	}
+	return burn.Request{Identity: ident, Credentials: creds, Identities: col}, nil
 }
 
 func printJSON(v any) {
@@ -225,3 +253,52 @@ func hasFlag(args []string, flag string) bool {
 	}
 	return false
 }
+
+// flagValue returns the value of a flag given as "--flag value" or
+// "--flag=value".
+func flagValue(args []string, flag string) (string, bool) {
+	for i, a := range args {
+		if v, ok := strings.CutPrefix(a, flag+"="); ok {
+			return v, true
+		}
+		if strings.EqualFold(a, flag) && i+1 < len(args) {
+			return args[i+1], true
+		}
+	}
+	return "", false
+}
+
+// flagValues returns every value given for a repeatable flag.
+func flagValues(args []string, flag string) []string {
+	var out []string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if v, ok := strings.CutPrefix(a, flag+"="); ok {
+			out = append(out, v)
+			continue
+		}
+		if strings.EqualFold(a, flag) && i+1 < len(args) {
+			out = append(out, args[i+1])
+			i++
+		}
+	}
+	return out
+}
+
+// firstArg returns the first positional argument, skipping flags and the
+// values of the given value-taking flags.
+func firstArg(args []string, valueFlags ...string) string {
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if !strings.HasPrefix(a, "--") {
+			return a
+		}
+		for _, f := range valueFlags {
+			if strings.EqualFold(a, f) {
+				i++
+				break
+			}
+		}
+	}
+	return ""
+}
@@ -4,6 +4,10 @@ import (
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/zarlcorp/core/pkg/zstore"
+	"github.com/zarlcorp/zburn/internal/burn"
+	"github.com/zarlcorp/zburn/internal/identity"
 )
 
 func TestDataDir(t *testing.T) {
@@ -66,6 +70,50 @@ func TestHasFlag(t *testing.T) {
 	}
 }
 
+func TestFlagValue(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+		ok   bool
+	}{
+		{"separate", []string{"abc", "--timeout", "30s"}, "30s", true},
+		{"equals", []string{"--timeout=1m", "abc"}, "1m", true},
+		{"missing value", []string{"--timeout"}, "", false},
+		{"absent", []string{"abc"}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := flagValue(tt.args, "--timeout")
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("flagValue() = %q, %v, want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestFirstArg(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"leading", []string{"abc", "--json"}, "abc"},
+		{"after value flag", []string{"--timeout", "30s", "abc"}, "abc"},
+		{"after bool flag", []string{"--json", "abc"}, "abc"},
+		{"none", []string{"--json"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := firstArg(tt.args, "--timeout"); got != tt.want {
+				t.Errorf("firstArg() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestIsFirstRun(t *testing.T) {
 	dir := t.TempDir()
 	if !IsFirstRun(dir) {
@@ -77,3 +125,62 @@ func TestIsFirstRun(t *testing.T) {
 		t.Error("expected not first run after salt exists")
 	}
 }
+
+func TestFilterByTags(t *testing.T) {
+	ids := []identity.Identity{
+		{ID: "a", Tags: []string{"acme", "trial"}},
+		{ID: "b", Tags: []string{"trial"}},
+		{ID: "c"},
+	}
+
+	tests := []struct {
+		tags []string
+		want string
+	}{
+		{nil, "abc"},
+		{[]string{"trial"}, "ab"},
+		{[]string{"Trial", "#acme"}, "a"},
+		{[]string{"beta"}, ""},
+	}
+	for _, tt := range tests {
+		var got string
+		for _, id := range filterByTags(ids, tt.tags) {
+			got += id.ID
+		}
+		if got != tt.want {
+			t.Errorf("filterByTags(%v) = %q, want %q", tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestForgetRequest(t *testing.T) {
+	s, col := openTestStore(t)
+	id := identity.Identity{ID: "abc123", Email: "jane@zburn.id"}
+	if err := col.Put(id.ID, id); err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := forgetRequest(s, col, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Phone != nil || req.Forwarding != nil {
+		t.Errorf("forget should stay local: %+v", req)
+	}
+
+	phones, err := zstore.NewCollection[burn.PhoneConfig](s, "phones")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := phones.Put(id.ID, burn.PhoneConfig{NumberSID: "PN1", PhoneNumber: "+447700900001"}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = forgetRequest(s, col, id)
+	if err == nil || !strings.Contains(err.Error(), "zburn burn") {
+		t.Errorf("err = %v, want a pointer to zburn burn", err)
+	}
+	if _, err := col.Get(id.ID); err != nil {
+		t.Error("identity should be kept")
+	}
+}
@@ -0,0 +1,422 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/zarlcorp/core/pkg/zstore"
+	"github.com/zarlcorp/zburn/internal/burn"
+	"github.com/zarlcorp/zburn/internal/codes"
+	"github.com/zarlcorp/zburn/internal/config"
+	"github.com/zarlcorp/zburn/internal/gmail"
+	"github.com/zarlcorp/zburn/internal/identity"
+	"github.com/zarlcorp/zburn/internal/twilio"
+)
+
+const (
+	defaultCodesTimeout = 2 * time.Minute
+	codesPollInterval   = 5 * time.Second
+	codesFetchLimit     = 10
+
+	// codesMaxFailures is how many polls in a row may fail with a transient
+	// error before the command gives up.
+	codesMaxFailures = 3
+)
+
+// errCodesTimeout is returned when no code arrives before the deadline.
+var errCodesTimeout = errors.New("timed out waiting for a code")
+
+// CodeResult is the verification code printed by the codes command.
+type CodeResult struct {
+	Code     string    `json:"code"`
+	Type     string    `json:"type"`
+	Source   string    `json:"source"` // "email" or "sms"
+	From     string    `json:"from"`
+	Subject  string    `json:"subject,omitempty"`
+	Received time.Time `json:"received"`
+}
+
+// mailSource abstracts the Gmail calls used while polling.
+type mailSource interface {
+	ListMessages(ctx context.Context, query string, maxResults int) ([]gmail.Message, error)
+	GetMessage(ctx context.Context, messageID string) (*gmail.Message, error)
+}
+
+// smsSource abstracts the Twilio call used while polling.
+type smsSource interface {
+	ListMessages(ctx context.Context, to string, limit int) ([]twilio.SMSMessage, error)
+}
+
+// codeWatcher polls an identity's inboxes for messages that were not there
+// when it started.
+type codeWatcher struct {
+	mail  mailSource // nil when gmail is not connected
+	email string
+	// renewMail returns a Gmail client whose access token is current; force
+	// refreshes it even before expiry. nil when the token cannot be renewed.
+	renewMail func(ctx context.Context, force bool) (mailSource, error)
+	sms       smsSource // nil when no phone is assigned
+	number    string
+	seen      map[string]bool
+}
+
+// CmdCodes waits for the next verification code sent to an identity and
+// prints it.
+func CmdCodes(ctx context.Context, args []string) {
+	asJSON := hasFlag(args, "--json")
+
+	timeout := defaultCodesTimeout
+	if v, ok := flagValue(args, "--timeout"); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			fmt.Fprintf(os.Stderr, "zburn: invalid --timeout %q\n", v)
+			os.Exit(1)
+		}
+		timeout = d
+	}
+
+	ref := firstArg(args, "--timeout")
+	if ref == "" {
+		fmt.Fprintln(os.Stderr, "usage: zburn codes <id|email> [--json] [--timeout 2m]")
+		os.Exit(1)
+	}
+
+	dir := DataDir()
+	s, col, err := OpenStore(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+		os.Exit(1)
+	}
+	defer s.Close()
+
+	id, err := findIdentity(col, ref)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: codes: %v\n", err)
+		os.Exit(1)
+	}
+
+	w, err := newCodeWatcher(ctx, s, id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: codes: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := w.prime(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: codes: %v\n", err)
+		os.Exit(1)
+	}
+
+	res, err := w.wait(ctx, codesPollInterval)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: codes: %v\n", err)
+		os.Exit(1)
+	}
+
+	if asJSON {
+		printJSON(res)
+		return
+	}
+	fmt.Println(res.Code)
+}
+
+// newCodeWatcher connects the sources configured for an identity: Gmail for
+// its email and Twilio for its provisioned number.
+func newCodeWatcher(ctx context.Context, s *zstore.Store, id identity.Identity) (*codeWatcher, error) {
+	cfgCol, err := zstore.NewCollection[config.Envelope](s, config.Collection)
+	if err != nil {
+		return nil, err
+	}
+	phones, err := zstore.NewCollection[burn.PhoneConfig](s, "phones")
+	if err != nil {
+		return nil, err
+	}
+
+	w := &codeWatcher{email: id.Email, seen: make(map[string]bool)}
+
+	gm := config.Load[config.Gmail](cfgCol, config.KeyGmail)
+	if gm.Configured() {
+		w.renewMail = gmailRenewer(cfgCol, gm)
+		if w.mail, err = w.renewMail(ctx, false); err != nil {
+			return nil, fmt.Errorf("gmail: %w", err)
+		}
+	}
+
+	tw := config.Load[config.Twilio](cfgCol, config.KeyTwilio)
+	if phone, err := phones.Get(id.ID); err == nil && tw.Configured() {
+		w.sms = twilio.NewClient(tw.TwilioConfig())
+		w.number = phone.PhoneNumber
+	}
+
+	if w.mail == nil && w.sms == nil {
+		return nil, fmt.Errorf("gmail not connected and no phone assigned")
+	}
+
+	return w, nil
+}
+
+// gmailRenewer returns a function that hands out Gmail clients for gm. The
+// access token is refreshed when it nears expiry, or when force is set
+// because the API rejected it, and a refreshed token is saved.
+func gmailRenewer(cfgCol *zstore.Collection[config.Envelope], gm config.Gmail) func(context.Context, bool) (mailSource, error) {
+	return func(ctx context.Context, force bool) (mailSource, error) {
+		if force && gm.Token != nil {
+			stale := *gm.Token
+			stale.AccessToken = ""
+			gm.Token = &stale
+		}
+
+		tok, refreshed, err := gm.AccessToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if refreshed {
+			gm.Token = tok
+			// a failed save only costs a refresh next time
+			_ = config.Save(cfgCol, config.KeyGmail, gm)
+		}
+		return gmail.NewClient(tok.AccessToken), nil
+	}
+}
+
+// prime records the messages already present so only new ones are considered.
+func (w *codeWatcher) prime(ctx context.Context) error {
+	if w.mail != nil {
+		refs, err := w.mail.ListMessages(ctx, "to:"+w.email, codesFetchLimit)
+		if err != nil {
+			return fmt.Errorf("gmail: %w", err)
+		}
+		for _, r := range refs {
+			w.seen["email:"+r.ID] = true
+		}
+	}
+
+	if w.sms != nil {
+		msgs, err := w.sms.ListMessages(ctx, w.number, codesFetchLimit)
+		if err != nil {
+			return fmt.Errorf("twilio: %w", err)
+		}
+		for _, m := range msgs {
+			w.seen["sms:"+m.SID] = true
+		}
+	}
+
+	return nil
+}
+
+// wait polls until a new message with a code arrives or ctx is done.
+// Transient fetch errors are retried on the next tick. An error that
+// retrying will not fix, or codesMaxFailures transient ones in a row, ends
+// the wait early.
+func (w *codeWatcher) wait(ctx context.Context, interval time.Duration) (CodeResult, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		res, ok, err := w.poll(ctx)
+		switch {
+		case ok:
+			return res, nil
+		case err != nil && ctx.Err() == nil:
+			failures++
+			if !transient(err) || failures >= codesMaxFailures {
+				return CodeResult{}, err
+			}
+			fmt.Fprintf(os.Stderr, "zburn: codes: %v (retrying)\n", err)
+		default:
+			failures = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return CodeResult{}, errCodesTimeout
+			}
+			return CodeResult{}, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll checks each source once and returns the first code from an unseen
+// message. Messages without a code are marked seen and skipped. A failing
+// source does not stop the other from being checked; its error is returned
+// when no code was found.
+func (w *codeWatcher) poll(ctx context.Context) (CodeResult, bool, error) {
+	var errs []error
+
+	if w.sms != nil {
+		res, ok, err := w.pollSMS(ctx)
+		if ok {
+			return res, true, nil
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("twilio: %w", err))
+		}
+	}
+
+	if w.mail != nil {
+		res, ok, err := w.pollMail(ctx)
+		if ok {
+			return res, true, nil
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("gmail: %w", err))
+		}
+	}
+
+	return CodeResult{}, false, errors.Join(errs...)
+}
+
+func (w *codeWatcher) pollSMS(ctx context.Context) (CodeResult, bool, error) {
+	msgs, err := w.sms.ListMessages(ctx, w.number, codesFetchLimit)
+	if err != nil {
+		return CodeResult{}, false, err
+	}
+
+	for _, m := range msgs {
+		key := "sms:" + m.SID
+		if w.seen[key] {
+			continue
+		}
+		w.seen[key] = true
+		if found := codes.Extract(m.Body); len(found) > 0 {
+			return CodeResult{
+				Code:     found[0].Value,
+				Type:     found[0].Type,
+				Source:   "sms",
+				From:     m.From,
+				Received: m.DateSent,
+			}, true, nil
+		}
+	}
+	return CodeResult{}, false, nil
+}
+
+// pollMail checks the inbox, renewing the access token before it expires
+// and once more if the API rejects it.
+func (w *codeWatcher) pollMail(ctx context.Context) (CodeResult, bool, error) {
+	if w.renewMail == nil {
+		return w.checkMail(ctx)
+	}
+
+	mail, err := w.renewMail(ctx, false)
+	if err != nil {
+		return CodeResult{}, false, err
+	}
+	w.mail = mail
+
+	res, ok, err := w.checkMail(ctx)
+	if !hasStatus(err, http.StatusUnauthorized) {
+		return res, ok, err
+	}
+
+	if w.mail, err = w.renewMail(ctx, true); err != nil {
+		return CodeResult{}, false, err
+	}
+	return w.checkMail(ctx)
+}
+
+func (w *codeWatcher) checkMail(ctx context.Context) (CodeResult, bool, error) {
+	refs, err := w.mail.ListMessages(ctx, "to:"+w.email, codesFetchLimit)
+	if err != nil {
+		return CodeResult{}, false, err
+	}
+
+	for _, r := range refs {
+		key := "email:" + r.ID
+		if w.seen[key] {
+			continue
+		}
+		full, err := w.mail.GetMessage(ctx, r.ID)
+		if hasStatus(err, http.StatusNotFound) {
+			// deleted since it was listed
+			w.seen[key] = true
+			continue
+		}
+		if err != nil {
+			// leave it unseen so it is retried on the next tick
+			return CodeResult{}, false, err
+		}
+		w.seen[key] = true
+		if found := codes.Extract(full.Subject + "\n" + full.Body); len(found) > 0 {
+			return CodeResult{
+				Code:     found[0].Value,
+				Type:     found[0].Type,
+				Source:   "email",
+				From:     full.From,
+				Subject:  full.Subject,
+				Received: full.Date,
+			}, true, nil
+		}
+	}
+	return CodeResult{}, false, nil
+}
+
+// hasStatus reports whether err is a Gmail or Twilio API error with the
+// given HTTP status.
+func hasStatus(err error, status int) bool {
+	var ge *gmail.StatusError
+	if errors.As(err, &ge) {
+		return ge.StatusCode == status
+	}
+	var te *twilio.Error
+	if errors.As(err, &te) {
+		return te.StatusCode == status
+	}
+	return false
+}
+
+// transient reports whether polling again may succeed after err: network
+// failures, rate limits and server errors are transient, other API
+// rejections are not. A joined error is transient only if all its parts are.
+func transient(err error) bool {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			if !transient(e) {
+				return false
+			}
+		}
+		return true
+	}
+
+	status := 0
+	var ge *gmail.StatusError
+	var te *twilio.Error
+	switch {
+	case errors.As(err, &ge):
+		status = ge.StatusCode
+	case errors.As(err, &te):
+		status = te.StatusCode
+	default:
+		return true
+	}
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// findIdentity looks up a saved identity by ID or email address.
+func findIdentity(col *zstore.Collection[identity.Identity], ref string) (identity.Identity, error) {
+	if id, err := col.Get(ref); err == nil {
+		return id, nil
+	}
+
+	ids, err := col.List()
+	if err != nil {
+		return identity.Identity{}, fmt.Errorf("list: %w", err)
+	}
+	for _, id := range ids {
+		if strings.EqualFold(id.Email, ref) {
+			return id, nil
+		}
+	}
+
+	return identity.Identity{}, fmt.Errorf("no identity matches %q", ref)
+}
@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/zarlcorp/core/pkg/zfilesystem"
+	"github.com/zarlcorp/core/pkg/zstore"
+	"github.com/zarlcorp/zburn/internal/gmail"
+	"github.com/zarlcorp/zburn/internal/identity"
+	"github.com/zarlcorp/zburn/internal/twilio"
+)
+
+// fakeMail returns the messages in its list, newest first.
+type fakeMail struct {
+	messages []gmail.Message
+	listErr  error
+}
+
+func (f *fakeMail) ListMessages(_ context.Context, _ string, _ int) ([]gmail.Message, error) {
+	if f.listErr != nil {
+		return nil, f.listErr
+	}
+	refs := make([]gmail.Message, len(f.messages))
+	for i, m := range f.messages {
+		refs[i] = gmail.Message{ID: m.ID}
+	}
+	return refs, nil
+}
+
+func (f *fakeMail) GetMessage(_ context.Context, id string) (*gmail.Message, error) {
+	for _, m := range f.messages {
+		if m.ID == id {
+			return &m, nil
+		}
+	}
+	return nil, fmt.Errorf("not found")
+}
+
+type fakeSMS struct {
+	messages []twilio.SMSMessage
+}
+
+func (f *fakeSMS) ListMessages(_ context.Context, _ string, _ int) ([]twilio.SMSMessage, error) {
+	return f.messages, nil
+}
+
+func TestCodeWatcherIgnoresExistingMessages(t *testing.T) {
+	mail := &fakeMail{messages: []gmail.Message{
+		{ID: "old", Subject: "Your code", Body: "Your verification code is 111111"},
+	}}
+	w := &codeWatcher{mail: mail, email: "jane@zburn.id", seen: make(map[string]bool)}
+
+	if err := w.prime(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := w.poll(context.Background()); ok || err != nil {
+		t.Fatalf("messages present at start should be ignored (err %v)", err)
+	}
+
+	mail.messages = append([]gmail.Message{
+		{ID: "new", From: "Acme <no-reply@acme.com>", Subject: "Verify", Body: "Your verification code is 482913"},
+	}, mail.messages...)
+
+	res, ok, err := w.poll(context.Background())
+	if !ok || err != nil {
+		t.Fatalf("new message should yield a code (err %v)", err)
+	}
+	if res.Code != "482913" || res.Source != "email" || res.Subject != "Verify" {
+		t.Errorf("result = %+v", res)
+	}
+}
+
+func TestCodeWatcherSkipsMessagesWithoutCode(t *testing.T) {
+	mail := &fakeMail{}
+	w := &codeWatcher{mail: mail, email: "jane@zburn.id", seen: make(map[string]bool)}
+
+	mail.messages = []gmail.Message{{ID: "welcome", Subject: "Welcome", Body: "thanks for signing up"}}
+	if _, ok, _ := w.poll(context.Background()); ok {
+		t.Fatal("message without code should not match")
+	}
+	if !w.seen["email:welcome"] {
+		t.Error("message without code should be marked seen")
+	}
+}
+
+func TestCodeWatcherSMS(t *testing.T) {
+	sms := &fakeSMS{}
+	w := &codeWatcher{sms: sms, number: "+447700900123", seen: make(map[string]bool)}
+
+	if err := w.prime(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	sms.messages = []twilio.SMSMessage{{SID: "SM1", From: "+15550001", Body: "Your Acme code is 739104"}}
+	res, ok, err := w.poll(context.Background())
+	if !ok || err != nil {
+		t.Fatalf("new sms should yield a code (err %v)", err)
+	}
+	if res.Code != "739104" || res.Source != "sms" || res.From != "+15550001" {
+		t.Errorf("result = %+v", res)
+	}
+}
+
+func TestCodeWatcherWaitTimeout(t *testing.T) {
+	w := &codeWatcher{mail: &fakeMail{}, email: "jane@zburn.id", seen: make(map[string]bool)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	_, err := w.wait(ctx, 5*time.Millisecond)
+	if !errors.Is(err, errCodesTimeout) {
+		t.Errorf("err = %v, want errCodesTimeout", err)
+	}
+}
+
+func TestCodeWatcherRenewsRejectedToken(t *testing.T) {
+	rejected := &fakeMail{listErr: &gmail.StatusError{StatusCode: 401}}
+	fresh := &fakeMail{messages: []gmail.Message{{ID: "new", Body: "Your verification code is 482913"}}}
+
+	var forced int
+	w := &codeWatcher{
+		mail:  rejected,
+		email: "jane@zburn.id",
+		renewMail: func(_ context.Context, force bool) (mailSource, error) {
+			if force {
+				forced++
+				return fresh, nil
+			}
+			return rejected, nil
+		},
+		seen: make(map[string]bool),
+	}
+
+	res, ok, err := w.poll(context.Background())
+	if !ok || err != nil {
+		t.Fatalf("poll after renewal = %v, %v", ok, err)
+	}
+	if forced != 1 || res.Code != "482913" {
+		t.Errorf("forced refreshes = %d, code = %q", forced, res.Code)
+	}
+}
+
+func TestCodeWatcherWaitStopsOnError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"permanent", &gmail.StatusError{StatusCode: 403}},
+		{"repeated transient", fmt.Errorf("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &codeWatcher{mail: &fakeMail{listErr: tt.err}, email: "jane@zburn.id", seen: make(map[string]bool)}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+
+			_, err := w.wait(ctx, time.Millisecond)
+			if err == nil || errors.Is(err, errCodesTimeout) {
+				t.Fatalf("err = %v, want the fetch error", err)
+			}
+			if ctx.Err() != nil {
+				t.Error("wait should give up before the timeout")
+			}
+		})
+	}
+}
+
+func TestTransient(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{fmt.Errorf("dial tcp: connection refused"), true},
+		{fmt.Errorf("gmail: %w", &gmail.StatusError{StatusCode: 503}), true},
+		{&twilio.Error{StatusCode: 429}, true},
+		{fmt.Errorf("gmail: %w", &gmail.StatusError{StatusCode: 401}), false},
+		{&twilio.Error{StatusCode: 404}, false},
+		{errors.Join(fmt.Errorf("timeout"), &twilio.Error{StatusCode: 403}), false},
+	}
+
+	for _, tt := range tests {
+		if got := transient(tt.err); got != tt.want {
+			t.Errorf("transient(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestCodeWatcherPrimeError(t *testing.T) {
+	w := &codeWatcher{mail: &fakeMail{listErr: fmt.Errorf("status 401")}, email: "jane@zburn.id", seen: make(map[string]bool)}
+	if err := w.prime(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestFindIdentity(t *testing.T) {
+	s, err := zstore.Open(zfilesystem.NewOSFileSystem(t.TempDir()), []byte("testpass"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	col, err := zstore.NewCollection[identity.Identity](s, "identities")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := col.Put("abc123", identity.Identity{ID: "abc123", Email: "jane@zburn.id"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ref := range []string{"abc123", "jane@zburn.id", "JANE@zburn.id"} {
+		id, err := findIdentity(col, ref)
+		if err != nil {
+			t.Errorf("%s: %v", ref, err)
+			continue
+		}
+		if id.ID != "abc123" {
+			t.Errorf("%s: id = %q, want abc123", ref, id.ID)
+		}
+	}
+
+	if _, err := findIdentity(col, "nobody@zburn.id"); err == nil {
+		t.Error("expected error for unknown identity")
+	}
+}
@@ -0,0 +1,415 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zarlcorp/core/pkg/zstore"
+	"github.com/zarlcorp/zburn/internal/credential"
+	"github.com/zarlcorp/zburn/internal/identity"
+	"github.com/zarlcorp/zburn/internal/otp"
+)
+
+// credValueFlags are the cred flags that take a value.
+var credValueFlags = append([]string{"--identity", "--label", "--url", "--username", "--totp", "--notes"}, policyValueFlags...)
+
+const credUsage = `usage: zburn cred <command> [flags]
+
+commands:
+  add   --identity <id|email> --label <label> [flags]
+  list  [--identity <id|email>] [--json]
+  show  <id> [--json]
+  edit  <id> [flags]
+  rm    <id>
+
+flags for add and edit:
+  --label <label>        site or service name
+  --url <url>            login URL
+  --username <name>      login name (add defaults to the identity's email)
+  --totp <secret|uri>    base32 TOTP secret or otpauth:// URI
+  --notes <text>         free-form notes
+  --generate-password    generate a random password, using the preset saved
+                         for the --url (or --label) site and any policy flags
+  --password-stdin       read the password from the first line of stdin
+  --json                 output as JSON
+
+` + policyFlagUsage
+
+// CmdCred dispatches the credential subcommands.
+func CmdCred(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, credUsage)
+		os.Exit(1)
+	}
+
+	sub, rest := args[0], args[1:]
+	switch sub {
+	case "add":
+		cmdCredAdd(rest)
+	case "list", "ls":
+		cmdCredList(rest)
+	case "show":
+		cmdCredShow(rest)
+	case "edit":
+		cmdCredEdit(rest)
+	case "rm":
+		cmdCredRemove(rest)
+	default:
+		fmt.Fprintf(os.Stderr, "zburn: unknown cred command %q\n\n%s\n", sub, credUsage)
+		os.Exit(1)
+	}
+}
+
+// credVault holds the collections the cred commands work on.
+type credVault struct {
+	store       *zstore.Store
+	identities  *zstore.Collection[identity.Identity]
+	credentials *zstore.Collection[credential.Credential]
+}
+
+func openCredVault() (*credVault, error) {
+	s, ids, err := OpenStore(DataDir())
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := zstore.NewCollection[credential.Credential](s, "credentials")
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	return &credVault{store: s, identities: ids, credentials: creds}, nil
+}
+
+func cmdCredAdd(args []string) {
+	ref, ok := flagValue(args, "--identity")
+	if !ok || ref == "" {
+		fmt.Fprintln(os.Stderr, "zburn: cred add: --identity is required")
+		os.Exit(1)
+	}
+	label, _ := flagValue(args, "--label")
+	if strings.TrimSpace(label) == "" {
+		fmt.Fprintln(os.Stderr, "zburn: cred add: --label is required")
+		os.Exit(1)
+	}
+
+	// read stdin before the store prompt so piped input is not consumed twice
+	password, err := credPassword(args, os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: cred add: %v\n", err)
+		os.Exit(1)
+	}
+
+	v, err := openCredVault()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+		os.Exit(1)
+	}
+	defer v.store.Close()
+
+	id, err := findIdentity(v.identities, ref)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: cred add: %v\n", err)
+		os.Exit(1)
+	}
+
+	now := time.Now().UTC()
+	c := credential.Credential{
+		ID:         credential.NewID(),
+		IdentityID: id.ID,
+		Username:   id.Email,
+		Password:   password,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := applyCredFlags(&c, args); err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: cred add: %v\n", err)
+		os.Exit(1)
+	}
+	if hasFlag(args, "--generate-password") {
+		if c.Password, err = generateCredPassword(v.store, args, c); err != nil {
+			fmt.Fprintf(os.Stderr, "zburn: cred add: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := v.credentials.Put(c.ID, c); err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: cred add: %v\n", err)
+		os.Exit(1)
+	}
+
+	if hasFlag(args, "--json") {
+		printJSON(c)
+		return
+	}
+	printCredential(os.Stdout, c)
+}
+
+func cmdCredList(args []string) {
+	v, err := openCredVault()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+		os.Exit(1)
+	}
+	defer v.store.Close()
+
+	identityID := ""
+	if ref, ok := flagValue(args, "--identity"); ok {
+		id, err := findIdentity(v.identities, ref)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "zburn: cred list: %v\n", err)
+			os.Exit(1)
+		}
+		identityID = id.ID
+	}
+
+	all, err := v.credentials.List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: cred list: %v\n", err)
+		os.Exit(1)
+	}
+	creds := filterCredentials(all, identityID)
+
+	if hasFlag(args, "--json") {
+		printJSON(redactCredentials(creds))
+		return
+	}
+
+	if len(creds) == 0 {
+		fmt.Println("no saved credentials")
+		return
+	}
+
+	for _, c := range creds {
+		fmt.Printf("  %-10s %-10s %-24s %s\n", c.ID, c.IdentityID, truncateField(c.Label, 24), c.Username)
+	}
+}
+
+func cmdCredShow(args []string) {
+	credID := firstArg(args, credValueFlags...)
+	if credID == "" {
+		fmt.Fprintln(os.Stderr, "usage: zburn cred show <id> [--json]")
+		os.Exit(1)
+	}
+
+	v, err := openCredVault()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+		os.Exit(1)
+	}
+	defer v.store.Close()
+
+	c, err := v.credentials.Get(credID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: cred show: %v\n", err)
+		os.Exit(1)
+	}
+
+	if hasFlag(args, "--json") {
+		printJSON(c)
+		return
+	}
+	printCredential(os.Stdout, c)
+}
+
+func cmdCredEdit(args []string) {
+	credID := firstArg(args, credValueFlags...)
+	if credID == "" {
+		fmt.Fprintln(os.Stderr, "usage: zburn cred edit <id> [flags]")
+		os.Exit(1)
+	}
+
+	password, err := credPassword(args, os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: cred edit: %v\n", err)
+		os.Exit(1)
+	}
+
+	v, err := openCredVault()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+		os.Exit(1)
+	}
+	defer v.store.Close()
+
+	c, err := v.credentials.Get(credID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: cred edit: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := applyCredFlags(&c, args); err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: cred edit: %v\n", err)
+		os.Exit(1)
+	}
+	if hasFlag(args, "--password-stdin") {
+		c.Password = password
+	}
+	if hasFlag(args, "--generate-password") {
+		if c.Password, err = generateCredPassword(v.store, args, c); err != nil {
+			fmt.Fprintf(os.Stderr, "zburn: cred edit: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	c.UpdatedAt = time.Now().UTC()
+
+	if err := v.credentials.Put(c.ID, c); err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: cred edit: %v\n", err)
+		os.Exit(1)
+	}
+
+	if hasFlag(args, "--json") {
+		printJSON(c)
+		return
+	}
+	printCredential(os.Stdout, c)
+}
+
+func cmdCredRemove(args []string) {
+	credID := firstArg(args)
+	if credID == "" {
+		fmt.Fprintln(os.Stderr, "usage: zburn cred rm <id>")
+		os.Exit(1)
+	}
+
+	v, err := openCredVault()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+		os.Exit(1)
+	}
+	defer v.store.Close()
+
+	if err := v.credentials.Delete(credID); err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: cred rm: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("deleted %s\n", credID)
+}
+
+// credPassword returns the first line of stdin when --password-stdin is
+// given, or "". Generated passwords come from generateCredPassword once the
+// store is open, so a saved site preset can apply.
+func credPassword(args []string, stdin io.Reader) (string, error) {
+	gen := hasFlag(args, "--generate-password")
+	fromStdin := hasFlag(args, "--password-stdin")
+
+	switch {
+	case gen && fromStdin:
+		return "", fmt.Errorf("--generate-password and --password-stdin are mutually exclusive")
+	case fromStdin:
+		line, err := bufio.NewReader(stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", fmt.Errorf("read password: %w", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			return "", fmt.Errorf("empty password on stdin")
+		}
+		return line, nil
+	}
+	return "", nil
+}
+
+// generateCredPassword generates a password for c following the preset
+// saved for its site and the policy flags in args.
+func generateCredPassword(s *zstore.Store, args []string, c credential.Credential) (string, error) {
+	site := c.URL
+	if site == "" {
+		site = c.Label
+	}
+	policy, err := resolvePolicy(s, args, site)
+	if err != nil {
+		return "", err
+	}
+	return identity.NewPassword(policy), nil
+}
+
+// applyCredFlags copies the value flags present in args onto c.
+func applyCredFlags(c *credential.Credential, args []string) error {
+	if v, ok := flagValue(args, "--label"); ok {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			return fmt.Errorf("label cannot be empty")
+		}
+		c.Label = v
+	}
+	if v, ok := flagValue(args, "--url"); ok {
+		c.URL = strings.TrimSpace(v)
+	}
+	if v, ok := flagValue(args, "--username"); ok {
+		c.Username = strings.TrimSpace(v)
+	}
+	if v, ok := flagValue(args, "--totp"); ok {
+		if err := c.SetTOTP(v); err != nil {
+			return err
+		}
+	}
+	if v, ok := flagValue(args, "--notes"); ok {
+		c.Notes = strings.TrimSpace(v)
+	}
+	return nil
+}
+
+// filterCredentials returns the credentials for identityID (all when empty),
+// sorted by label.
+func filterCredentials(all []credential.Credential, identityID string) []credential.Credential {
+	var out []credential.Credential
+	for _, c := range all {
+		if identityID == "" || c.IdentityID == identityID {
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return strings.ToLower(out[i].Label) < strings.ToLower(out[j].Label)
+	})
+	return out
+}
+
+// redactCredentials blanks secrets so listings never print them.
+func redactCredentials(creds []credential.Credential) []credential.Credential {
+	out := make([]credential.Credential, len(creds))
+	for i, c := range creds {
+		c.Password = ""
+		c.TOTPSecret = ""
+		out[i] = c
+	}
+	return out
+}
+
+func printCredential(w io.Writer, c credential.Credential) {
+	fmt.Fprintf(w, "  id:        %s\n", c.ID)
+	fmt.Fprintf(w, "  identity:  %s\n", c.IdentityID)
+	fmt.Fprintf(w, "  label:     %s\n", c.Label)
+	if c.URL != "" {
+		fmt.Fprintf(w, "  url:       %s\n", c.URL)
+	}
+	fmt.Fprintf(w, "  username:  %s\n", c.Username)
+	fmt.Fprintf(w, "  password:  %s\n", c.Password)
+	if c.TOTPSecret != "" {
+		fmt.Fprintf(w, "  totp:      %s\n", c.TOTPSecret)
+		if c.TOTPIssuer != "" {
+			fmt.Fprintf(w, "  issuer:    %s\n", c.TOTPIssuer)
+		}
+		if p := c.TOTP().Normalized(); p.Algorithm != otp.DefaultAlgorithm || p.Digits != otp.DefaultDigits || p.Period != otp.DefaultPeriod {
+			fmt.Fprintf(w, "  params:    %s, %d digits, %ds\n", p.Algorithm, p.Digits, p.Period)
+		}
+	}
+	if c.Notes != "" {
+		fmt.Fprintf(w, "  notes:     %s\n", c.Notes)
+	}
+}
+
+// truncateField shortens s to n runes, marking the cut with an ellipsis.
+func truncateField(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/zarlcorp/zburn/internal/credential"
+)
+
+func TestCredPassword(t *testing.T) {
+	// generated passwords wait for the store so a site preset can apply
+	pw, err := credPassword([]string{"--generate-password"}, nil)
+	if err != nil || pw != "" {
+		t.Errorf("generate password = %q, %v, want empty", pw, err)
+	}
+
+	pw, err = credPassword([]string{"--password-stdin"}, strings.NewReader("hunter2\nignored\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pw != "hunter2" {
+		t.Errorf("stdin password = %q, want %q", pw, "hunter2")
+	}
+
+	pw, err = credPassword([]string{"--password-stdin"}, strings.NewReader("no-newline"))
+	if err != nil || pw != "no-newline" {
+		t.Errorf("stdin password = %q, %v, want %q", pw, err, "no-newline")
+	}
+
+	if _, err := credPassword([]string{"--password-stdin"}, strings.NewReader("")); err == nil {
+		t.Error("empty stdin should be an error")
+	}
+
+	if _, err := credPassword([]string{"--generate-password", "--password-stdin"}, nil); err == nil {
+		t.Error("both password flags should be an error")
+	}
+
+	if pw, _ := credPassword(nil, nil); pw != "" {
+		t.Errorf("no flags password = %q, want empty", pw)
+	}
+}
+
+func TestApplyCredFlags(t *testing.T) {
+	c := credential.Credential{Label: "old", Username: "jane@zburn.id", Notes: "keep"}
+
+	args := []string{"abc", "--label", "GitHub", "--url", "https://github.com", "--totp", "JBSWY3DPEHPK3PXP"}
+	if err := applyCredFlags(&c, args); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Label != "GitHub" || c.URL != "https://github.com" || c.TOTPSecret != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("credential = %+v", c)
+	}
+	if c.Username != "jane@zburn.id" || c.Notes != "keep" {
+		t.Error("flags not given should leave fields unchanged")
+	}
+
+	if err := applyCredFlags(&c, []string{"--totp", "!!!"}); err == nil {
+		t.Error("invalid totp should be an error")
+	}
+	if err := applyCredFlags(&c, []string{"--label", " "}); err == nil {
+		t.Error("empty label should be an error")
+	}
+}
+
+func TestApplyCredFlagsTOTPURI(t *testing.T) {
+	var c credential.Credential
+	uri := "otpauth://totp/Acme:jane?secret=JBSWY3DPEHPK3PXP&issuer=Acme&algorithm=SHA256&period=60"
+	if err := applyCredFlags(&c, []string{"--totp", uri}); err != nil {
+		t.Fatal(err)
+	}
+	if c.TOTPSecret != "JBSWY3DPEHPK3PXP" || c.TOTPIssuer != "Acme" || c.TOTPAlgorithm != "SHA256" || c.TOTPPeriod != 60 {
+		t.Errorf("credential = %+v", c)
+	}
+
+	var buf bytes.Buffer
+	printCredential(&buf, c)
+	for _, want := range []string{"Acme", "SHA256, 6 digits, 60s"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output should contain %q: %s", want, buf.String())
+		}
+	}
+}
+
+func TestFilterCredentials(t *testing.T) {
+	all := []credential.Credential{
+		{ID: "c1", IdentityID: "a", Label: "zeta"},
+		{ID: "c2", IdentityID: "b", Label: "alpha"},
+		{ID: "c3", IdentityID: "a", Label: "Beta"},
+	}
+
+	got := filterCredentials(all, "a")
+	if len(got) != 2 || got[0].ID != "c3" || got[1].ID != "c1" {
+		t.Errorf("filtered = %+v, want c3, c1", got)
+	}
+
+	if got := filterCredentials(all, ""); len(got) != 3 {
+		t.Errorf("unfiltered = %d, want 3", len(got))
+	}
+}
+
+func TestRedactCredentials(t *testing.T) {
+	creds := []credential.Credential{{ID: "c1", Password: "secret", TOTPSecret: "JBSWY3DP"}}
+
+	got := redactCredentials(creds)
+	if got[0].Password != "" || got[0].TOTPSecret != "" {
+		t.Error("secrets should be blanked")
+	}
+	if creds[0].Password != "secret" {
+		t.Error("input should not be modified")
+	}
+}
+
+func TestPrintCredential(t *testing.T) {
+	var buf bytes.Buffer
+	printCredential(&buf, credential.Credential{ID: "c1", Label: "GitHub", Password: "pw"})
+
+	out := buf.String()
+	for _, want := range []string{"c1", "GitHub", "pw"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output should contain %q: %s", want, out)
+		}
+	}
+	if strings.Contains(out, "totp") {
+		t.Error("empty totp should be omitted")
+	}
+}
+
+func TestTruncateField(t *testing.T) {
+	tests := []struct {
+		input string
+		n     int
+		want  string
+	}{
+		{"GitHub", 10, "GitHub"},
+		{"this is too long", 10, "this is t…"},
+		{"café", 4, "café"},
+		{"zürich-münchen", 8, "zürich-…"},
+	}
+
+	for _, tt := range tests {
+		got := truncateField(tt.input, tt.n)
+		if got != tt.want {
+			t.Errorf("truncateField(%q, %d) = %q, want %q", tt.input, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/zarlcorp/zburn/internal/credential"
+	"github.com/zarlcorp/zburn/internal/exporter"
+	"github.com/zarlcorp/zburn/internal/identity"
+)
+
+const exportUsage = `usage: zburn export [flags]
+
+flags:
+  --format <format>      zburn, bitwarden, keepass-csv or keepass-xml (default: zburn)
+  --out <file>           write to a file instead of stdout
+  --force                overwrite an existing --out file
+  --identity <id|email>  export only this identity (repeatable)
+  --tag <tag>            export only identities with this tag (repeatable)`
+
+// CmdExport writes identities and their credentials in a format other
+// password managers import. The output is unencrypted.
+func CmdExport(args []string) {
+	if firstArg(args, "--format", "--out", "--identity", "--tag") != "" {
+		fmt.Fprintln(os.Stderr, exportUsage)
+		os.Exit(1)
+	}
+
+	format := exporter.Zburn
+	if v, ok := flagValue(args, "--format"); ok {
+		f, err := exporter.ParseFormat(v)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "zburn: export: %v\n\n%s\n", err, exportUsage)
+			os.Exit(1)
+		}
+		format = f
+	}
+
+	out, toFile := flagValue(args, "--out")
+	if toFile && out == "-" {
+		toFile = false
+	}
+	if toFile && !hasFlag(args, "--force") {
+		if _, err := os.Stat(out); err == nil {
+			fmt.Fprintf(os.Stderr, "zburn: export: %s already exists (use --force to overwrite)\n", out)
+			os.Exit(1)
+		}
+	}
+
+	v, err := openCredVault()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+		os.Exit(1)
+	}
+	defer v.store.Close()
+
+	ids, err := v.identities.List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: export: %v\n", err)
+		os.Exit(1)
+	}
+	creds, err := v.credentials.List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: export: %v\n", err)
+		os.Exit(1)
+	}
+
+	var only []string
+	for _, ref := range flagValues(args, "--identity") {
+		id, err := findIdentity(v.identities, ref)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "zburn: export: %v\n", err)
+			os.Exit(1)
+		}
+		only = append(only, id.ID)
+	}
+
+	vault := exportVault(ids, creds, only, flagValues(args, "--tag"))
+	if len(vault.Identities) == 0 && len(vault.Credentials) == 0 {
+		fmt.Fprintln(os.Stderr, "zburn: export: nothing to export")
+		os.Exit(1)
+	}
+
+	write := func(w io.Writer) error { return exporter.Write(w, format, vault, time.Now()) }
+	if toFile {
+		err = writeFileAtomic(out, write)
+	} else {
+		err = write(os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: export: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Fprintf(os.Stderr, "exported %d identities, %d credentials (%s)\n", len(vault.Identities), len(vault.Credentials), format)
+	fmt.Fprintln(os.Stderr, "warning: the export is unencrypted and contains every password; delete it once imported")
+}
+
+// exportVault selects what to export, oldest identity first. With no
+// selection everything is exported, including credentials whose identity
+// was forgotten; otherwise only the selected identities' credentials are.
+func exportVault(ids []identity.Identity, creds []credential.Credential, only, tags []string) exporter.Vault {
+	selected := len(only) > 0 || len(tags) > 0
+	ids = filterByTags(ids, tags)
+	if len(only) > 0 {
+		ids = slices.DeleteFunc(ids, func(id identity.Identity) bool {
+			return !slices.Contains(only, id.ID)
+		})
+	}
+	sort.SliceStable(ids, func(i, j int) bool {
+		return ids[i].CreatedAt.Before(ids[j].CreatedAt)
+	})
+
+	if !selected {
+		return exporter.Vault{Identities: ids, Credentials: creds}
+	}
+
+	keep := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		keep[id.ID] = true
+	}
+	var picked []credential.Credential
+	for _, c := range creds {
+		if keep[c.IdentityID] {
+			picked = append(picked, c)
+		}
+	}
+	return exporter.Vault{Identities: ids, Credentials: picked}
+}
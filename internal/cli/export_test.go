@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/zarlcorp/zburn/internal/credential"
+	"github.com/zarlcorp/zburn/internal/identity"
+)
+
+func TestExportVault(t *testing.T) {
+	now := time.Now()
+	ids := []identity.Identity{
+		{ID: "new", CreatedAt: now, Tags: []string{"work"}},
+		{ID: "old", CreatedAt: now.Add(-time.Hour)},
+	}
+	creds := []credential.Credential{
+		{ID: "c1", IdentityID: "new"},
+		{ID: "c2", IdentityID: "old"},
+		{ID: "c3", IdentityID: "gone"},
+	}
+
+	tests := []struct {
+		name      string
+		only      []string
+		tags      []string
+		wantIDs   []string
+		wantCreds []string
+	}{
+		{"everything", nil, nil, []string{"old", "new"}, []string{"c1", "c2", "c3"}},
+		{"by identity", []string{"old"}, nil, []string{"old"}, []string{"c2"}},
+		{"by tag", nil, []string{"work"}, []string{"new"}, []string{"c1"}},
+		{"identity and tag", []string{"old"}, []string{"work"}, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := exportVault(append([]identity.Identity(nil), ids...), creds, tt.only, tt.tags)
+			var gotIDs, gotCreds []string
+			for _, id := range v.Identities {
+				gotIDs = append(gotIDs, id.ID)
+			}
+			for _, c := range v.Credentials {
+				gotCreds = append(gotCreds, c.ID)
+			}
+			if !slices.Equal(gotIDs, tt.wantIDs) || !slices.Equal(gotCreds, tt.wantCreds) {
+				t.Errorf("exportVault = %v %v, want %v %v", gotIDs, gotCreds, tt.wantIDs, tt.wantCreds)
+			}
+		})
+	}
+}
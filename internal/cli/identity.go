@@ -0,0 +1,312 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zarlcorp/zburn/internal/identity"
+)
+
+// maxIdentityCount caps a single --count batch.
+const maxIdentityCount = 100000
+
+// Output formats for generated identities.
+const (
+	formatText  = "text"
+	formatJSON  = "json"
+	formatJSONL = "jsonl"
+	formatCSV   = "csv"
+)
+
+// CmdIdentity generates and prints one identity, or a batch with --count.
+// Batches are streamed as they are generated; with --save the whole batch
+// is stored after a single unlock, and in per-mailbox mode each saved
+// address gets its forwarding rule.
+func CmdIdentity(ctx context.Context, args []string) {
+	save := hasFlag(args, "--save")
+
+	count, err := identityCount(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: identity: %v\n", err)
+		os.Exit(1)
+	}
+
+	var opts []identity.Option
+	if v, ok := flagValue(args, "--locale"); ok {
+		l, err := identity.LookupLocale(v)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+			os.Exit(1)
+		}
+		opts = append(opts, identity.WithLocale(l))
+	}
+
+	g, err := identityGenerator(args, save)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: identity: %v\n", err)
+		os.Exit(1)
+	}
+
+	tplName, useTemplate := flagValue(args, "--template")
+
+	var put func(identity.Identity) error
+	forwardFailed := false
+	if save || useTemplate {
+		// unlock before printing so the prompt does not interleave with output
+		s, col, err := OpenStore(DataDir())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+			os.Exit(1)
+		}
+		defer s.Close()
+
+		if useTemplate {
+			t, err := loadTemplate(s, tplName)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "zburn: identity: %v\n", err)
+				os.Exit(1)
+			}
+			opts = append(opts, identity.WithTemplate(t))
+		}
+		if save {
+			fwd, err := newMailboxForwarder(s)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+				os.Exit(1)
+			}
+			put = func(id identity.Identity) error {
+				if err := col.Put(id.ID, id); err != nil {
+					return err
+				}
+				if err := fwd.add(ctx, id); err != nil {
+					forwardFailed = true
+					fmt.Fprintf(os.Stderr, "zburn: identity: %v\n", err)
+				}
+				return nil
+			}
+		}
+	}
+
+	enc := newIdentityEncoder(os.Stdout, identityFormat(args, count))
+	for range count {
+		id := g.Generate("", opts...)
+		if err := enc.Encode(id); err != nil {
+			fmt.Fprintf(os.Stderr, "zburn: identity: %v\n", err)
+			os.Exit(1)
+		}
+		if put != nil {
+			if err := put(id); err != nil {
+				enc.Flush()
+				fmt.Fprintf(os.Stderr, "zburn: save: %v\n", err)
+				os.Exit(1)
+			}
+		}
+	}
+	if err := enc.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: identity: %v\n", err)
+		os.Exit(1)
+	}
+
+	if save {
+		if count == 1 {
+			fmt.Fprintln(os.Stderr, "saved")
+		} else {
+			fmt.Fprintf(os.Stderr, "saved %d identities\n", count)
+		}
+	}
+	if forwardFailed {
+		fmt.Fprintln(os.Stderr, "run zburn reconcile to add the missing forwarding rules")
+		os.Exit(1)
+	}
+}
+
+// identityGenerator returns a seeded generator for --seed, which cannot be
+// saved: every run would store the same IDs and addresses again.
+func identityGenerator(args []string, save bool) (*identity.Generator, error) {
+	seed, ok := flagValue(args, "--seed")
+	if !ok {
+		return identity.New(), nil
+	}
+	if save {
+		return nil, fmt.Errorf("--seed cannot be combined with --save")
+	}
+	return identity.NewSeeded(seed), nil
+}
+
+// identityCount parses --count, defaulting to 1.
+func identityCount(args []string) (int, error) {
+	v, ok := flagValue(args, "--count")
+	if !ok {
+		return 1, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("--count must be a positive number, got %q", v)
+	}
+	if n > maxIdentityCount {
+		return 0, fmt.Errorf("--count is limited to %d", maxIdentityCount)
+	}
+	return n, nil
+}
+
+// identityFormat picks the output format from the flags. --json prints a
+// single indented object, or JSON Lines when more than one is generated.
+func identityFormat(args []string, count int) string {
+	switch {
+	case hasFlag(args, "--csv"):
+		return formatCSV
+	case hasFlag(args, "--jsonl"):
+		return formatJSONL
+	case hasFlag(args, "--json") && count == 1:
+		return formatJSON
+	case hasFlag(args, "--json"):
+		return formatJSONL
+	}
+	return formatText
+}
+
+// identityEncoder writes identities one at a time in a single format.
+type identityEncoder struct {
+	w      io.Writer
+	format string
+	csv    *csv.Writer
+	n      int
+	extra  []string // template field columns, fixed by the first identity
+}
+
+func newIdentityEncoder(w io.Writer, format string) *identityEncoder {
+	e := &identityEncoder{w: w, format: format}
+	if format == formatCSV {
+		e.csv = csv.NewWriter(w)
+	}
+	return e
+}
+
+// Encode writes id, preceded by the CSV header or a separating blank line
+// where the format needs one.
+func (e *identityEncoder) Encode(id identity.Identity) error {
+	first := e.n == 0
+	e.n++
+
+	switch e.format {
+	case formatJSON:
+		enc := json.NewEncoder(e.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(id)
+	case formatJSONL:
+		return json.NewEncoder(e.w).Encode(id)
+	case formatCSV:
+		if first {
+			for _, f := range id.Fields {
+				e.extra = append(e.extra, f.Name)
+			}
+			header := append(slices.Clone(identityCSVHeader), e.extra...)
+			if err := e.csv.Write(header); err != nil {
+				return err
+			}
+		}
+		record := identityRecord(id)
+		for _, name := range e.extra {
+			v, _ := id.FieldValue(name)
+			record = append(record, v)
+		}
+		if err := e.csv.Write(record); err != nil {
+			return err
+		}
+		// flush per row so large batches stream
+		e.csv.Flush()
+		return e.csv.Error()
+	default:
+		if !first {
+			fmt.Fprintln(e.w)
+		}
+		printIdentity(e.w, id)
+		return nil
+	}
+}
+
+// Flush writes any buffered output.
+func (e *identityEncoder) Flush() error {
+	if e.csv == nil {
+		return nil
+	}
+	e.csv.Flush()
+	return e.csv.Error()
+}
+
+// identityCSVHeader matches the identity JSON field names. Template fields
+// follow as extra columns.
+var identityCSVHeader = []string{
+	"id", "first_name", "last_name", "email", "phone",
+	"street", "city", "state", "zip", "country", "dob", "created_at",
+	"username", "company", "job_title", "security_answers",
+}
+
+func identityRecord(id identity.Identity) []string {
+	return []string{
+		id.ID, id.FirstName, id.LastName, id.Email, id.Phone,
+		id.Street, id.City, id.State, id.Zip, id.Country,
+		id.DOB.Format("2006-01-02"),
+		id.CreatedAt.UTC().Format(time.RFC3339),
+		id.Username, id.Company, id.JobTitle,
+		securityAnswersCell(id.SecurityAnswers),
+	}
+}
+
+// securityAnswersCell flattens security answers into one CSV cell as
+// "question answer" pairs separated by semicolons.
+func securityAnswersCell(answers []identity.SecurityAnswer) string {
+	parts := make([]string, len(answers))
+	for i, a := range answers {
+		parts[i] = a.Question + " " + a.Answer
+	}
+	return strings.Join(parts, "; ")
+}
+
+func printIdentity(w io.Writer, id identity.Identity) {
+	fmt.Fprintf(w, "  id:       %s\n", id.ID)
+	fmt.Fprintf(w, "  name:     %s %s\n", id.FirstName, id.LastName)
+	fmt.Fprintf(w, "  email:    %s\n", id.Email)
+	fmt.Fprintf(w, "  phone:    %s\n", id.Phone)
+	fmt.Fprintf(w, "  address:  %s, %s, %s %s\n", id.Street, id.City, id.State, id.Zip)
+	if id.Country != "" {
+		fmt.Fprintf(w, "  country:  %s\n", id.Country)
+	}
+	fmt.Fprintf(w, "  dob:      %s\n", id.DOB.Format("2006-01-02"))
+	if id.Username != "" {
+		fmt.Fprintf(w, "  username: %s\n", id.Username)
+	}
+	if id.Company != "" {
+		fmt.Fprintf(w, "  company:  %s\n", id.Company)
+	}
+	if id.JobTitle != "" {
+		fmt.Fprintf(w, "  job:      %s\n", id.JobTitle)
+	}
+	for i, a := range id.SecurityAnswers {
+		label := ""
+		if i == 0 {
+			label = "security:"
+		}
+		fmt.Fprintf(w, "  %-9s %s %s\n", label, a.Question, a.Answer)
+	}
+	for _, f := range id.Fields {
+		fmt.Fprintf(w, "  %-9s %s\n", f.Name+":", f.Value)
+	}
+	if id.Label != "" {
+		fmt.Fprintf(w, "  label:    %s\n", id.Label)
+	}
+	if len(id.Tags) > 0 {
+		fmt.Fprintf(w, "  tags:     %s\n", strings.Join(id.Tags, ", "))
+	}
+	if id.Notes != "" {
+		fmt.Fprintf(w, "  notes:    %s\n", id.Notes)
+	}
+}
@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/zarlcorp/zburn/internal/identity"
+)
+
+func TestIdentityCount(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    int
+		wantErr bool
+	}{
+		{nil, 1, false},
+		{[]string{"--count", "250"}, 250, false},
+		{[]string{"--count=3"}, 3, false},
+		{[]string{"--count", "0"}, 0, true},
+		{[]string{"--count", "many"}, 0, true},
+		{[]string{"--count", "1000001"}, 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := identityCount(tt.args)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("identityCount(%v) = %d, %v; want %d, err %v", tt.args, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestIdentityGenerator(t *testing.T) {
+	tests := []struct {
+		args    []string
+		save    bool
+		wantErr bool
+	}{
+		{nil, false, false},
+		{nil, true, false},
+		{[]string{"--seed", "qa"}, false, false},
+		{[]string{"--seed", "qa"}, true, true},
+	}
+
+	for _, tt := range tests {
+		g, err := identityGenerator(tt.args, tt.save)
+		if (err != nil) != tt.wantErr || (err == nil) != (g != nil) {
+			t.Errorf("identityGenerator(%v, %v) = %v, %v; want err %v", tt.args, tt.save, g, err, tt.wantErr)
+		}
+	}
+
+	a, _ := identityGenerator([]string{"--seed", "qa"}, false)
+	b, _ := identityGenerator([]string{"--seed", "qa"}, false)
+	if a.Generate("").ID != b.Generate("").ID {
+		t.Error("--seed should give a reproducible generator")
+	}
+}
+
+func TestIdentityFormat(t *testing.T) {
+	tests := []struct {
+		args  []string
+		count int
+		want  string
+	}{
+		{nil, 1, formatText},
+		{[]string{"--json"}, 1, formatJSON},
+		{[]string{"--json"}, 5, formatJSONL},
+		{[]string{"--jsonl"}, 1, formatJSONL},
+		{[]string{"--csv", "--json"}, 5, formatCSV},
+	}
+
+	for _, tt := range tests {
+		if got := identityFormat(tt.args, tt.count); got != tt.want {
+			t.Errorf("identityFormat(%v, %d) = %s, want %s", tt.args, tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestIdentityEncoderJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	enc := newIdentityEncoder(&buf, formatJSONL)
+	g := identity.NewSeeded("jsonl")
+	for range 3 {
+		if err := enc.Encode(g.Generate("")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3", len(lines))
+	}
+	for _, l := range lines {
+		var id identity.Identity
+		if err := json.Unmarshal([]byte(l), &id); err != nil || id.ID == "" {
+			t.Errorf("line %q is not an identity: %v", l, err)
+		}
+	}
+}
+
+func TestIdentityEncoderCSV(t *testing.T) {
+	var buf bytes.Buffer
+	enc := newIdentityEncoder(&buf, formatCSV)
+	g := identity.NewSeeded("csv")
+	var ids []identity.Identity
+	for range 2 {
+		id := g.Generate("")
+		ids = append(ids, id)
+		if err := enc.Encode(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := enc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want header + 2", len(rows))
+	}
+	if strings.Join(rows[0], ",") != strings.Join(identityCSVHeader, ",") {
+		t.Errorf("header = %v", rows[0])
+	}
+	if rows[1][0] != ids[0].ID || rows[2][3] != ids[1].Email {
+		t.Errorf("rows do not match identities: %v", rows[1:])
+	}
+}
+
+func TestIdentityEncoderText(t *testing.T) {
+	var buf bytes.Buffer
+	enc := newIdentityEncoder(&buf, formatText)
+	enc.Encode(identity.Identity{ID: "a1"})
+	enc.Encode(identity.Identity{ID: "b2"})
+
+	if !strings.Contains(buf.String(), "a1\n") || !strings.Contains(buf.String(), "\n\n  id:       b2") {
+		t.Errorf("identities should be separated by a blank line:\n%s", buf.String())
+	}
+}
+
+func TestIdentityOutputExtraAttributes(t *testing.T) {
+	id := identity.NewSeeded("attrs").Generate("")
+
+	var text bytes.Buffer
+	printIdentity(&text, id)
+	for _, want := range []string{"  username: " + id.Username, "  company:  " + id.Company, "  security: " + id.SecurityAnswers[0].Question} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("text output missing %q:\n%s", want, text.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := newIdentityEncoder(&buf, formatCSV)
+	enc.Encode(id)
+	enc.Flush()
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	col := slices.Index(rows[0], "security_answers")
+	if col < 0 || !strings.Contains(rows[1][col], id.SecurityAnswers[2].Answer) {
+		t.Errorf("csv security_answers = %v", rows)
+	}
+}
@@ -0,0 +1,249 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/zarlcorp/zburn/internal/identity"
+	"github.com/zarlcorp/zburn/internal/importer"
+)
+
+// importTag marks identities generated by an import.
+const importTag = "imported"
+
+const importUsage = `usage: zburn import <file> [flags]
+
+flags:
+  --format <format>      bitwarden, 1password-csv, 1pux or keepass
+                         (default: from the file extension)
+  --identity <id|email>  attach logins that match no identity to this one
+                         (default: generate an identity per unmatched username)
+  --dry-run              show the report without saving
+  --yes                  save without asking
+  --json                 output the report as JSON`
+
+// importReportItem is the JSON form of one planned entry. Passwords and
+// TOTP secrets are left out so the report is safe to share.
+type importReportItem struct {
+	Action        string `json:"action"`
+	Reason        string `json:"reason,omitempty"`
+	Warning       string `json:"warning,omitempty"`
+	Label         string `json:"label"`
+	URL           string `json:"url,omitempty"`
+	Username      string `json:"username,omitempty"`
+	HasTOTP       bool   `json:"has_totp"`
+	IdentityID    string `json:"identity_id,omitempty"`
+	IdentityEmail string `json:"identity_email,omitempty"`
+	NewIdentity   bool   `json:"new_identity,omitempty"`
+}
+
+// importReport is the JSON output of the import command.
+type importReport struct {
+	Format        importer.Format    `json:"format"`
+	Items         []importReportItem `json:"items"`
+	NewIdentities int                `json:"new_identities"`
+	Ignored       int                `json:"ignored"` // non-login items in the export
+	Saved         bool               `json:"saved"`
+}
+
+// CmdImport imports logins from a password-manager export as credentials.
+// The plan is always reported before anything is saved. New identities on
+// a managed domain get their forwarding rule in per-mailbox mode.
+func CmdImport(ctx context.Context, args []string) {
+	path := firstArg(args, "--format", "--identity")
+	if path == "" {
+		fmt.Fprintln(os.Stderr, importUsage)
+		os.Exit(1)
+	}
+	asJSON := hasFlag(args, "--json")
+	dryRun := hasFlag(args, "--dry-run")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: import: %v\n", err)
+		os.Exit(1)
+	}
+
+	var format importer.Format
+	if v, ok := flagValue(args, "--format"); ok {
+		format, err = importer.ParseFormat(v)
+	} else {
+		format, err = importer.Detect(path, data)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: import: %v\n\n%s\n", err, importUsage)
+		os.Exit(1)
+	}
+
+	entries, ignored, err := importer.Parse(format, data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: import: %v\n", err)
+		os.Exit(1)
+	}
+
+	v, err := openCredVault()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+		os.Exit(1)
+	}
+	defer v.store.Close()
+
+	ids, err := v.identities.List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: import: %v\n", err)
+		os.Exit(1)
+	}
+	existing, err := v.credentials.List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: import: %v\n", err)
+		os.Exit(1)
+	}
+
+	opts := importer.Options{NewIdentity: importedIdentity(identity.New()), Now: time.Now().UTC()}
+	if ref, ok := flagValue(args, "--identity"); ok {
+		id, err := findIdentity(v.identities, ref)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "zburn: import: %v\n", err)
+			os.Exit(1)
+		}
+		opts.Fallback = &id
+	}
+
+	plan := importer.PlanImport(entries, ids, existing, opts)
+	report := newImportReport(format, plan, ignored)
+
+	if !asJSON {
+		printImportReport(os.Stdout, report)
+	}
+
+	if dryRun || plan.Count(importer.ActionAdd) == 0 {
+		if asJSON {
+			printJSON(report)
+		}
+		return
+	}
+
+	if !hasFlag(args, "--yes") && !confirmImport(os.Stdin, os.Stderr, report, !asJSON) {
+		fmt.Fprintln(os.Stderr, "aborted")
+		os.Exit(1)
+	}
+
+	fwd, err := newMailboxForwarder(v.store)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+		os.Exit(1)
+	}
+	forwardFailed := false
+	for _, id := range plan.NewIdentities {
+		if err := v.identities.Put(id.ID, id); err != nil {
+			fmt.Fprintf(os.Stderr, "zburn: import: save identity: %v\n", err)
+			os.Exit(1)
+		}
+		if err := fwd.add(ctx, id); err != nil {
+			forwardFailed = true
+			fmt.Fprintf(os.Stderr, "zburn: import: %v\n", err)
+		}
+	}
+	saved := 0
+	for _, c := range plan.Credentials() {
+		if err := v.credentials.Put(c.ID, c); err != nil {
+			fmt.Fprintf(os.Stderr, "zburn: import: save %s: %v (%d of %d saved)\n", c.Label, err, saved, plan.Count(importer.ActionAdd))
+			os.Exit(1)
+		}
+		saved++
+	}
+
+	if asJSON {
+		report.Saved = true
+		printJSON(report)
+	} else {
+		fmt.Printf("imported %d credentials\n", saved)
+	}
+	if forwardFailed {
+		fmt.Fprintln(os.Stderr, "run zburn reconcile to add the missing forwarding rules")
+		os.Exit(1)
+	}
+}
+
+// confirmImport asks whether to save the plan. The report is printed to w
+// first unless it was already shown, as with --json, where stdout is kept
+// for the JSON.
+func confirmImport(r io.Reader, w io.Writer, report importReport, shown bool) bool {
+	if !shown {
+		printImportReport(w, report)
+	}
+	return confirm(r, w, "import these credentials? [y/N] ")
+}
+
+// importedIdentity generates identities for unmatched usernames: an email
+// address becomes the identity's email, anything else its username.
+func importedIdentity(g *identity.Generator) func(string) identity.Identity {
+	return func(username string) identity.Identity {
+		id := g.Generate("")
+		if strings.Contains(username, "@") {
+			id.Email = username
+		} else {
+			id.Username = username
+		}
+		id.Tags = []string{importTag}
+		return id
+	}
+}
+
+func newImportReport(format importer.Format, plan importer.Plan, ignored int) importReport {
+	r := importReport{
+		Format:        format,
+		Items:         make([]importReportItem, 0, len(plan.Items)),
+		NewIdentities: len(plan.NewIdentities),
+		Ignored:       ignored,
+	}
+	for _, it := range plan.Items {
+		r.Items = append(r.Items, importReportItem{
+			Action:        string(it.Action),
+			Reason:        it.Reason,
+			Warning:       it.Warning,
+			Label:         it.Credential.Label,
+			URL:           it.Credential.URL,
+			Username:      it.Credential.Username,
+			HasTOTP:       it.Credential.TOTPSecret != "",
+			IdentityID:    it.Identity.ID,
+			IdentityEmail: it.Identity.Email,
+			NewIdentity:   it.NewIdentity,
+		})
+	}
+	return r
+}
+
+func printImportReport(w io.Writer, r importReport) {
+	added, skipped := 0, 0
+	for _, it := range r.Items {
+		target := it.Reason
+		if it.Action == string(importer.ActionAdd) {
+			added++
+			target = "→ " + it.IdentityEmail
+			if it.NewIdentity {
+				target += " (new identity)"
+			}
+		} else {
+			skipped++
+		}
+		totp := ""
+		if it.HasTOTP {
+			totp = "totp"
+		}
+		fmt.Fprintf(w, "%-5s %-20s %-28s %-5s %s\n", it.Action, truncateField(it.Label, 20), truncateField(it.Username, 28), totp, target)
+		if it.Warning != "" {
+			fmt.Fprintf(w, "      ! %s\n", it.Warning)
+		}
+	}
+
+	summary := fmt.Sprintf("%d to import, %d new identities, %d skipped", added, r.NewIdentities, skipped)
+	if r.Ignored > 0 {
+		summary += fmt.Sprintf(", %d non-login items ignored", r.Ignored)
+	}
+	fmt.Fprintf(w, "\n%s (%s)\n", summary, r.Format)
+}
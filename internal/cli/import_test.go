@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/zarlcorp/zburn/internal/credential"
+	"github.com/zarlcorp/zburn/internal/identity"
+	"github.com/zarlcorp/zburn/internal/importer"
+)
+
+func TestImportedIdentity(t *testing.T) {
+	gen := importedIdentity(identity.NewSeeded("import"))
+
+	byEmail := gen("jane@example.com")
+	if byEmail.Email != "jane@example.com" || !byEmail.HasTag(importTag) {
+		t.Errorf("identity = %+v, want email jane@example.com tagged %s", byEmail, importTag)
+	}
+
+	byName := gen("janed")
+	if byName.Username != "janed" || byName.Email == "janed" || byName.ID == byEmail.ID {
+		t.Errorf("identity = %+v, want username janed and a generated email", byName)
+	}
+}
+
+func TestImportReport(t *testing.T) {
+	plan := importer.Plan{
+		Items: []importer.Item{
+			{
+				Action:      importer.ActionAdd,
+				Credential:  credential.Credential{Label: "GitHub", Username: "jane@zburn.id", Password: "s3cret", TOTPSecret: "JBSWY3DPEHPK3PXP"},
+				Identity:    identity.Identity{ID: "abc", Email: "jane@zburn.id"},
+				NewIdentity: true,
+			},
+			{
+				Action:     importer.ActionSkip,
+				Reason:     "already saved",
+				Warning:    "totp not imported",
+				Credential: credential.Credential{Label: "Forum", Username: "janed"},
+			},
+		},
+		NewIdentities: []identity.Identity{{ID: "abc"}},
+	}
+
+	r := newImportReport(importer.Bitwarden, plan, 3)
+	if !r.Items[0].HasTOTP || r.Items[0].IdentityID != "abc" {
+		t.Errorf("item = %+v", r.Items[0])
+	}
+
+	var buf bytes.Buffer
+	printImportReport(&buf, r)
+	out := buf.String()
+	for _, want := range []string{
+		"→ jane@zburn.id (new identity)",
+		"already saved",
+		"! totp not imported",
+		"1 to import, 1 new identities, 1 skipped, 3 non-login items ignored (bitwarden)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "s3cret") {
+		t.Error("report must not show passwords")
+	}
+}
+
+func TestConfirmImport(t *testing.T) {
+	r := importReport{Format: importer.KeePass, Items: []importReportItem{{Action: string(importer.ActionAdd), Label: "GitHub"}}}
+
+	tests := []struct {
+		name       string
+		shown      bool
+		wantReport bool
+	}{
+		{"text report already on stdout", true, false},
+		{"json on stdout", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if !confirmImport(strings.NewReader("y\n"), &out, r, tt.shown) {
+				t.Error("yes should confirm")
+			}
+			if got := strings.Contains(out.String(), "GitHub"); got != tt.wantReport {
+				t.Errorf("report printed = %v, want %v:\n%s", got, tt.wantReport, out.String())
+			}
+			if strings.Index(out.String(), "import these credentials?") < strings.Index(out.String(), "GitHub") {
+				t.Error("the report should come before the prompt")
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/zarlcorp/zburn/internal/rekey"
+)
+
+// CmdPasswd changes the master password, re-encrypting the whole vault.
+func CmdPasswd(args []string) {
+	if len(args) > 0 {
+		fmt.Fprintln(os.Stderr, "usage: zburn passwd")
+		os.Exit(1)
+	}
+
+	dir := DataDir()
+	if IsFirstRun(dir) {
+		fmt.Fprintln(os.Stderr, "zburn: passwd: no vault yet; run zburn to create one")
+		os.Exit(1)
+	}
+
+	current, err := ReadPassword("current password: ", os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: passwd: %v\n", err)
+		os.Exit(1)
+	}
+	next, err := readChangedPassword()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: passwd: %v\n", err)
+		os.Exit(1)
+	}
+
+	res, err := rekey.Rekey(dir, current, next)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: passwd: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("re-encrypted %s under the new password\n", snapshotSummary(res.Snapshot))
+	if res.Leftover != "" {
+		fmt.Fprintf(os.Stderr, "warning: could not remove the old vault at %s; delete it by hand\n", res.Leftover)
+	}
+	if os.Getenv("ZBURN_PASSWORD") != "" {
+		fmt.Fprintln(os.Stderr, "note: ZBURN_PASSWORD still holds the old password")
+	}
+}
+
+// readChangedPassword reads the new master password, from
+// ZBURN_NEW_PASSWORD if set, otherwise prompting with confirmation.
+func readChangedPassword() (string, error) {
+	if p := os.Getenv("ZBURN_NEW_PASSWORD"); p != "" {
+		return p, nil
+	}
+	pass, err := promptPassword("new password: ", os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	again, err := promptPassword("confirm password: ", os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	if pass != again {
+		return "", fmt.Errorf("passwords do not match")
+	}
+	return pass, nil
+}
@@ -0,0 +1,14 @@
+package cli
+
+import "testing"
+
+func TestReadChangedPasswordFromEnv(t *testing.T) {
+	t.Setenv("ZBURN_NEW_PASSWORD", "n3w")
+	got, err := readChangedPassword()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "n3w" {
+		t.Errorf("password = %q, want %q", got, "n3w")
+	}
+}
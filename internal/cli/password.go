@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/zarlcorp/zburn/internal/identity"
+)
+
+// maxPasswordCount caps a single --count batch.
+const maxPasswordCount = 1000
+
+const passwordUsage = `usage: zburn password [flags]
+
+flags:
+  --count <n>            generate this many passwords (default 1)
+  --json                 output as JSON
+
+` + policyFlagUsage
+
+// passwordResult is the JSON output of the password command.
+type passwordResult struct {
+	Passwords   []string                `json:"passwords"`
+	Policy      identity.PasswordPolicy `json:"policy"`
+	EntropyBits float64                 `json:"entropy_bits"`
+}
+
+// CmdPassword generates passwords without touching the store, unless
+// --preset names a saved site preset.
+func CmdPassword(args []string) {
+	count, err := passwordCount(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: password: %v\n", err)
+		os.Exit(1)
+	}
+
+	var policy identity.PasswordPolicy
+	if _, ok := flagValue(args, "--preset"); ok {
+		s, _, err := OpenStore(DataDir())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+			os.Exit(1)
+		}
+		policy, err = resolvePolicy(s, args, "")
+		s.Close()
+	} else {
+		policy, err = parsePolicy(args, identity.DefaultPasswordPolicy())
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: password: %v\n\n%s\n", err, passwordUsage)
+		os.Exit(1)
+	}
+
+	res := passwordResult{Policy: policy, EntropyBits: policy.Entropy()}
+	for range count {
+		res.Passwords = append(res.Passwords, identity.NewPassword(policy))
+	}
+
+	if hasFlag(args, "--json") {
+		printJSON(res)
+		return
+	}
+	printPasswords(os.Stdout, os.Stderr, res)
+}
+
+// passwordCount parses --count, defaulting to 1.
+func passwordCount(args []string) (int, error) {
+	v, ok := flagValue(args, "--count")
+	if !ok {
+		return 1, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("--count must be a positive number, got %q", v)
+	}
+	if n > maxPasswordCount {
+		return 0, fmt.Errorf("--count is limited to %d", maxPasswordCount)
+	}
+	return n, nil
+}
+
+// printPasswords writes one password per line to w, and the policy and
+// entropy estimate to info so piped output holds only the passwords.
+func printPasswords(w, info io.Writer, res passwordResult) {
+	for _, pw := range res.Passwords {
+		fmt.Fprintln(w, pw)
+	}
+	fmt.Fprintf(info, "%s, ~%.0f bits of entropy (%s)\n", res.Policy, res.EntropyBits, strength(res.EntropyBits))
+}
+
+// strength rates an entropy estimate in words.
+func strength(bits float64) string {
+	switch {
+	case bits < 40:
+		return "weak"
+	case bits < 60:
+		return "fair"
+	case bits < 80:
+		return "good"
+	}
+	return "strong"
+}
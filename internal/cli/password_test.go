@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/zarlcorp/zburn/internal/identity"
+)
+
+func TestPasswordCount(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    int
+		wantErr bool
+	}{
+		{nil, 1, false},
+		{[]string{"--count", "5"}, 5, false},
+		{[]string{"--count=0"}, 0, true},
+		{[]string{"--count", "lots"}, 0, true},
+		{[]string{"--count", "1001"}, 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := passwordCount(tt.args)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("passwordCount(%v) = %d, %v; want %d, err %v", tt.args, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestPrintPasswords(t *testing.T) {
+	p := identity.PassphrasePolicy(6)
+	res := passwordResult{Passwords: []string{"a-b", "c-d"}, Policy: p, EntropyBits: p.Entropy()}
+
+	var out, info bytes.Buffer
+	printPasswords(&out, &info, res)
+	if out.String() != "a-b\nc-d\n" {
+		t.Errorf("stdout should hold only the passwords, got %q", out.String())
+	}
+	if got := info.String(); !strings.Contains(got, "6-word passphrase, ~66 bits of entropy (good)") {
+		t.Errorf("info = %q", got)
+	}
+}
+
+func TestStrength(t *testing.T) {
+	tests := []struct {
+		bits float64
+		want string
+	}{
+		{20, "weak"},
+		{45, "fair"},
+		{66, "good"},
+		{129, "strong"},
+	}
+	for _, tt := range tests {
+		if got := strength(tt.bits); got != tt.want {
+			t.Errorf("strength(%v) = %q, want %q", tt.bits, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/zarlcorp/core/pkg/zstore"
+	"github.com/zarlcorp/zburn/internal/identity"
+)
+
+// presetCollection is the store collection holding per-site password presets.
+const presetCollection = "presets"
+
+// policyValueFlags are the password policy flags that take a value.
+var policyValueFlags = []string{"--length", "--symbols", "--exclude", "--words", "--separator", "--preset"}
+
+const policyFlagUsage = `password policy flags:
+  --length <n>           password length (default 20)
+  --no-lower             no lowercase letters
+  --no-upper             no uppercase letters
+  --no-digits            no digits
+  --no-symbols           no symbols
+  --symbols <chars>      only use these symbols
+  --exclude <chars>      never use these characters
+  --passphrase           random words instead of characters
+  --words <n>            passphrase word count (default 5)
+  --separator <s>        passphrase word separator (default -)
+  --preset <site>        start from the preset saved for a site`
+
+const presetUsage = `usage: zburn preset <command> [flags]
+
+commands:
+  add   <site> [policy flags]
+  list  [--json]
+  show  <site> [--json]
+  rm    <site>
+
+` + policyFlagUsage
+
+// CmdPreset dispatches the password preset subcommands.
+func CmdPreset(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, presetUsage)
+		os.Exit(1)
+	}
+
+	sub, rest := args[0], args[1:]
+	switch sub {
+	case "add":
+		cmdPresetAdd(rest)
+	case "list", "ls":
+		cmdPresetList(rest)
+	case "show":
+		cmdPresetShow(rest)
+	case "rm":
+		cmdPresetRemove(rest)
+	default:
+		fmt.Fprintf(os.Stderr, "zburn: unknown preset command %q\n\n%s\n", sub, presetUsage)
+		os.Exit(1)
+	}
+}
+
+// parsePolicy applies the policy flags in args on top of base.
+func parsePolicy(args []string, base identity.PasswordPolicy) (identity.PasswordPolicy, error) {
+	p := base
+
+	_, words := flagValue(args, "--words")
+	if (hasFlag(args, "--passphrase") || words) && !p.Passphrase {
+		p = identity.PassphrasePolicy(identity.DefaultPassphraseWords)
+	}
+
+	if v, ok := flagValue(args, "--length"); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return p, fmt.Errorf("invalid --length %q", v)
+		}
+		p.Length = n
+	}
+	if v, ok := flagValue(args, "--words"); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return p, fmt.Errorf("invalid --words %q", v)
+		}
+		p.Words = n
+	}
+	if v, ok := flagValue(args, "--separator"); ok {
+		p.Separator = v
+	}
+	if v, ok := flagValue(args, "--symbols"); ok {
+		p.Symbols = v != ""
+		p.SymbolSet = v
+	}
+	if v, ok := flagValue(args, "--exclude"); ok {
+		p.Exclude = v
+	}
+
+	if hasFlag(args, "--no-lower") {
+		p.Lower = false
+	}
+	if hasFlag(args, "--no-upper") {
+		p.Upper = false
+	}
+	if hasFlag(args, "--no-digits") {
+		p.Digits = false
+	}
+	if hasFlag(args, "--no-symbols") {
+		p.Symbols = false
+		p.SymbolSet = ""
+	}
+
+	if err := p.Validate(); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// resolvePolicy builds the policy for a password: the preset named by
+// --preset, else the preset saved for site, else the default, with any
+// policy flags applied on top.
+func resolvePolicy(s *zstore.Store, args []string, site string) (identity.PasswordPolicy, error) {
+	base := identity.DefaultPasswordPolicy()
+
+	if name, ok := flagValue(args, "--preset"); ok {
+		preset, err := loadPreset(s, name)
+		if err != nil {
+			return base, err
+		}
+		base = preset.Policy
+	} else if site != "" {
+		if preset, err := loadPreset(s, site); err == nil {
+			base = preset.Policy
+		}
+	}
+
+	return parsePolicy(args, base)
+}
+
+// loadPreset reads the preset saved for a site.
+func loadPreset(s *zstore.Store, site string) (identity.PasswordPreset, error) {
+	col, err := zstore.NewCollection[identity.PasswordPreset](s, presetCollection)
+	if err != nil {
+		return identity.PasswordPreset{}, err
+	}
+	p, err := col.Get(identity.PresetKey(site))
+	if errors.Is(err, zstore.ErrNotFound) {
+		return identity.PasswordPreset{}, fmt.Errorf("no preset for %q", identity.SiteName(site))
+	}
+	return p, err
+}
+
+// openPresets opens the store and its preset collection.
+func openPresets() (*zstore.Store, *zstore.Collection[identity.PasswordPreset], error) {
+	s, _, err := OpenStore(DataDir())
+	if err != nil {
+		return nil, nil, err
+	}
+	col, err := zstore.NewCollection[identity.PasswordPreset](s, presetCollection)
+	if err != nil {
+		s.Close()
+		return nil, nil, err
+	}
+	return s, col, nil
+}
+
+func cmdPresetAdd(args []string) {
+	site := identity.SiteName(firstArg(args, policyValueFlags...))
+	if site == "" {
+		fmt.Fprintln(os.Stderr, "usage: zburn preset add <site> [policy flags]")
+		os.Exit(1)
+	}
+
+	policy, err := parsePolicy(args, identity.DefaultPasswordPolicy())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: preset add: %v\n", err)
+		os.Exit(1)
+	}
+
+	s, col, err := openPresets()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+		os.Exit(1)
+	}
+	defer s.Close()
+
+	p := identity.PasswordPreset{Site: site, Policy: policy}
+	if err := col.Put(identity.PresetKey(site), p); err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: preset add: %v\n", err)
+		os.Exit(1)
+	}
+
+	if hasFlag(args, "--json") {
+		printJSON(p)
+		return
+	}
+	printPreset(os.Stdout, p)
+}
+
+func cmdPresetList(args []string) {
+	s, col, err := openPresets()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+		os.Exit(1)
+	}
+	defer s.Close()
+
+	presets, err := col.List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: preset list: %v\n", err)
+		os.Exit(1)
+	}
+	sort.Slice(presets, func(i, j int) bool { return presets[i].Site < presets[j].Site })
+
+	if hasFlag(args, "--json") {
+		printJSON(presets)
+		return
+	}
+
+	if len(presets) == 0 {
+		fmt.Println("no saved presets")
+		return
+	}
+	for _, p := range presets {
+		printPreset(os.Stdout, p)
+	}
+}
+
+func cmdPresetShow(args []string) {
+	site := firstArg(args)
+	if site == "" {
+		fmt.Fprintln(os.Stderr, "usage: zburn preset show <site> [--json]")
+		os.Exit(1)
+	}
+
+	s, _, err := openPresets()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+		os.Exit(1)
+	}
+	defer s.Close()
+
+	p, err := loadPreset(s, site)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: preset show: %v\n", err)
+		os.Exit(1)
+	}
+
+	if hasFlag(args, "--json") {
+		printJSON(p)
+		return
+	}
+	printPreset(os.Stdout, p)
+}
+
+func cmdPresetRemove(args []string) {
+	site := firstArg(args)
+	if site == "" {
+		fmt.Fprintln(os.Stderr, "usage: zburn preset rm <site>")
+		os.Exit(1)
+	}
+
+	s, col, err := openPresets()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+		os.Exit(1)
+	}
+	defer s.Close()
+
+	if err := col.Delete(identity.PresetKey(site)); err != nil {
+		if errors.Is(err, zstore.ErrNotFound) {
+			err = fmt.Errorf("no preset for %q", identity.SiteName(site))
+		}
+		fmt.Fprintf(os.Stderr, "zburn: preset rm: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("deleted %s\n", identity.SiteName(site))
+}
+
+func printPreset(w io.Writer, p identity.PasswordPreset) {
+	fmt.Fprintf(w, "  %-24s %s\n", truncateField(p.Site, 24), p.Policy)
+}
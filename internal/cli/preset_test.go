@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zarlcorp/core/pkg/zstore"
+	"github.com/zarlcorp/zburn/internal/credential"
+	"github.com/zarlcorp/zburn/internal/identity"
+)
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{"default", nil, "20 chars, a-z A-Z 0-9 symbols", false},
+		{"length and no symbols", []string{"--length", "12", "--no-symbols"}, "12 chars, a-z A-Z 0-9", false},
+		{"symbol set", []string{"--symbols=!_", "--exclude", "0O"}, "20 chars, a-z A-Z 0-9 symbols (!_), excluding 0O", false},
+		{"passphrase", []string{"--passphrase"}, "5-word passphrase", false},
+		{"words implies passphrase", []string{"--words", "7"}, "7-word passphrase", false},
+		{"bad length", []string{"--length", "x"}, "", true},
+		{"too short", []string{"--length", "2"}, "", true},
+		{"no classes", []string{"--no-lower", "--no-upper", "--no-digits", "--no-symbols"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := parsePolicy(tt.args, identity.DefaultPasswordPolicy())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && p.String() != tt.want {
+				t.Errorf("policy = %q, want %q", p, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolvePolicyUsesSitePreset(t *testing.T) {
+	s, _ := openTestStore(t)
+	col, err := zstore.NewCollection[identity.PasswordPreset](s, presetCollection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	preset := identity.PasswordPreset{
+		Site:   "bank.example",
+		Policy: identity.PasswordPolicy{Length: 8, Digits: true},
+	}
+	if err := col.Put(identity.PresetKey(preset.Site), preset); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := resolvePolicy(s, nil, "https://www.bank.example/login")
+	if err != nil || p.String() != "8 chars, 0-9" {
+		t.Errorf("site preset = %q, %v", p, err)
+	}
+
+	// flags apply on top of the preset
+	p, err = resolvePolicy(s, []string{"--length", "10"}, "bank.example")
+	if err != nil || p.Length != 10 || p.Lower {
+		t.Errorf("preset with flags = %+v, %v", p, err)
+	}
+
+	if p, _ := resolvePolicy(s, nil, "other.example"); p.Length != identity.DefaultPasswordLength {
+		t.Errorf("unknown site should use the default, got %q", p)
+	}
+
+	if _, err := resolvePolicy(s, []string{"--preset", "missing.example"}, ""); err == nil || !strings.Contains(err.Error(), "no preset") {
+		t.Errorf("missing --preset error = %v", err)
+	}
+
+	pw, err := generateCredPassword(s, nil, credential.Credential{Label: "Bank", URL: "bank.example"})
+	if err != nil || len(pw) != 8 || strings.Trim(pw, "0123456789") != "" {
+		t.Errorf("credential password = %q, %v", pw, err)
+	}
+}
@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/zarlcorp/core/pkg/zstore"
+	"github.com/zarlcorp/zburn/internal/config"
+	"github.com/zarlcorp/zburn/internal/forwarding"
+	"github.com/zarlcorp/zburn/internal/identity"
+	"github.com/zarlcorp/zburn/internal/namecheap"
+)
+
+// CmdReconcile fixes drift between per-mailbox forwarding rules and the
+// saved identities.
+func CmdReconcile(ctx context.Context, args []string) {
+	dryRun := hasFlag(args, "--dry-run")
+	asJSON := hasFlag(args, "--json")
+
+	dir := DataDir()
+	s, col, err := OpenStore(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+		os.Exit(1)
+	}
+	defer s.Close()
+
+	cfgCol, err := zstore.NewCollection[config.Envelope](s, config.Collection)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+		os.Exit(1)
+	}
+
+	nc := config.Load[config.Namecheap](cfgCol, config.KeyNamecheap)
+	gm := config.Load[config.Gmail](cfgCol, config.KeyGmail)
+	if err := checkReconcileConfig(nc, gm); err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: reconcile: %v\n", err)
+		os.Exit(1)
+	}
+
+	ids, err := col.List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: list: %v\n", err)
+		os.Exit(1)
+	}
+
+	created := config.Load[forwarding.Ledger](cfgCol, config.KeyForwarded)
+	c := namecheap.NewClient(nc.NamecheapConfig())
+	changes, owned, err := forwarding.Reconcile(ctx, c, nc.CachedDomains, ids, created, gm.Email, !dryRun)
+	if !dryRun {
+		if serr := config.Save(cfgCol, config.KeyForwarded, owned); serr != nil {
+			err = errors.Join(err, fmt.Errorf("save forwarding ledger: %w", serr))
+		}
+	}
+
+	if asJSON {
+		if changes == nil {
+			changes = []forwarding.Change{}
+		}
+		printJSON(changes)
+	} else {
+		printChanges(os.Stdout, changes, dryRun)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: reconcile: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// checkReconcileConfig ensures the settings allow per-mailbox reconciliation.
+// In catch-all mode reconciling would strip the wildcard rule, so it is refused.
+func checkReconcileConfig(nc config.Namecheap, gm config.Gmail) error {
+	switch {
+	case !nc.Configured():
+		return fmt.Errorf("namecheap not configured")
+	case gm.Email == "":
+		return fmt.Errorf("gmail not connected")
+	case !nc.PerMailbox():
+		return fmt.Errorf("forwarding mode is catch-all; switch to per-mailbox in settings")
+	}
+	return nil
+}
+
+// mailboxAdder adds a single forwarding rule.
+type mailboxAdder interface {
+	AddForwarding(ctx context.Context, domain, mailbox, forwardTo string) error
+}
+
+// mailboxForwarder adds the forwarding rule of identities saved from the
+// command line in per-mailbox mode, as the TUI does on save, and records
+// each rule in the ledger so reconcile and burn can remove it later.
+type mailboxForwarder struct {
+	client    mailboxAdder
+	domains   []string
+	forwardTo string
+	configs   *zstore.Collection[config.Envelope]
+}
+
+// newMailboxForwarder returns nil unless per-mailbox forwarding is set up.
+func newMailboxForwarder(s *zstore.Store) (*mailboxForwarder, error) {
+	cfgCol, err := zstore.NewCollection[config.Envelope](s, config.Collection)
+	if err != nil {
+		return nil, err
+	}
+	nc := config.Load[config.Namecheap](cfgCol, config.KeyNamecheap)
+	gm := config.Load[config.Gmail](cfgCol, config.KeyGmail)
+	if checkReconcileConfig(nc, gm) != nil {
+		return nil, nil
+	}
+	return &mailboxForwarder{
+		client:    namecheap.NewClient(nc.NamecheapConfig()),
+		domains:   nc.CachedDomains,
+		forwardTo: gm.Email,
+		configs:   cfgCol,
+	}, nil
+}
+
+// add creates the rule for id's mailbox when its domain is managed.
+func (f *mailboxForwarder) add(ctx context.Context, id identity.Identity) error {
+	if f == nil {
+		return nil
+	}
+	mailbox, domain, ok := forwarding.Managed(id.Email, f.domains)
+	if !ok {
+		return nil
+	}
+	if err := f.client.AddForwarding(ctx, domain, mailbox, f.forwardTo); err != nil {
+		return fmt.Errorf("forward %s: %w", id.Email, err)
+	}
+	l := config.Load[forwarding.Ledger](f.configs, config.KeyForwarded)
+	if err := config.Save(f.configs, config.KeyForwarded, l.Add(domain, mailbox)); err != nil {
+		return fmt.Errorf("record forwarding for %s: %w", id.Email, err)
+	}
+	return nil
+}
+
+func printChanges(w io.Writer, changes []forwarding.Change, dryRun bool) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "forwarding in sync")
+		return
+	}
+
+	for _, c := range changes {
+		fmt.Fprintf(w, "  %s\n", c)
+	}
+
+	if dryRun {
+		fmt.Fprintf(w, "%d changes (dry run, nothing applied)\n", len(changes))
+		return
+	}
+	fmt.Fprintf(w, "%d changes applied\n", len(changes))
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/zarlcorp/core/pkg/zstore"
+	"github.com/zarlcorp/zburn/internal/config"
+	"github.com/zarlcorp/zburn/internal/forwarding"
+	"github.com/zarlcorp/zburn/internal/gmail"
+	"github.com/zarlcorp/zburn/internal/identity"
+)
+
+func TestCheckReconcileConfig(t *testing.T) {
+	nc := config.Namecheap{Username: "u", APIKey: "k", ForwardingMode: config.ForwardMailbox}
+	gm := config.Gmail{Email: "me@gmail.com", Token: &gmail.Token{RefreshToken: "r"}}
+
+	if err := checkReconcileConfig(nc, gm); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	catchAll := nc
+	catchAll.ForwardingMode = config.ForwardCatchAll
+	if err := checkReconcileConfig(catchAll, gm); err == nil || !strings.Contains(err.Error(), "catch-all") {
+		t.Errorf("catch-all mode should be refused, got %v", err)
+	}
+
+	if err := checkReconcileConfig(config.Namecheap{}, gm); err == nil {
+		t.Error("missing namecheap should be refused")
+	}
+
+	if err := checkReconcileConfig(nc, config.Gmail{}); err == nil {
+		t.Error("missing gmail should be refused")
+	}
+}
+
+func TestPrintChanges(t *testing.T) {
+	changes := []forwarding.Change{
+		{Action: forwarding.ActionAdd, Domain: "a.test", Mailbox: "alice", ForwardTo: "me@gmail.com"},
+	}
+
+	var buf bytes.Buffer
+	printChanges(&buf, changes, true)
+	out := buf.String()
+	if !strings.Contains(out, "alice@a.test") || !strings.Contains(out, "dry run") {
+		t.Errorf("output = %q", out)
+	}
+
+	buf.Reset()
+	printChanges(&buf, nil, false)
+	if !strings.Contains(buf.String(), "in sync") {
+		t.Errorf("output = %q, want in sync", buf.String())
+	}
+}
+
+type fakeAdder struct {
+	added []string
+	err   error
+}
+
+func (f *fakeAdder) AddForwarding(_ context.Context, domain, mailbox, forwardTo string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.added = append(f.added, mailbox+"@"+domain+">"+forwardTo)
+	return nil
+}
+
+func TestNewMailboxForwarder(t *testing.T) {
+	s, _ := openTestStore(t)
+	cfgCol, err := zstore.NewCollection[config.Envelope](s, config.Collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f, err := newMailboxForwarder(s); err != nil || f != nil {
+		t.Errorf("unconfigured = %v, %v; want nil", f, err)
+	}
+
+	nc := config.Namecheap{Username: "u", APIKey: "k", CachedDomains: []string{"a.test"}, ForwardingMode: config.ForwardMailbox}
+	if err := config.Save(cfgCol, config.KeyNamecheap, nc); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Save(cfgCol, config.KeyGmail, config.Gmail{Email: "me@gmail.com"}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := newMailboxForwarder(s)
+	if err != nil || f == nil || f.forwardTo != "me@gmail.com" {
+		t.Errorf("per-mailbox = %+v, %v", f, err)
+	}
+}
+
+func TestMailboxForwarderAdd(t *testing.T) {
+	s, _ := openTestStore(t)
+	cfgCol, err := zstore.NewCollection[config.Envelope](s, config.Collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &fakeAdder{}
+	f := &mailboxForwarder{client: c, domains: []string{"a.test"}, forwardTo: "me@gmail.com", configs: cfgCol}
+
+	for _, email := range []string{"jane@a.test", "bob@elsewhere.test"} {
+		if err := f.add(context.Background(), identity.Identity{Email: email}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(c.added) != 1 || c.added[0] != "jane@a.test>me@gmail.com" {
+		t.Errorf("added = %v, want only jane@a.test", c.added)
+	}
+	if !config.Load[forwarding.Ledger](cfgCol, config.KeyForwarded).Has("a.test", "jane") {
+		t.Error("added rule should be recorded in the ledger")
+	}
+
+	c.err = fmt.Errorf("api down")
+	if err := f.add(context.Background(), identity.Identity{Email: "carol@a.test"}); err == nil {
+		t.Error("expected error")
+	}
+	if config.Load[forwarding.Ledger](cfgCol, config.KeyForwarded).Has("a.test", "carol") {
+		t.Error("a failed rule should not be recorded")
+	}
+
+	var none *mailboxForwarder
+	if err := none.add(context.Background(), identity.Identity{Email: "jane@a.test"}); err != nil {
+		t.Errorf("nil forwarder should do nothing, got %v", err)
+	}
+}
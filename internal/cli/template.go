@@ -0,0 +1,225 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/zarlcorp/core/pkg/zstore"
+	"github.com/zarlcorp/zburn/internal/identity"
+)
+
+// templateCollection is the store collection holding identity templates.
+const templateCollection = "templates"
+
+const templateUsage = `usage: zburn template <command> [flags]
+
+commands:
+  add   <name> --field <spec> [--field <spec>...]
+  list  [--json]
+  show  <name> [--json]
+  rm    <name>
+
+field specs:
+  name=value             fixed value
+  name:generator         generated value, e.g. handle:username
+  name:choice=a,b,c      one of the listed values
+
+generators: %s`
+
+// CmdTemplate dispatches the template subcommands.
+func CmdTemplate(args []string) {
+	usage := fmt.Sprintf(templateUsage, strings.Join(identity.FieldGenerators(), ", "))
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	sub, rest := args[0], args[1:]
+	switch sub {
+	case "add":
+		cmdTemplateAdd(rest)
+	case "list", "ls":
+		cmdTemplateList(rest)
+	case "show":
+		cmdTemplateShow(rest)
+	case "rm":
+		cmdTemplateRemove(rest)
+	default:
+		fmt.Fprintf(os.Stderr, "zburn: unknown template command %q\n\n%s\n", sub, usage)
+		os.Exit(1)
+	}
+}
+
+// openTemplates opens the store and its template collection.
+func openTemplates() (*zstore.Store, *zstore.Collection[identity.Template], error) {
+	s, _, err := OpenStore(DataDir())
+	if err != nil {
+		return nil, nil, err
+	}
+	col, err := zstore.NewCollection[identity.Template](s, templateCollection)
+	if err != nil {
+		s.Close()
+		return nil, nil, err
+	}
+	return s, col, nil
+}
+
+// loadTemplate reads a saved template by name.
+func loadTemplate(s *zstore.Store, name string) (identity.Template, error) {
+	col, err := zstore.NewCollection[identity.Template](s, templateCollection)
+	if err != nil {
+		return identity.Template{}, err
+	}
+	t, err := col.Get(identity.TemplateKey(name))
+	if errors.Is(err, zstore.ErrNotFound) {
+		return identity.Template{}, fmt.Errorf("no template named %q", name)
+	}
+	return t, err
+}
+
+// parseTemplate builds a template from the add command's arguments.
+func parseTemplate(args []string) (identity.Template, error) {
+	t := identity.Template{Name: strings.TrimSpace(firstArg(args, "--field"))}
+	for _, spec := range flagValues(args, "--field") {
+		f, err := identity.ParseTemplateField(spec)
+		if err != nil {
+			return identity.Template{}, err
+		}
+		t.Fields = append(t.Fields, f)
+	}
+	if err := t.Validate(); err != nil {
+		return identity.Template{}, err
+	}
+	return t, nil
+}
+
+func cmdTemplateAdd(args []string) {
+	t, err := parseTemplate(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: template add: %v\n", err)
+		os.Exit(1)
+	}
+
+	s, col, err := openTemplates()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+		os.Exit(1)
+	}
+	defer s.Close()
+
+	if err := col.Put(identity.TemplateKey(t.Name), t); err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: template add: %v\n", err)
+		os.Exit(1)
+	}
+
+	if hasFlag(args, "--json") {
+		printJSON(t)
+		return
+	}
+	printTemplate(os.Stdout, t)
+}
+
+func cmdTemplateList(args []string) {
+	s, col, err := openTemplates()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+		os.Exit(1)
+	}
+	defer s.Close()
+
+	tpls, err := col.List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: template list: %v\n", err)
+		os.Exit(1)
+	}
+	sort.Slice(tpls, func(i, j int) bool {
+		return strings.ToLower(tpls[i].Name) < strings.ToLower(tpls[j].Name)
+	})
+
+	if hasFlag(args, "--json") {
+		printJSON(tpls)
+		return
+	}
+
+	if len(tpls) == 0 {
+		fmt.Println("no saved templates")
+		return
+	}
+
+	for _, t := range tpls {
+		names := make([]string, len(t.Fields))
+		for i, f := range t.Fields {
+			names[i] = f.Name
+		}
+		fmt.Printf("  %-20s %s\n", truncateField(t.Name, 20), strings.Join(names, ", "))
+	}
+}
+
+func cmdTemplateShow(args []string) {
+	name := firstArg(args)
+	if name == "" {
+		fmt.Fprintln(os.Stderr, "usage: zburn template show <name> [--json]")
+		os.Exit(1)
+	}
+
+	s, _, err := openTemplates()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+		os.Exit(1)
+	}
+	defer s.Close()
+
+	t, err := loadTemplate(s, name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: template show: %v\n", err)
+		os.Exit(1)
+	}
+
+	if hasFlag(args, "--json") {
+		printJSON(t)
+		return
+	}
+	printTemplate(os.Stdout, t)
+}
+
+func cmdTemplateRemove(args []string) {
+	name := firstArg(args)
+	if name == "" {
+		fmt.Fprintln(os.Stderr, "usage: zburn template rm <name>")
+		os.Exit(1)
+	}
+
+	s, col, err := openTemplates()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+		os.Exit(1)
+	}
+	defer s.Close()
+
+	if err := col.Delete(identity.TemplateKey(name)); err != nil {
+		if errors.Is(err, zstore.ErrNotFound) {
+			err = fmt.Errorf("no template named %q", name)
+		}
+		fmt.Fprintf(os.Stderr, "zburn: template rm: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("deleted %s\n", name)
+}
+
+func printTemplate(w io.Writer, t identity.Template) {
+	fmt.Fprintf(w, "  template: %s\n", t.Name)
+	for _, f := range t.Fields {
+		switch {
+		case len(f.Choices) > 0:
+			fmt.Fprintf(w, "  %-20s one of %s\n", f.Name, strings.Join(f.Choices, ", "))
+		case f.Generator != "":
+			fmt.Fprintf(w, "  %-20s <%s>\n", f.Name, f.Generator)
+		default:
+			fmt.Fprintf(w, "  %-20s %s\n", f.Name, f.Value)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/zarlcorp/core/pkg/zstore"
+	"github.com/zarlcorp/zburn/internal/identity"
+)
+
+func TestFlagValues(t *testing.T) {
+	args := []string{"name", "--field", "a=1", "--json", "--field=b:colour", "--field"}
+	got := flagValues(args, "--field")
+	if strings.Join(got, "|") != "a=1|b:colour" {
+		t.Errorf("flagValues = %v", got)
+	}
+}
+
+func TestParseTemplate(t *testing.T) {
+	tpl, err := parseTemplate([]string{"--field", "handle:username", "signup", "--field", "source=qa"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tpl.Name != "signup" || len(tpl.Fields) != 2 || tpl.Fields[0].Generator != "username" || tpl.Fields[1].Value != "qa" {
+		t.Errorf("template = %+v", tpl)
+	}
+
+	if _, err := parseTemplate([]string{"signup", "--field", "x:nope"}); err == nil {
+		t.Error("unknown generator should be an error")
+	}
+	if _, err := parseTemplate([]string{"--field", "a=1"}); err == nil {
+		t.Error("missing name should be an error")
+	}
+}
+
+func TestLoadTemplate(t *testing.T) {
+	s, _ := openTestStore(t)
+	col, err := zstore.NewCollection[identity.Template](s, templateCollection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tpl := identity.Template{Name: "Signup", Fields: []identity.TemplateField{{Name: "source", Value: "qa"}}}
+	if err := col.Put(identity.TemplateKey(tpl.Name), tpl); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := loadTemplate(s, "signup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "Signup" {
+		t.Errorf("loaded %+v", got)
+	}
+
+	if _, err := loadTemplate(s, "missing"); err == nil || !strings.Contains(err.Error(), "no template") {
+		t.Errorf("missing template error = %v", err)
+	}
+}
+
+func TestIdentityOutputTemplateFields(t *testing.T) {
+	id := identity.Identity{ID: "a1", Fields: []identity.Field{{Name: "company", Value: "Bluepeak Labs"}}}
+
+	var text bytes.Buffer
+	printIdentity(&text, id)
+	if !strings.Contains(text.String(), "  company:  Bluepeak Labs\n") {
+		t.Errorf("text output missing template field:\n%s", text.String())
+	}
+
+	var buf bytes.Buffer
+	enc := newIdentityEncoder(&buf, formatCSV)
+	enc.Encode(id)
+	enc.Flush()
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := len(rows[0]) - 1
+	if rows[0][last] != "company" || rows[1][last] != "Bluepeak Labs" {
+		t.Errorf("csv should end with the template column: %v", rows)
+	}
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/zarlcorp/zburn/internal/credential"
+)
+
+// TOTPResult is the code printed by the totp command.
+type TOTPResult struct {
+	Code             string `json:"code"`
+	RemainingSeconds int    `json:"remaining_seconds"`
+	Issuer           string `json:"issuer,omitempty"`
+	Algorithm        string `json:"algorithm"`
+	Digits           int    `json:"digits"`
+	Period           int    `json:"period"`
+}
+
+// CmdTOTP prints the current TOTP code for a saved credential.
+func CmdTOTP(args []string) {
+	credID := firstArg(args)
+	if credID == "" {
+		fmt.Fprintln(os.Stderr, "usage: zburn totp <credential-id> [--json]")
+		os.Exit(1)
+	}
+
+	v, err := openCredVault()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: %v\n", err)
+		os.Exit(1)
+	}
+	defer v.store.Close()
+
+	c, err := v.credentials.Get(credID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: totp: %v\n", err)
+		os.Exit(1)
+	}
+
+	res, err := totpResult(c, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zburn: totp: %v\n", err)
+		os.Exit(1)
+	}
+
+	if hasFlag(args, "--json") {
+		printJSON(res)
+		return
+	}
+	printTOTP(os.Stdout, res)
+}
+
+// totpResult computes the code for c at time t.
+func totpResult(c credential.Credential, t time.Time) (TOTPResult, error) {
+	if c.TOTPSecret == "" {
+		return TOTPResult{}, fmt.Errorf("credential %s has no totp secret", c.ID)
+	}
+
+	p := c.TOTP().Normalized()
+	code, err := p.Code(t)
+	if err != nil {
+		return TOTPResult{}, err
+	}
+
+	return TOTPResult{
+		Code:             code,
+		RemainingSeconds: int(p.Remaining(t) / time.Second),
+		Issuer:           p.Issuer,
+		Algorithm:        p.Algorithm,
+		Digits:           p.Digits,
+		Period:           p.Period,
+	}, nil
+}
+
+func printTOTP(w io.Writer, res TOTPResult) {
+	fmt.Fprintf(w, "%s  (%ds remaining)\n", res.Code, res.RemainingSeconds)
+}
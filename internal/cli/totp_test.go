@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/zarlcorp/zburn/internal/credential"
+)
+
+func TestTOTPResult(t *testing.T) {
+	// RFC 6238 SHA1 seed "12345678901234567890"
+	c := credential.Credential{ID: "c1", TOTPSecret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", TOTPIssuer: "Acme", TOTPDigits: 8}
+
+	res, err := totpResult(c, time.Unix(59, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := TOTPResult{Code: "94287082", RemainingSeconds: 1, Issuer: "Acme", Algorithm: "SHA1", Digits: 8, Period: 30}
+	if res != want {
+		t.Errorf("result = %+v, want %+v", res, want)
+	}
+
+	var buf bytes.Buffer
+	printTOTP(&buf, res)
+	if got := buf.String(); got != "94287082  (1s remaining)\n" {
+		t.Errorf("output = %q", got)
+	}
+}
+
+func TestTOTPResultNoSecret(t *testing.T) {
+	if _, err := totpResult(credential.Credential{ID: "c1"}, time.Now()); err == nil {
+		t.Fatal("expected error for credential without totp")
+	}
+}
@@ -0,0 +1,208 @@
+// Package config defines zburn's provider settings and how they are
+// persisted in the encrypted "config" collection.
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/zarlcorp/core/pkg/zstore"
+	"github.com/zarlcorp/zburn/internal/forwarding"
+	"github.com/zarlcorp/zburn/internal/gmail"
+	"github.com/zarlcorp/zburn/internal/namecheap"
+	"github.com/zarlcorp/zburn/internal/twilio"
+)
+
+// Collection is the name of the zstore collection holding settings.
+const Collection = "config"
+
+// Keys for each provider's settings within the collection.
+const (
+	KeyNamecheap = "namecheap"
+	KeyGmail     = "gmail"
+	KeyTwilio    = "twilio"
+	KeyLock      = "lock"
+
+	// KeyForwarded holds the forwarding.Ledger of rules zburn created.
+	KeyForwarded = "forwarded"
+)
+
+// DefaultIdleTimeout is how long the TUI may sit idle before it locks,
+// unless configured otherwise.
+const DefaultIdleTimeout = 15 * time.Minute
+
+// Forwarding modes for Namecheap domains.
+const (
+	ForwardCatchAll = ""        // a single "*" rule per domain
+	ForwardMailbox  = "mailbox" // one rule per saved identity
+)
+
+// tokenRefreshMargin is how close to expiry an access token may get before
+// it is refreshed ahead of an API call.
+const tokenRefreshMargin = time.Minute
+
+// Envelope wraps a JSON-encoded config value so we can store
+// heterogeneous config types in a single zstore collection.
+type Envelope struct {
+	Data json.RawMessage `json:"data"`
+}
+
+// Namecheap holds Namecheap credentials and cached domain list.
+type Namecheap struct {
+	Username       string   `json:"username"`
+	APIKey         string   `json:"api_key"`
+	CachedDomains  []string `json:"cached_domains"`
+	ForwardingMode string   `json:"forwarding_mode,omitempty"`
+}
+
+// Gmail holds Gmail OAuth2 credentials and tokens.
+type Gmail struct {
+	ClientID     string       `json:"client_id"`
+	ClientSecret string       `json:"client_secret"`
+	Token        *gmail.Token `json:"token,omitempty"`
+	Email        string       `json:"email,omitempty"`
+}
+
+// Twilio holds Twilio credentials and preferred countries.
+type Twilio struct {
+	AccountSID         string   `json:"account_sid"`
+	AuthToken          string   `json:"auth_token"`
+	PreferredCountries []string `json:"preferred_countries"`
+}
+
+// Lock holds the TUI auto-lock setting.
+type Lock struct {
+	// IdleTimeout is a duration such as "5m", or "off". Empty means
+	// DefaultIdleTimeout.
+	IdleTimeout string `json:"idle_timeout,omitempty"`
+}
+
+// LockAfter returns the setting that locks after d idle; 0 disables
+// auto-lock.
+func LockAfter(d time.Duration) Lock {
+	if d <= 0 {
+		return Lock{IdleTimeout: "off"}
+	}
+	return Lock{IdleTimeout: d.String()}
+}
+
+// Timeout returns the idle timeout, or 0 when auto-lock is off. Invalid
+// values fall back to DefaultIdleTimeout rather than disabling the lock.
+func (s Lock) Timeout() time.Duration {
+	if s.IdleTimeout == "off" {
+		return 0
+	}
+	d, err := time.ParseDuration(s.IdleTimeout)
+	if err != nil || d <= 0 {
+		return DefaultIdleTimeout
+	}
+	return d
+}
+
+func (s Namecheap) Configured() bool {
+	return s.Username != "" && s.APIKey != ""
+}
+
+// PerMailbox reports whether identities get their own forwarding rule.
+func (s Namecheap) PerMailbox() bool {
+	return s.ForwardingMode == ForwardMailbox
+}
+
+func (s Gmail) Configured() bool {
+	return s.Token != nil && s.Token.RefreshToken != "" && s.Email != ""
+}
+
+func (s Twilio) Configured() bool {
+	return s.AccountSID != "" && s.AuthToken != ""
+}
+
+// NamecheapConfig converts settings to a namecheap.Config for API use.
+func (s Namecheap) NamecheapConfig() namecheap.Config {
+	return namecheap.Config{
+		Username: s.Username,
+		APIKey:   s.APIKey,
+	}
+}
+
+// OAuthConfig converts settings to a gmail.OAuthConfig for API use.
+func (s Gmail) OAuthConfig() gmail.OAuthConfig {
+	return gmail.OAuthConfig{
+		ClientID:     s.ClientID,
+		ClientSecret: s.ClientSecret,
+	}
+}
+
+// AccessToken returns a usable token for the configured account. The bool
+// result reports whether the token was refreshed and should be saved.
+func (s Gmail) AccessToken(ctx context.Context) (*gmail.Token, bool, error) {
+	if s.Token == nil {
+		return nil, false, fmt.Errorf("gmail not connected")
+	}
+
+	if s.Token.AccessToken != "" && time.Until(s.Token.Expiry) > tokenRefreshMargin {
+		return s.Token, false, nil
+	}
+
+	tok, err := gmail.RefreshToken(ctx, s.OAuthConfig(), s.Token.RefreshToken)
+	if err != nil {
+		return nil, false, err
+	}
+	return tok, true, nil
+}
+
+// TwilioConfig converts settings to a twilio.Config for API use.
+func (s Twilio) TwilioConfig() twilio.Config {
+	return twilio.Config{
+		AccountSID: s.AccountSID,
+		AuthToken:  s.AuthToken,
+	}
+}
+
+// Load reads a typed config from the envelope collection.
+// Missing or unreadable configs return the zero value (unconfigured).
+func Load[T any](col *zstore.Collection[Envelope], key string) T {
+	var zero T
+	if col == nil {
+		return zero
+	}
+
+	env, err := col.Get(key)
+	if err != nil {
+		return zero
+	}
+
+	var v T
+	if err := json.Unmarshal(env.Data, &v); err != nil {
+		return zero
+	}
+
+	return v
+}
+
+// Save persists a typed config into the envelope collection.
+func Save[T any](col *zstore.Collection[Envelope], key string, v T) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal config: %w", err)
+	}
+
+	return col.Put(key, Envelope{Data: data})
+}
+
+// Forwarded is the forwarding.Ledger stored under KeyForwarded in a
+// collection.
+type Forwarded struct {
+	Col *zstore.Collection[Envelope]
+}
+
+// Forget drops mailbox on domain from the stored ledger once its rule has
+// been removed.
+func (f Forwarded) Forget(domain, mailbox string) error {
+	l := Load[forwarding.Ledger](f.Col, KeyForwarded)
+	if !l.Has(domain, mailbox) {
+		return nil
+	}
+	return Save(f.Col, KeyForwarded, l.Remove(domain, mailbox))
+}
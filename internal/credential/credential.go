@@ -1,6 +1,14 @@
 package credential
 
-import "time"
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zarlcorp/zburn/internal/otp"
+)
 
 // Credential holds login data linked to a generated identity.
 type Credential struct {
@@ -14,4 +22,72 @@ type Credential struct {
 	Notes      string    `json:"notes,omitempty"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
+
+	// TOTP parameters from an otpauth URI; zero values mean the defaults
+	// (SHA1, 6 digits, 30 seconds).
+	TOTPIssuer    string `json:"totp_issuer,omitempty"`
+	TOTPAlgorithm string `json:"totp_algorithm,omitempty"`
+	TOTPDigits    int    `json:"totp_digits,omitempty"`
+	TOTPPeriod    int    `json:"totp_period,omitempty"`
+}
+
+// TOTP returns the credential's TOTP configuration.
+func (c Credential) TOTP() otp.Params {
+	return otp.Params{
+		Secret:    c.TOTPSecret,
+		Issuer:    c.TOTPIssuer,
+		Account:   c.Username,
+		Algorithm: c.TOTPAlgorithm,
+		Digits:    c.TOTPDigits,
+		Period:    c.TOTPPeriod,
+	}
+}
+
+// SetTOTP sets the TOTP configuration from a raw base32 secret or an
+// otpauth:// URI. A raw secret resets the parameters to the defaults, and an
+// empty input clears TOTP.
+func (c *Credential) SetTOTP(input string) error {
+	input = strings.TrimSpace(input)
+
+	var p otp.Params
+	switch {
+	case input == "":
+	case otp.IsURI(input):
+		parsed, err := otp.ParseURI(input)
+		if err != nil {
+			return err
+		}
+		p = parsed
+	default:
+		if !ValidTOTPSecret(input) {
+			return fmt.Errorf("invalid totp secret (must be base32)")
+		}
+		p.Secret = input
+	}
+
+	c.TOTPSecret = p.Secret
+	c.TOTPIssuer = p.Issuer
+	c.TOTPAlgorithm = p.Algorithm
+	c.TOTPDigits = p.Digits
+	c.TOTPPeriod = p.Period
+	return nil
+}
+
+// NewID returns a random 8-character hex credential ID.
+func NewID() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		panic("crypto/rand: " + err.Error())
+	}
+	return hex.EncodeToString(b)
+}
+
+// ValidTOTPSecret reports whether s is a secret otp can decode. An empty
+// secret is valid, since it clears TOTP.
+func ValidTOTPSecret(s string) bool {
+	if strings.TrimSpace(s) == "" {
+		return true
+	}
+	_, err := otp.DecodeSecret(s)
+	return err == nil
 }
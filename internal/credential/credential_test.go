@@ -196,3 +196,77 @@ func assertCredentialEqual(t *testing.T, want, got Credential) {
 		}
 	}
 }
+
+func TestNewID(t *testing.T) {
+	seen := make(map[string]bool)
+	for range 100 {
+		id := NewID()
+		if len(id) != 8 {
+			t.Fatalf("id length = %d, want 8", len(id))
+		}
+		if seen[id] {
+			t.Fatalf("duplicate id: %s", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestValidTOTPSecret(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"JBSWY3DPEHPK3PXP", true},
+		{"jbswy3dpehpk3pxp", true},
+		{"JBSW Y3DP EHPK 3PXP", true},
+		{"!!!invalid!!!", false},
+		{"", true},
+	}
+
+	for _, tt := range tests {
+		if got := ValidTOTPSecret(tt.input); got != tt.want {
+			t.Errorf("ValidTOTPSecret(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestSetTOTP(t *testing.T) {
+	var c Credential
+	uri := "otpauth://totp/Acme:jane?secret=JBSWY3DPEHPK3PXP&issuer=Acme&digits=8&period=60"
+	if err := c.SetTOTP(uri); err != nil {
+		t.Fatal(err)
+	}
+	if c.TOTPSecret != "JBSWY3DPEHPK3PXP" || c.TOTPIssuer != "Acme" || c.TOTPDigits != 8 || c.TOTPPeriod != 60 {
+		t.Errorf("after uri: %+v", c)
+	}
+
+	// a raw secret resets the uri parameters
+	if err := c.SetTOTP("GEZDGNBVGY3TQOJQ"); err != nil {
+		t.Fatal(err)
+	}
+	if c.TOTPSecret != "GEZDGNBVGY3TQOJQ" || c.TOTPIssuer != "" || c.TOTPDigits != 0 || c.TOTPPeriod != 0 {
+		t.Errorf("after secret: %+v", c)
+	}
+
+	if err := c.SetTOTP("!!!"); err == nil {
+		t.Error("expected error for invalid secret")
+	}
+	if c.TOTPSecret != "GEZDGNBVGY3TQOJQ" {
+		t.Error("failed SetTOTP should leave the credential unchanged")
+	}
+
+	if err := c.SetTOTP(""); err != nil {
+		t.Fatal(err)
+	}
+	if c.TOTPSecret != "" {
+		t.Errorf("empty input should clear totp, got %q", c.TOTPSecret)
+	}
+}
+
+func TestTOTPAccount(t *testing.T) {
+	c := Credential{Username: "jane", TOTPSecret: "JBSWY3DPEHPK3PXP", TOTPDigits: 8}
+	p := c.TOTP()
+	if p.Account != "jane" || p.Digits != 8 {
+		t.Errorf("TOTP() = %+v", p)
+	}
+}
@@ -0,0 +1,134 @@
+package exporter
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// Bitwarden item types.
+const (
+	bitwardenTypeLogin    = 1
+	bitwardenTypeIdentity = 4
+)
+
+type bitwardenExport struct {
+	Encrypted bool              `json:"encrypted"`
+	Folders   []bitwardenFolder `json:"folders"`
+	Items     []bitwardenItem   `json:"items"`
+}
+
+type bitwardenFolder struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type bitwardenItem struct {
+	ID       string                 `json:"id"`
+	FolderID string                 `json:"folderId,omitempty"`
+	Type     int                    `json:"type"`
+	Name     string                 `json:"name"`
+	Notes    string                 `json:"notes,omitempty"`
+	Favorite bool                   `json:"favorite"`
+	Login    *bitwardenLoginData    `json:"login,omitempty"`
+	Identity *bitwardenIdentityData `json:"identity,omitempty"`
+}
+
+type bitwardenLoginData struct {
+	Username string         `json:"username,omitempty"`
+	Password string         `json:"password,omitempty"`
+	TOTP     string         `json:"totp,omitempty"`
+	URIs     []bitwardenURI `json:"uris,omitempty"`
+}
+
+type bitwardenURI struct {
+	Match *int   `json:"match"`
+	URI   string `json:"uri"`
+}
+
+type bitwardenIdentityData struct {
+	FirstName  string `json:"firstName,omitempty"`
+	LastName   string `json:"lastName,omitempty"`
+	Email      string `json:"email,omitempty"`
+	Phone      string `json:"phone,omitempty"`
+	Address1   string `json:"address1,omitempty"`
+	City       string `json:"city,omitempty"`
+	State      string `json:"state,omitempty"`
+	PostalCode string `json:"postalCode,omitempty"`
+	Country    string `json:"country,omitempty"`
+	Username   string `json:"username,omitempty"`
+	Company    string `json:"company,omitempty"`
+}
+
+// writeBitwarden puts each identity in its own folder, holding a Bitwarden
+// identity item for the persona and a login item per credential.
+func writeBitwarden(w io.Writer, v Vault) error {
+	exp := bitwardenExport{Folders: []bitwardenFolder{}, Items: []bitwardenItem{}}
+
+	for _, g := range groups(v) {
+		folder := bitwardenFolder{ID: uuidString(newUUID()), Name: g.name()}
+		exp.Folders = append(exp.Folders, folder)
+
+		if id := g.identity; id != nil {
+			exp.Items = append(exp.Items, bitwardenItem{
+				ID:       uuidString(newUUID()),
+				FolderID: folder.ID,
+				Type:     bitwardenTypeIdentity,
+				Name:     g.name(),
+				Notes:    identityNotes(id.Label, id.Tags, id.Notes),
+				Identity: &bitwardenIdentityData{
+					FirstName:  id.FirstName,
+					LastName:   id.LastName,
+					Email:      id.Email,
+					Phone:      id.Phone,
+					Address1:   id.Street,
+					City:       id.City,
+					State:      id.State,
+					PostalCode: id.Zip,
+					Country:    id.Country,
+					Username:   id.Username,
+					Company:    id.Company,
+				},
+			})
+		}
+
+		for _, c := range g.credentials {
+			login := &bitwardenLoginData{
+				Username: c.Username,
+				Password: c.Password,
+				TOTP:     totpURI(c),
+			}
+			if c.URL != "" {
+				login.URIs = []bitwardenURI{{URI: c.URL}}
+			}
+			exp.Items = append(exp.Items, bitwardenItem{
+				ID:       uuidString(newUUID()),
+				FolderID: folder.ID,
+				Type:     bitwardenTypeLogin,
+				Name:     c.Label,
+				Notes:    c.Notes,
+				Login:    login,
+			})
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(exp)
+}
+
+// identityNotes keeps an identity's label, tags and notes in formats that
+// have no fields for them.
+func identityNotes(label string, tags []string, notes string) string {
+	var lines []string
+	if label != "" {
+		lines = append(lines, "label: "+label)
+	}
+	if len(tags) > 0 {
+		lines = append(lines, "tags: "+strings.Join(tags, ", "))
+	}
+	if notes != "" {
+		lines = append(lines, notes)
+	}
+	return strings.Join(lines, "\n")
+}
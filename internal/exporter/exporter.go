@@ -0,0 +1,141 @@
+// Package exporter writes identities and their credentials in formats
+// other password managers import.
+//
+// Exports are plain text and hold every password; callers are expected to
+// warn about that and to write them with restrictive permissions.
+package exporter
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zarlcorp/zburn/internal/credential"
+	"github.com/zarlcorp/zburn/internal/identity"
+)
+
+// Format is a supported export format.
+type Format string
+
+const (
+	Zburn      Format = "zburn"       // zburn's own JSON schema
+	Bitwarden  Format = "bitwarden"   // Bitwarden unencrypted JSON
+	KeePassCSV Format = "keepass-csv" // KeePassXC CSV
+	KeePassXML Format = "keepass-xml" // KeePass 2 XML
+)
+
+// Formats lists the supported formats in the order they are documented.
+var Formats = []Format{Zburn, Bitwarden, KeePassCSV, KeePassXML}
+
+// ParseFormat validates a --format value.
+func ParseFormat(s string) (Format, error) {
+	f := Format(strings.ToLower(strings.TrimSpace(s)))
+	if !slices.Contains(Formats, f) {
+		return "", fmt.Errorf("unknown format %q", s)
+	}
+	return f, nil
+}
+
+// Vault is the data to export.
+type Vault struct {
+	Identities  []identity.Identity
+	Credentials []credential.Credential
+}
+
+// Write writes v to w in format f. now stamps the export.
+func Write(w io.Writer, f Format, v Vault, now time.Time) error {
+	switch f {
+	case Zburn:
+		return writeZburn(w, v, now)
+	case Bitwarden:
+		return writeBitwarden(w, v)
+	case KeePassCSV:
+		return writeKeePassCSV(w, v)
+	case KeePassXML:
+		return writeKeePassXML(w, v)
+	}
+	return fmt.Errorf("unknown format %q", f)
+}
+
+// group is an identity and its credentials. Credentials whose identity is
+// not in the export form a group with a nil identity.
+type group struct {
+	identity    *identity.Identity
+	credentials []credential.Credential
+}
+
+// name labels a group for folder-based formats.
+func (g group) name() string {
+	if g.identity == nil {
+		return "unassigned"
+	}
+	name := strings.TrimSpace(g.identity.FirstName + " " + g.identity.LastName)
+	if g.identity.Email != "" {
+		name += " <" + g.identity.Email + ">"
+	}
+	return name
+}
+
+// groups pairs each identity with its credentials, sorted by label.
+func groups(v Vault) []group {
+	byID := make(map[string][]credential.Credential)
+	for _, c := range v.Credentials {
+		byID[c.IdentityID] = append(byID[c.IdentityID], c)
+	}
+
+	var out []group
+	for i := range v.Identities {
+		id := &v.Identities[i]
+		out = append(out, group{identity: id, credentials: sortByLabel(byID[id.ID])})
+		delete(byID, id.ID)
+	}
+
+	var orphans []credential.Credential
+	for _, creds := range byID {
+		orphans = append(orphans, creds...)
+	}
+	if len(orphans) > 0 {
+		out = append(out, group{credentials: sortByLabel(orphans)})
+	}
+	return out
+}
+
+func sortByLabel(creds []credential.Credential) []credential.Credential {
+	slices.SortStableFunc(creds, func(a, b credential.Credential) int {
+		return strings.Compare(strings.ToLower(a.Label), strings.ToLower(b.Label))
+	})
+	return creds
+}
+
+// totpURI encodes a credential's TOTP as an otpauth URI, or "" without
+// one. The label stands in for a missing issuer so authenticator apps can
+// name the entry.
+func totpURI(c credential.Credential) string {
+	if c.TOTPSecret == "" {
+		return ""
+	}
+	p := c.TOTP()
+	p.Secret = strings.ToUpper(strings.ReplaceAll(p.Secret, " ", ""))
+	if p.Issuer == "" {
+		p.Issuer = c.Label
+	}
+	return p.URI()
+}
+
+// newUUID returns a random RFC 4122 version 4 UUID as 16 bytes.
+func newUUID() [16]byte {
+	var u [16]byte
+	if _, err := rand.Read(u[:]); err != nil {
+		panic("crypto/rand: " + err.Error())
+	}
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return u
+}
+
+func uuidString(u [16]byte) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
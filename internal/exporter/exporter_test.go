@@ -0,0 +1,228 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zarlcorp/zburn/internal/credential"
+	"github.com/zarlcorp/zburn/internal/identity"
+	"github.com/zarlcorp/zburn/internal/importer"
+)
+
+var exportNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func testVault() Vault {
+	return Vault{
+		Identities: []identity.Identity{
+			{ID: "jane", FirstName: "Jane", LastName: "Doe", Email: "jane@zburn.id", Label: "shopping", Tags: []string{"work"}},
+			{ID: "empty", FirstName: "Max", LastName: "Roe", Email: "max@zburn.id"},
+		},
+		Credentials: []credential.Credential{
+			{ID: "c2", IdentityID: "jane", Label: "Forum", Username: "janed", Password: "pw", CreatedAt: exportNow, UpdatedAt: exportNow},
+			{ID: "c1", IdentityID: "jane", Label: "GitHub", URL: "https://github.com", Username: "jane@zburn.id", Password: "s3cret", TOTPSecret: "jbsw y3dp ehpk 3pxp", Notes: "work account", CreatedAt: exportNow, UpdatedAt: exportNow},
+			{ID: "c3", IdentityID: "gone", Label: "Orphan", Username: "old", Password: "pw2", CreatedAt: exportNow, UpdatedAt: exportNow},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Format
+		wantErr bool
+	}{
+		{"zburn", Zburn, false},
+		{"Bitwarden", Bitwarden, false},
+		{" keepass-csv ", KeePassCSV, false},
+		{"keepass-xml", KeePassXML, false},
+		{"keepass", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseFormat(%q) = %q, %v", tt.in, got, err)
+		}
+	}
+}
+
+func TestGroups(t *testing.T) {
+	gs := groups(testVault())
+	if len(gs) != 3 {
+		t.Fatalf("groups = %d, want 3", len(gs))
+	}
+	if gs[0].name() != "Jane Doe <jane@zburn.id>" {
+		t.Errorf("name = %q", gs[0].name())
+	}
+	if gs[0].credentials[0].Label != "Forum" || gs[0].credentials[1].Label != "GitHub" {
+		t.Errorf("credentials not sorted by label: %+v", gs[0].credentials)
+	}
+	if len(gs[1].credentials) != 0 {
+		t.Errorf("identity without credentials has %d", len(gs[1].credentials))
+	}
+	if gs[2].identity != nil || gs[2].name() != "unassigned" || gs[2].credentials[0].ID != "c3" {
+		t.Errorf("orphan group = %+v", gs[2])
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	tests := []struct {
+		name string
+		cred credential.Credential
+		want string
+	}{
+		{"none", credential.Credential{Label: "GitHub"}, ""},
+		{
+			"label as issuer",
+			credential.Credential{Label: "GitHub", Username: "jane", TOTPSecret: "jbsw y3dp ehpk 3pxp"},
+			"otpauth://totp/GitHub:jane?issuer=GitHub&secret=JBSWY3DPEHPK3PXP",
+		},
+		{
+			"issuer and params",
+			credential.Credential{Label: "gh", Username: "jane", TOTPSecret: "JBSWY3DPEHPK3PXP", TOTPIssuer: "GitHub", TOTPDigits: 8},
+			"otpauth://totp/GitHub:jane?digits=8&issuer=GitHub&secret=JBSWY3DPEHPK3PXP",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := totpURI(tt.cred); got != tt.want {
+				t.Errorf("totpURI = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteZburn(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, Zburn, testVault(), exportNow); err != nil {
+		t.Fatal(err)
+	}
+
+	var got zburnExport
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Format != "zburn" || got.Version != zburnVersion || !got.ExportedAt.Equal(exportNow) {
+		t.Errorf("header = %q %d %v", got.Format, got.Version, got.ExportedAt)
+	}
+	if len(got.Identities) != 2 || got.Identities[0].Email != "jane@zburn.id" || len(got.Identities[0].Credentials) != 2 {
+		t.Fatalf("identities = %+v", got.Identities)
+	}
+	gh := got.Identities[0].Credentials[1]
+	if gh.Password != "s3cret" || !strings.HasPrefix(gh.TOTP, "otpauth://totp/") {
+		t.Errorf("credential = %+v", gh)
+	}
+	if len(got.Unassigned) != 1 || got.Unassigned[0].Label != "Orphan" {
+		t.Errorf("unassigned = %+v", got.Unassigned)
+	}
+	if strings.Contains(buf.String(), "totp_secret") {
+		t.Error("raw totp_secret field exported")
+	}
+}
+
+// TestWriteRoundTrip checks the formats zburn imports read back the
+// exported logins.
+func TestWriteRoundTrip(t *testing.T) {
+	for _, tt := range []struct {
+		format Format
+		parse  importer.Format
+	}{
+		{Bitwarden, importer.Bitwarden},
+		{KeePassXML, importer.KeePass},
+	} {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, tt.format, testVault(), exportNow); err != nil {
+				t.Fatal(err)
+			}
+			entries, _, err := importer.Parse(tt.parse, buf.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 3 {
+				t.Fatalf("entries = %d, want 3", len(entries))
+			}
+			want := importer.Entry{
+				Title:    "GitHub",
+				URL:      "https://github.com",
+				Username: "jane@zburn.id",
+				Password: "s3cret",
+				TOTP:     "otpauth://totp/GitHub:jane@zburn.id?issuer=GitHub&secret=JBSWY3DPEHPK3PXP",
+				Notes:    "work account",
+			}
+			if entries[1] != want {
+				t.Errorf("entry = %+v, want %+v", entries[1], want)
+			}
+		})
+	}
+}
+
+func TestWriteBitwardenIdentity(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, Bitwarden, testVault(), exportNow); err != nil {
+		t.Fatal(err)
+	}
+	var got bitwardenExport
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Folders) != 3 {
+		t.Errorf("folders = %d, want 3", len(got.Folders))
+	}
+	item := got.Items[0]
+	if item.Type != bitwardenTypeIdentity || item.Identity.Email != "jane@zburn.id" || item.FolderID != got.Folders[0].ID {
+		t.Errorf("identity item = %+v", item)
+	}
+	if item.Notes != "label: shopping\ntags: work" {
+		t.Errorf("notes = %q", item.Notes)
+	}
+}
+
+func TestWriteKeePassCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, KeePassCSV, testVault(), exportNow); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("rows = %d, want header and 3", len(rows))
+	}
+	if strings.Join(rows[0], ",") != strings.Join(keePassCSVHeader, ",") {
+		t.Errorf("header = %v", rows[0])
+	}
+	gh := rows[2]
+	if gh[0] != "zburn/Jane Doe <jane@zburn.id>" || gh[1] != "GitHub" || gh[3] != "s3cret" || !strings.HasPrefix(gh[6], "otpauth://") {
+		t.Errorf("row = %v", gh)
+	}
+	if gh[9] != "2026-03-01T12:00:00Z" {
+		t.Errorf("created = %q", gh[9])
+	}
+	if rows[3][0] != "zburn/unassigned" {
+		t.Errorf("orphan group = %q", rows[3][0])
+	}
+}
+
+func TestWriteKeePassXMLIdentityNotes(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, KeePassXML, testVault(), exportNow); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"<Name>Jane Doe &lt;jane@zburn.id&gt;</Name>",
+		"email: jane@zburn.id",
+		"label: shopping",
+		`<Value ProtectInMemory="True">s3cret</Value>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("export missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package exporter
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/zarlcorp/zburn/internal/credential"
+)
+
+// keePassRoot names the top-level group of an export; each identity is a
+// group below it.
+const keePassRoot = "zburn"
+
+// keePassTime is the timestamp layout of KeePass exports.
+const keePassTime = "2006-01-02T15:04:05Z"
+
+// keePassCSVHeader is the column layout KeePassXC writes and reads.
+var keePassCSVHeader = []string{"Group", "Title", "Username", "Password", "URL", "Notes", "TOTP", "Icon", "Last Modified", "Created"}
+
+func writeKeePassCSV(w io.Writer, v Vault) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(keePassCSVHeader); err != nil {
+		return err
+	}
+	for _, g := range groups(v) {
+		path := keePassRoot + "/" + g.name()
+		for _, c := range g.credentials {
+			err := cw.Write([]string{
+				path,
+				c.Label,
+				c.Username,
+				c.Password,
+				c.URL,
+				c.Notes,
+				totpURI(c),
+				"0",
+				c.UpdatedAt.UTC().Format(keePassTime),
+				c.CreatedAt.UTC().Format(keePassTime),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+type keePassFile struct {
+	XMLName xml.Name `xml:"KeePassFile"`
+	Meta    struct {
+		Generator string `xml:"Generator"`
+	} `xml:"Meta"`
+	Root struct {
+		Group keePassGroup `xml:"Group"`
+	} `xml:"Root"`
+}
+
+type keePassGroup struct {
+	UUID    string         `xml:"UUID"`
+	Name    string         `xml:"Name"`
+	Notes   string         `xml:"Notes,omitempty"`
+	Entries []keePassEntry `xml:"Entry"`
+	Groups  []keePassGroup `xml:"Group"`
+}
+
+type keePassEntry struct {
+	UUID    string          `xml:"UUID"`
+	Times   keePassTimes    `xml:"Times"`
+	Strings []keePassString `xml:"String"`
+}
+
+type keePassTimes struct {
+	CreationTime         string `xml:"CreationTime"`
+	LastModificationTime string `xml:"LastModificationTime"`
+}
+
+type keePassString struct {
+	Key   string       `xml:"Key"`
+	Value keePassValue `xml:"Value"`
+}
+
+type keePassValue struct {
+	Protect string `xml:"ProtectInMemory,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// writeKeePassXML writes a KeePass 2 XML export: a group per identity,
+// holding the identity's details in its notes and an entry per credential.
+// TOTP is stored under "otp" as KeePassXC does.
+func writeKeePassXML(w io.Writer, v Vault) error {
+	var f keePassFile
+	f.Meta.Generator = "zburn"
+	f.Root.Group = keePassGroup{UUID: keePassUUID(), Name: keePassRoot}
+
+	for _, g := range groups(v) {
+		kg := keePassGroup{UUID: keePassUUID(), Name: g.name()}
+		if g.identity != nil {
+			kg.Notes = keePassIdentityNotes(g)
+		}
+		for _, c := range g.credentials {
+			kg.Entries = append(kg.Entries, keePassCredential(c))
+		}
+		f.Root.Group.Groups = append(f.Root.Group.Groups, kg)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "\t")
+	if err := enc.Encode(f); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func keePassCredential(c credential.Credential) keePassEntry {
+	e := keePassEntry{
+		UUID: keePassUUID(),
+		Times: keePassTimes{
+			CreationTime:         c.CreatedAt.UTC().Format(keePassTime),
+			LastModificationTime: c.UpdatedAt.UTC().Format(keePassTime),
+		},
+	}
+	add := func(key, value string, protect bool) {
+		s := keePassString{Key: key, Value: keePassValue{Text: value}}
+		if protect {
+			s.Value.Protect = "True"
+		}
+		e.Strings = append(e.Strings, s)
+	}
+	add("Title", c.Label, false)
+	add("UserName", c.Username, false)
+	add("Password", c.Password, true)
+	add("URL", c.URL, false)
+	add("Notes", c.Notes, false)
+	if uri := totpURI(c); uri != "" {
+		add("otp", uri, true)
+	}
+	return e
+}
+
+// keePassIdentityNotes lists an identity's details, as KeePass groups have
+// no structured fields.
+func keePassIdentityNotes(g group) string {
+	id := g.identity
+	notes := fmt.Sprintf("name: %s %s\nemail: %s\nphone: %s\naddress: %s, %s, %s %s",
+		id.FirstName, id.LastName, id.Email, id.Phone, id.Street, id.City, id.State, id.Zip)
+	if id.Username != "" {
+		notes += "\nusername: " + id.Username
+	}
+	if !id.DOB.IsZero() {
+		notes += "\ndob: " + id.DOB.Format(time.DateOnly)
+	}
+	if extra := identityNotes(id.Label, id.Tags, id.Notes); extra != "" {
+		notes += "\n" + extra
+	}
+	return notes
+}
+
+func keePassUUID() string {
+	u := newUUID()
+	return base64.StdEncoding.EncodeToString(u[:])
+}
@@ -0,0 +1,74 @@
+package exporter
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/zarlcorp/zburn/internal/identity"
+)
+
+// zburnVersion is bumped when the zburn export schema changes.
+const zburnVersion = 1
+
+type zburnExport struct {
+	Format     string            `json:"format"`
+	Version    int               `json:"version"`
+	ExportedAt time.Time         `json:"exported_at"`
+	Identities []zburnIdentity   `json:"identities"`
+	Unassigned []zburnCredential `json:"unassigned_credentials,omitempty"`
+}
+
+type zburnIdentity struct {
+	identity.Identity
+	Credentials []zburnCredential `json:"credentials"`
+}
+
+// zburnCredential is a credential with its TOTP settings folded into one
+// otpauth URI.
+type zburnCredential struct {
+	ID        string    `json:"id"`
+	Label     string    `json:"label"`
+	URL       string    `json:"url,omitempty"`
+	Username  string    `json:"username,omitempty"`
+	Password  string    `json:"password"`
+	TOTP      string    `json:"totp,omitempty"`
+	Notes     string    `json:"notes,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func writeZburn(w io.Writer, v Vault, now time.Time) error {
+	exp := zburnExport{
+		Format:     "zburn",
+		Version:    zburnVersion,
+		ExportedAt: now.UTC(),
+		Identities: []zburnIdentity{},
+	}
+
+	for _, g := range groups(v) {
+		creds := make([]zburnCredential, 0, len(g.credentials))
+		for _, c := range g.credentials {
+			creds = append(creds, zburnCredential{
+				ID:        c.ID,
+				Label:     c.Label,
+				URL:       c.URL,
+				Username:  c.Username,
+				Password:  c.Password,
+				TOTP:      totpURI(c),
+				Notes:     c.Notes,
+				CreatedAt: c.CreatedAt,
+				UpdatedAt: c.UpdatedAt,
+			})
+		}
+		if g.identity == nil {
+			exp.Unassigned = creds
+			continue
+		}
+		exp.Identities = append(exp.Identities, zburnIdentity{Identity: *g.identity, Credentials: creds})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(exp)
+}
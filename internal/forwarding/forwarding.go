@@ -0,0 +1,231 @@
+// Package forwarding keeps per-identity Namecheap mailbox forwarding rules in
+// sync with the saved identities.
+package forwarding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zarlcorp/zburn/internal/identity"
+	"github.com/zarlcorp/zburn/internal/namecheap"
+)
+
+// CatchAll is the wildcard mailbox that matches every address on a domain.
+const CatchAll = "*"
+
+// excludedDomains are org-owned domains whose forwarding zburn never touches.
+var excludedDomains = map[string]bool{
+	"zarlcorp.com": true,
+	"zarl.dev":     true,
+}
+
+// Excluded reports whether zburn must leave a domain's forwarding alone.
+func Excluded(domain string) bool {
+	return excludedDomains[domain]
+}
+
+// Managed splits email into mailbox and domain and reports whether the
+// domain is one of domains and not excluded.
+func Managed(email string, domains []string) (mailbox, domain string, ok bool) {
+	mailbox, domain, ok = strings.Cut(email, "@")
+	if !ok || mailbox == "" || domain == "" || Excluded(domain) {
+		return "", "", false
+	}
+	for _, d := range domains {
+		if d == domain {
+			return mailbox, domain, true
+		}
+	}
+	return "", "", false
+}
+
+// Client reads and replaces the forwarding rules of a domain.
+type Client interface {
+	GetForwarding(ctx context.Context, domain string) ([]namecheap.ForwardingRule, error)
+	SetForwarding(ctx context.Context, domain string, rules []namecheap.ForwardingRule) error
+}
+
+// Action is the kind of fix a Change applies.
+type Action string
+
+const (
+	ActionAdd    Action = "add"
+	ActionUpdate Action = "update"
+	ActionRemove Action = "remove"
+)
+
+// Change describes one rule that differs from the identity store.
+type Change struct {
+	Action    Action `json:"action"`
+	Domain    string `json:"domain"`
+	Mailbox   string `json:"mailbox"`
+	ForwardTo string `json:"forward_to"`
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%-6s %s@%s → %s", c.Action, c.Mailbox, c.Domain, c.ForwardTo)
+}
+
+// Ledger records, per domain, the mailboxes zburn has created forwarding
+// rules for. Only rules in the ledger are ever removed, so aliases and
+// catch-alls set up by hand are left alone.
+type Ledger struct {
+	Mailboxes map[string][]string `json:"mailboxes,omitempty"`
+}
+
+// Has reports whether zburn created the rule for mailbox on domain.
+func (l Ledger) Has(domain, mailbox string) bool {
+	for _, mb := range l.Mailboxes[domain] {
+		if mb == mailbox {
+			return true
+		}
+	}
+	return false
+}
+
+// Add returns a copy of the ledger with mailbox recorded on domain.
+func (l Ledger) Add(domain, mailbox string) Ledger {
+	if l.Has(domain, mailbox) {
+		return l
+	}
+	return l.Set(domain, append(append([]string(nil), l.Mailboxes[domain]...), mailbox))
+}
+
+// Remove returns a copy of the ledger without mailbox on domain.
+func (l Ledger) Remove(domain, mailbox string) Ledger {
+	var kept []string
+	for _, mb := range l.Mailboxes[domain] {
+		if mb != mailbox {
+			kept = append(kept, mb)
+		}
+	}
+	return l.Set(domain, kept)
+}
+
+// Set returns a copy of the ledger recording exactly mailboxes on domain.
+func (l Ledger) Set(domain string, mailboxes []string) Ledger {
+	next := Ledger{Mailboxes: make(map[string][]string, len(l.Mailboxes)+1)}
+	for d, mbs := range l.Mailboxes {
+		next.Mailboxes[d] = mbs
+	}
+	if len(mailboxes) == 0 {
+		delete(next.Mailboxes, domain)
+		return next
+	}
+	sorted := append([]string(nil), mailboxes...)
+	sort.Strings(sorted)
+	next.Mailboxes[domain] = sorted
+	return next
+}
+
+// Diff compares a domain's current rules with the mailboxes that should
+// forward to forwardTo. It returns the corrected rule set and the changes
+// needed to get there. Wanted mailboxes are redirected to forwardTo, and
+// rules zburn created (listed in created) that are no longer wanted are
+// removed, including a catch-all it set up. Everything else is left
+// untouched.
+func Diff(domain string, rules []namecheap.ForwardingRule, mailboxes, created []string, forwardTo string) ([]namecheap.ForwardingRule, []Change) {
+	want := make(map[string]bool, len(mailboxes))
+	for _, mb := range mailboxes {
+		want[mb] = true
+	}
+	ours := make(map[string]bool, len(created))
+	for _, mb := range created {
+		ours[mb] = true
+	}
+
+	var out []namecheap.ForwardingRule
+	var changes []Change
+	seen := make(map[string]bool, len(rules))
+
+	for _, r := range rules {
+		switch {
+		case want[r.Mailbox]:
+			if seen[r.Mailbox] {
+				// duplicate rule for the same mailbox; drop it
+				changes = append(changes, Change{Action: ActionRemove, Domain: domain, Mailbox: r.Mailbox, ForwardTo: r.ForwardTo})
+				continue
+			}
+			seen[r.Mailbox] = true
+			if r.ForwardTo != forwardTo {
+				changes = append(changes, Change{Action: ActionUpdate, Domain: domain, Mailbox: r.Mailbox, ForwardTo: forwardTo})
+				r.ForwardTo = forwardTo
+			}
+			out = append(out, r)
+		case ours[r.Mailbox]:
+			changes = append(changes, Change{Action: ActionRemove, Domain: domain, Mailbox: r.Mailbox, ForwardTo: r.ForwardTo})
+		default:
+			out = append(out, r)
+		}
+	}
+
+	missing := make([]string, 0, len(want))
+	for mb := range want {
+		if !seen[mb] {
+			missing = append(missing, mb)
+		}
+	}
+	sort.Strings(missing)
+
+	for _, mb := range missing {
+		out = append(out, namecheap.ForwardingRule{Mailbox: mb, ForwardTo: forwardTo})
+		changes = append(changes, Change{Action: ActionAdd, Domain: domain, Mailbox: mb, ForwardTo: forwardTo})
+	}
+
+	return out, changes
+}
+
+// Reconcile diffs the forwarding rules of each managed domain against the
+// saved identities and, when apply is set, writes the corrected rules. Only
+// rules recorded in created are removed. The returned ledger records the
+// rules zburn owns afterwards; on a dry run it is created unchanged.
+// It continues past failing domains and returns their errors joined.
+func Reconcile(ctx context.Context, c Client, domains []string, ids []identity.Identity, created Ledger, forwardTo string, apply bool) ([]Change, Ledger, error) {
+	if forwardTo == "" {
+		return nil, created, fmt.Errorf("reconcile: no forwarding address")
+	}
+
+	mailboxes := make(map[string][]string)
+	for _, id := range ids {
+		if mb, d, ok := Managed(id.Email, domains); ok {
+			mailboxes[d] = append(mailboxes[d], mb)
+		}
+	}
+
+	sorted := append([]string(nil), domains...)
+	sort.Strings(sorted)
+
+	owned := created
+	var changes []Change
+	var errs []error
+	for _, d := range sorted {
+		if Excluded(d) {
+			continue
+		}
+
+		rules, err := c.GetForwarding(ctx, d)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", d, err))
+			continue
+		}
+
+		fixed, diff := Diff(d, rules, mailboxes[d], created.Mailboxes[d], forwardTo)
+
+		if apply && len(diff) > 0 {
+			if err := c.SetForwarding(ctx, d, fixed); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", d, err))
+				continue
+			}
+		}
+		if apply {
+			// every rule zburn created is now one of the wanted mailboxes
+			owned = owned.Set(d, mailboxes[d])
+		}
+		changes = append(changes, diff...)
+	}
+
+	return changes, owned, errors.Join(errs...)
+}
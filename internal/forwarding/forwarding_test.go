@@ -0,0 +1,233 @@
+package forwarding
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/zarlcorp/zburn/internal/identity"
+	"github.com/zarlcorp/zburn/internal/namecheap"
+)
+
+type fakeClient struct {
+	rules  map[string][]namecheap.ForwardingRule
+	getErr map[string]error
+	set    map[string][]namecheap.ForwardingRule
+}
+
+func (f *fakeClient) GetForwarding(_ context.Context, domain string) ([]namecheap.ForwardingRule, error) {
+	if err := f.getErr[domain]; err != nil {
+		return nil, err
+	}
+	return f.rules[domain], nil
+}
+
+func (f *fakeClient) SetForwarding(_ context.Context, domain string, rules []namecheap.ForwardingRule) error {
+	if f.set == nil {
+		f.set = make(map[string][]namecheap.ForwardingRule)
+	}
+	f.set[domain] = rules
+	return nil
+}
+
+func TestManaged(t *testing.T) {
+	domains := []string{"burner.test", "zarlcorp.com"}
+
+	tests := []struct {
+		email   string
+		mailbox string
+		domain  string
+		ok      bool
+	}{
+		{"jane@burner.test", "jane", "burner.test", true},
+		{"jane@other.test", "", "", false},
+		{"jane@zarlcorp.com", "", "", false},
+		{"not-an-email", "", "", false},
+		{"@burner.test", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			mb, d, ok := Managed(tt.email, domains)
+			if mb != tt.mailbox || d != tt.domain || ok != tt.ok {
+				t.Errorf("Managed(%q) = %q, %q, %v, want %q, %q, %v", tt.email, mb, d, ok, tt.mailbox, tt.domain, tt.ok)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	rules := []namecheap.ForwardingRule{
+		{Mailbox: "*", ForwardTo: "me@gmail.com"},
+		{Mailbox: "alice", ForwardTo: "me@gmail.com"},
+		{Mailbox: "bob", ForwardTo: "old@gmail.com"},
+		{Mailbox: "burned", ForwardTo: "me@gmail.com"},
+		{Mailbox: "postmaster", ForwardTo: "admin@example.com"},
+	}
+	created := []string{"*", "alice", "burned"}
+
+	got, changes := Diff("burner.test", rules, []string{"alice", "bob", "carol"}, created, "me@gmail.com")
+
+	wantRules := []namecheap.ForwardingRule{
+		{Mailbox: "alice", ForwardTo: "me@gmail.com"},
+		{Mailbox: "bob", ForwardTo: "me@gmail.com"},
+		{Mailbox: "postmaster", ForwardTo: "admin@example.com"},
+		{Mailbox: "carol", ForwardTo: "me@gmail.com"},
+	}
+	if !reflect.DeepEqual(got, wantRules) {
+		t.Errorf("rules = %+v, want %+v", got, wantRules)
+	}
+
+	wantChanges := []Change{
+		{Action: ActionRemove, Domain: "burner.test", Mailbox: "*", ForwardTo: "me@gmail.com"},
+		{Action: ActionUpdate, Domain: "burner.test", Mailbox: "bob", ForwardTo: "me@gmail.com"},
+		{Action: ActionRemove, Domain: "burner.test", Mailbox: "burned", ForwardTo: "me@gmail.com"},
+		{Action: ActionAdd, Domain: "burner.test", Mailbox: "carol", ForwardTo: "me@gmail.com"},
+	}
+	if !reflect.DeepEqual(changes, wantChanges) {
+		t.Errorf("changes = %+v, want %+v", changes, wantChanges)
+	}
+}
+
+func TestDiffKeepsManualRules(t *testing.T) {
+	rules := []namecheap.ForwardingRule{
+		{Mailbox: "*", ForwardTo: "me@gmail.com"},
+		{Mailbox: "info", ForwardTo: "me@gmail.com"},
+		{Mailbox: "admin", ForwardTo: "me@gmail.com"},
+		{Mailbox: "burned", ForwardTo: "me@gmail.com"},
+	}
+
+	got, changes := Diff("burner.test", rules, nil, []string{"burned"}, "me@gmail.com")
+
+	wantRules := rules[:3]
+	if !reflect.DeepEqual(got, wantRules) {
+		t.Errorf("rules = %+v, want %+v", got, wantRules)
+	}
+	wantChanges := []Change{{Action: ActionRemove, Domain: "burner.test", Mailbox: "burned", ForwardTo: "me@gmail.com"}}
+	if !reflect.DeepEqual(changes, wantChanges) {
+		t.Errorf("changes = %+v, want %+v", changes, wantChanges)
+	}
+}
+
+func TestDiffInSync(t *testing.T) {
+	rules := []namecheap.ForwardingRule{{Mailbox: "alice", ForwardTo: "me@gmail.com"}}
+
+	_, changes := Diff("burner.test", rules, []string{"alice"}, []string{"alice"}, "me@gmail.com")
+	if len(changes) != 0 {
+		t.Errorf("changes = %+v, want none", changes)
+	}
+}
+
+func TestLedger(t *testing.T) {
+	var l Ledger
+	l = l.Add("a.test", "bob").Add("a.test", "alice").Add("a.test", "bob")
+
+	if !l.Has("a.test", "alice") || l.Has("b.test", "alice") {
+		t.Errorf("ledger = %+v", l)
+	}
+	if want := []string{"alice", "bob"}; !reflect.DeepEqual(l.Mailboxes["a.test"], want) {
+		t.Errorf("a.test = %v, want %v", l.Mailboxes["a.test"], want)
+	}
+
+	cleared := l.Set("a.test", nil)
+	if _, ok := cleared.Mailboxes["a.test"]; ok {
+		t.Error("an empty domain should be dropped")
+	}
+	if !l.Has("a.test", "alice") {
+		t.Error("Set should not modify the original ledger")
+	}
+
+	removed := l.Remove("a.test", "bob")
+	if removed.Has("a.test", "bob") || !removed.Has("a.test", "alice") || !l.Has("a.test", "bob") {
+		t.Errorf("removed = %+v, original = %+v", removed, l)
+	}
+	if _, ok := removed.Remove("a.test", "alice").Mailboxes["a.test"]; ok {
+		t.Error("removing the last mailbox should drop the domain")
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	c := &fakeClient{rules: map[string][]namecheap.ForwardingRule{
+		"a.test": {
+			{Mailbox: "stale", ForwardTo: "me@gmail.com"},
+			{Mailbox: "info", ForwardTo: "me@gmail.com"},
+		},
+		"b.test": {{Mailbox: "bob", ForwardTo: "me@gmail.com"}},
+	}}
+	ids := []identity.Identity{
+		{Email: "alice@a.test"},
+		{Email: "bob@b.test"},
+		{Email: "carol@elsewhere.test"},
+	}
+	created := Ledger{Mailboxes: map[string][]string{"a.test": {"stale"}}}
+
+	changes, owned, err := Reconcile(context.Background(), c, []string{"b.test", "a.test", "zarl.dev"}, ids, created, "me@gmail.com", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(changes) != 2 {
+		t.Fatalf("changes = %+v, want 2", changes)
+	}
+
+	want := []namecheap.ForwardingRule{
+		{Mailbox: "info", ForwardTo: "me@gmail.com"},
+		{Mailbox: "alice", ForwardTo: "me@gmail.com"},
+	}
+	if !reflect.DeepEqual(c.set["a.test"], want) {
+		t.Errorf("a.test rules = %+v, want %+v", c.set["a.test"], want)
+	}
+	if _, ok := c.set["b.test"]; ok {
+		t.Error("in-sync domain should not be written")
+	}
+	if _, ok := c.set["zarl.dev"]; ok {
+		t.Error("excluded domain should not be written")
+	}
+
+	wantOwned := map[string][]string{"a.test": {"alice"}, "b.test": {"bob"}}
+	if !reflect.DeepEqual(owned.Mailboxes, wantOwned) {
+		t.Errorf("owned = %v, want %v", owned.Mailboxes, wantOwned)
+	}
+}
+
+func TestReconcileDryRun(t *testing.T) {
+	c := &fakeClient{rules: map[string][]namecheap.ForwardingRule{}}
+	ids := []identity.Identity{{Email: "alice@a.test"}}
+
+	changes, owned, err := Reconcile(context.Background(), c, []string{"a.test"}, ids, Ledger{}, "me@gmail.com", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(owned.Mailboxes) != 0 {
+		t.Errorf("dry run should not record rules, got %v", owned.Mailboxes)
+	}
+	if len(changes) != 1 || changes[0].Action != ActionAdd {
+		t.Errorf("changes = %+v, want one add", changes)
+	}
+	if len(c.set) != 0 {
+		t.Error("dry run should not write rules")
+	}
+}
+
+func TestReconcileContinuesPastErrors(t *testing.T) {
+	c := &fakeClient{
+		rules:  map[string][]namecheap.ForwardingRule{},
+		getErr: map[string]error{"a.test": fmt.Errorf("api: rate limited")},
+	}
+	ids := []identity.Identity{{Email: "alice@a.test"}, {Email: "bob@b.test"}}
+
+	changes, _, err := Reconcile(context.Background(), c, []string{"a.test", "b.test"}, ids, Ledger{}, "me@gmail.com", true)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if len(changes) != 1 || changes[0].Domain != "b.test" {
+		t.Errorf("changes = %+v, want b.test add", changes)
+	}
+}
+
+func TestReconcileRequiresForwardTo(t *testing.T) {
+	if _, _, err := Reconcile(context.Background(), &fakeClient{}, nil, nil, Ledger{}, "", true); err == nil {
+		t.Fatal("expected error without forwarding address")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"mime"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -42,6 +43,16 @@ func NewClient(accessToken string) *Client {
 	}
 }
 
+// StatusError is returned when the Gmail API answers with a status other
+// than 200 OK.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status %d", e.StatusCode)
+}
+
 // listResponse maps the JSON from the messages.list endpoint.
 type listResponse struct {
 	Messages []struct {
@@ -103,7 +114,7 @@ func (c *Client) GetProfile(ctx context.Context) (string, error) {
 
 // ListMessages returns message IDs matching a Gmail search query.
 func (c *Client) ListMessages(ctx context.Context, query string, maxResults int) ([]Message, error) {
-	u := fmt.Sprintf("%s/messages?q=%s&maxResults=%d", apiBase, query, maxResults)
+	u := fmt.Sprintf("%s/messages?q=%s&maxResults=%d", apiBase, url.QueryEscape(query), maxResults)
 
 	body, err := c.doGet(ctx, u)
 	if err != nil {
@@ -154,7 +165,7 @@ func (c *Client) doGet(ctx context.Context, url string) ([]byte, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("status %d", resp.StatusCode)
+		return nil, &StatusError{StatusCode: resp.StatusCode}
 	}
 
 	// gmail messages are bounded in size, safe to read fully
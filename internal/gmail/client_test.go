@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -253,6 +254,10 @@ func TestGetMessageErrorStatus(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error for 404 response")
 	}
+	var se *StatusError
+	if !errors.As(err, &se) || se.StatusCode != http.StatusNotFound {
+		t.Errorf("err = %v, want StatusError 404", err)
+	}
 }
 
 func TestParseMIMEBody(t *testing.T) {
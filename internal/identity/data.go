@@ -35,27 +35,6 @@ var lastNames = []string{
 	"Long", "Ross", "Foster", "Jimenez",
 }
 
-var cities = []string{
-	"New York", "Los Angeles", "Chicago", "Houston", "Phoenix",
-	"Philadelphia", "San Antonio", "San Diego", "Dallas", "San Jose",
-	"Austin", "Jacksonville", "Fort Worth", "Columbus", "Indianapolis",
-	"Charlotte", "San Francisco", "Seattle", "Denver", "Nashville",
-	"Oklahoma City", "El Paso", "Boston", "Portland", "Las Vegas",
-	"Memphis", "Louisville", "Baltimore", "Milwaukee", "Albuquerque",
-	"Tucson", "Fresno", "Sacramento", "Mesa", "Kansas City",
-	"Atlanta", "Omaha", "Raleigh", "Miami", "Minneapolis",
-	"Tampa", "New Orleans", "Cleveland", "Pittsburgh", "Cincinnati",
-	"St. Louis", "Orlando", "Richmond", "Salt Lake City", "Honolulu",
-}
-
-var states = []string{
-	"AL", "AK", "AZ", "AR", "CA", "CO", "CT", "DE", "FL", "GA",
-	"HI", "ID", "IL", "IN", "IA", "KS", "KY", "LA", "ME", "MD",
-	"MA", "MI", "MN", "MS", "MO", "MT", "NE", "NV", "NH", "NJ",
-	"NM", "NY", "NC", "ND", "OH", "OK", "OR", "PA", "RI", "SC",
-	"SD", "TN", "TX", "UT", "VT", "VA", "WA", "WV", "WI", "WY",
-}
-
 var streetNames = []string{
 	"Main", "Oak", "Maple", "Cedar", "Elm", "Pine", "Walnut", "Lake",
 	"Hill", "Washington", "Park", "River", "Spring", "Church", "High",
@@ -92,3 +71,53 @@ var nouns = []string{
 	"moss", "sage", "lily", "rose", "iris", "vine", "fern", "palm",
 	"cliff", "ridge",
 }
+
+// companyPrefixes and companySuffixes combine into fictional employers.
+var companyPrefixes = []string{
+	"Northwind", "Bluepeak", "Ironwood", "Silverline", "Brightwater", "Redstone",
+	"Greenfield", "Oakridge", "Clearview", "Summit", "Harbor", "Pinecrest",
+	"Lakeside", "Stonebridge", "Westbrook", "Eastgate", "Riverbend", "Highmark",
+	"Keystone", "Crescent",
+}
+
+var companySuffixes = []string{
+	"Labs", "Systems", "Partners", "Group", "Holdings", "Logistics",
+	"Analytics", "Consulting", "Media", "Industries", "Studios", "Works",
+}
+
+// jobLevels and jobRoles combine into job titles.
+var jobLevels = []string{
+	"Junior", "Senior", "Lead", "Principal", "Associate", "Staff", "Head of",
+}
+
+var jobRoles = []string{
+	"Accountant", "Analyst", "Designer", "Engineer", "Consultant", "Editor",
+	"Recruiter", "Planner", "Researcher", "Coordinator", "Developer", "Buyer",
+	"Copywriter", "Administrator", "Strategist",
+}
+
+var genders = []string{"female", "male", "non-binary"}
+
+var colours = []string{
+	"red", "orange", "yellow", "green", "teal", "blue", "indigo", "purple",
+	"pink", "brown", "black", "white", "grey", "silver", "gold",
+}
+
+// petNames, carMakes and foods answer security questions.
+var petNames = []string{
+	"Max", "Bella", "Charlie", "Luna", "Buddy", "Daisy", "Rocky", "Molly",
+	"Coco", "Bailey", "Milo", "Rosie", "Toby", "Ginger", "Oscar", "Pepper",
+	"Biscuit", "Shadow", "Smokey", "Whiskers",
+}
+
+var carMakes = []string{
+	"Toyota", "Honda", "Ford", "Volkswagen", "Nissan", "Mazda", "Subaru",
+	"Peugeot", "Renault", "Fiat", "Vauxhall", "Opel", "Hyundai", "Kia",
+	"Volvo", "Chevrolet", "Skoda", "Seat",
+}
+
+var foods = []string{
+	"lasagne", "pizza", "sushi", "tacos", "curry", "pancakes", "paella",
+	"ramen", "risotto", "dumplings", "chili", "falafel", "goulash", "pho",
+	"roast chicken", "apple pie",
+}
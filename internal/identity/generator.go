@@ -1,47 +1,91 @@
 package identity
 
 import (
-	"crypto/rand"
 	"encoding/hex"
 	"fmt"
-	"math/big"
+	"io"
 	"strings"
 	"time"
-
-	"github.com/zarlcorp/core/pkg/zcrypto"
 )
 
 // numEmailPatterns is the total number of email local-part patterns.
 const numEmailPatterns = 8
 
-// Generator produces random identity data using crypto/rand.
-type Generator struct{}
+// Generator produces random identity data. It draws from crypto/rand
+// unless built with NewSeeded or NewWithSource.
+type Generator struct {
+	rand *Rand
+	now  func() time.Time
+
+	// epoch, when set, replaces today as the date ages are counted from.
+	epoch time.Time
+}
 
-// New creates a generator.
+// seedEpoch is the reference date for seeded dates of birth, so a seed
+// gives the same identity whenever it is run.
+var seedEpoch = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// New creates a generator backed by crypto/rand.
 func New() *Generator {
-	return &Generator{}
+	return &Generator{rand: defaultRand, now: time.Now}
+}
+
+// NewSeeded creates a generator whose output is determined by seed: two
+// generators with the same seed produce the same sequence of identities.
+// DOB is counted back from a fixed date rather than today; only CreatedAt
+// is the wall clock. Seeded identities are for fixtures and tests, never
+// for real signups.
+func NewSeeded(seed string) *Generator {
+	return &Generator{rand: SeededRand(seed), now: time.Now, epoch: seedEpoch}
+}
+
+// NewWithSource creates a generator that reads its randomness from src.
+// Generation panics if src returns an error.
+func NewWithSource(src io.Reader) *Generator {
+	return &Generator{rand: NewRand(src), now: time.Now}
 }
 
 // Generate produces a complete random identity.
 // When domain is empty, falls back to the default zburn.id domain.
-func (g *Generator) Generate(domain string) Identity {
+// Without WithLocale the identity is American; WithTemplate adds the
+// template's user-defined fields.
+func (g *Generator) Generate(domain string, opts ...Option) Identity {
 	if domain == "" {
 		domain = defaultDomain
 	}
-	first, last := g.Name()
-	return Identity{
+
+	o := options{locale: locales[DefaultLocale]}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	l := o.locale
+
+	r := g.rand
+	first, last := r.Pick(l.FirstNames), r.Pick(l.LastNames)
+	place := l.Places[r.Intn(len(l.Places))]
+	id := Identity{
 		ID:        g.hexID(),
 		FirstName: first,
 		LastName:  last,
 		Email:     g.Email(first, last, domain),
-		Phone:     g.phone(),
-		Street:    g.street(),
-		City:      pick(cities),
-		State:     pick(states),
-		Zip:       g.zip(),
+		Phone:     l.Phone(r, place),
+		Street:    l.Street(r),
+		City:      place.City,
+		State:     place.Region,
+		Zip:       l.postcode(r, place),
+		Country:   l.Country,
 		DOB:       g.dob(),
-		CreatedAt: time.Now(),
+		CreatedAt: g.now(),
+
+		Username:        handle(r),
+		Company:         company(r),
+		JobTitle:        jobTitle(r),
+		SecurityAnswers: g.securityAnswers(l),
+	}
+	if o.template != nil {
+		id = g.ApplyTemplate(id, *o.template)
 	}
+	return id
 }
 
 // Email generates an email address using the identity's name and domain.
@@ -58,11 +102,12 @@ func (g *Generator) Email(firstName, lastName, domain string) string {
 	if domain == "" {
 		domain = defaultDomain
 	}
-	first := strings.ToLower(firstName)
-	last := strings.ToLower(lastName)
+	first := emailPart(firstName)
+	last := emailPart(lastName)
 	initial := string(first[0])
 
-	pattern := randIntn(numEmailPatterns)
+	r := g.rand
+	pattern := r.Intn(numEmailPatterns)
 	var local string
 	switch pattern {
 	case 0: // firstname.lastname
@@ -72,15 +117,15 @@ func (g *Generator) Email(firstName, lastName, domain string) string {
 	case 2: // firstnamelastname
 		local = first + last
 	case 3: // firstname.lastname + 2 digits
-		local = first + "." + last + fmt.Sprintf("%02d", randIntn(100))
+		local = first + "." + last + fmt.Sprintf("%02d", r.Intn(100))
 	case 4: // firstinitiallastname + 2 digits
-		local = initial + last + fmt.Sprintf("%02d", randIntn(100))
+		local = initial + last + fmt.Sprintf("%02d", r.Intn(100))
 	case 5: // firstinitial.lastname
 		local = initial + "." + last
 	case 6: // lastname.firstname
 		local = last + "." + first
 	case 7: // adjective + noun + 4 digits
-		local = pick(adjectives) + pick(nouns) + fmt.Sprintf("%04d", randIntn(10000))
+		local = handle(r)
 	}
 	return local + "@" + domain
 }
@@ -88,77 +133,80 @@ func (g *Generator) Email(firstName, lastName, domain string) string {
 // Password generates a password of the given length containing at least
 // one character from each class (lower, upper, digit, symbol).
 func (g *Generator) Password(length int) string {
-	return zcrypto.GeneratePassword(length)
+	p := DefaultPasswordPolicy()
+	p.Length = length
+	return g.PasswordFor(p)
+}
+
+// PasswordFor generates a password that satisfies p.
+func (g *Generator) PasswordFor(p PasswordPolicy) string {
+	return p.generate(g.rand)
 }
 
 // RandomHandle generates a random handle like "swiftfox4821".
 func RandomHandle() string {
-	return pick(adjectives) + pick(nouns) + fmt.Sprintf("%04d", randIntn(10000))
+	return handle(defaultRand)
 }
 
-// Name generates a random first/last name pair.
-func (g *Generator) Name() (first, last string) {
-	return pick(firstNames), pick(lastNames)
+// handle builds an adjective + noun + 4 digits handle from r.
+func handle(r *Rand) string {
+	return r.Pick(adjectives) + r.Pick(nouns) + fmt.Sprintf("%04d", r.Intn(10000))
 }
 
-// hexID generates an 8-character hex string.
-func (g *Generator) hexID() string {
-	b := make([]byte, 4)
-	mustRead(b)
-	return hex.EncodeToString(b)
+// company builds a fictional employer name from r.
+func company(r *Rand) string {
+	return r.Pick(companyPrefixes) + " " + r.Pick(companySuffixes)
 }
 
-// phone generates a US fictional phone number: (555) XXX-XXXX.
-func (g *Generator) phone() string {
-	line := randIntn(10000)
-	// second segment 100-999 to look realistic
-	prefix := 100 + randIntn(900)
-	return fmt.Sprintf("(555) %03d-%04d", prefix, line)
+// jobTitle builds a job title from r.
+func jobTitle(r *Rand) string {
+	return r.Pick(jobLevels) + " " + r.Pick(jobRoles)
 }
 
-// street generates a street address like "1234 Oak Ave".
-func (g *Generator) street() string {
-	num := 100 + randIntn(9900)
-	name := pick(streetNames)
-	suffix := pick(streetSuffixes)
-	return fmt.Sprintf("%d %s %s", num, name, suffix)
+// Name generates a random first/last name pair.
+func (g *Generator) Name() (first, last string) {
+	return g.rand.Pick(firstNames), g.rand.Pick(lastNames)
 }
 
-// zip generates a 5-digit US zip code.
-func (g *Generator) zip() string {
-	return fmt.Sprintf("%05d", randIntn(100000))
+// hexID generates an 8-character hex string.
+func (g *Generator) hexID() string {
+	b := make([]byte, 4)
+	g.rand.Read(b)
+	return hex.EncodeToString(b)
 }
 
-// dob generates a date of birth between 21 and 65 years ago.
+// dob generates a date of birth between 21 and 65 years before today, or
+// before the generator's epoch if it has one.
 func (g *Generator) dob() time.Time {
-	now := time.Now()
+	now := g.epoch
+	if now.IsZero() {
+		now = g.now()
+	}
 	minAge := 21
 	maxAge := 65
-	age := minAge + randIntn(maxAge-minAge+1)
+	age := minAge + g.rand.Intn(maxAge-minAge+1)
 	// subtract years, then randomize day within that year
 	base := now.AddDate(-age, 0, 0)
-	dayOffset := randIntn(365)
+	dayOffset := g.rand.Intn(365)
 	return base.AddDate(0, 0, -dayOffset).Truncate(24 * time.Hour)
 }
 
-// pick returns a random element from a string slice.
-func pick(s []string) string {
-	return s[randIntn(len(s))]
-}
-
-// randIntn returns a cryptographically random int in [0, n).
-func randIntn(n int) int {
-	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
-	if err != nil {
-		// crypto/rand failure is unrecoverable
-		panic("crypto/rand: " + err.Error())
-	}
-	return int(v.Int64())
-}
+// emailFold transliterates the accented letters used in the locale name
+// lists to ASCII.
+var emailFold = strings.NewReplacer(
+	"ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss",
+	"à", "a", "â", "a", "ç", "c", "é", "e", "è", "e", "ê", "e", "ë", "e",
+	"î", "i", "ï", "i", "ô", "o", "ù", "u", "û", "u", "ÿ", "y",
+)
 
-// mustRead fills b with cryptographically random bytes.
-func mustRead(b []byte) {
-	if _, err := rand.Read(b); err != nil {
-		panic("crypto/rand: " + err.Error())
-	}
+// emailPart lowercases a name and reduces it to characters that are safe
+// in an email local part.
+func emailPart(name string) string {
+	s := emailFold.Replace(strings.ToLower(name))
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			return r
+		}
+		return -1
+	}, s)
 }
@@ -2,6 +2,7 @@ package identity
 
 import (
 	"regexp"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -23,13 +24,17 @@ func TestGenerate(t *testing.T) {
 		{"Email non-empty", func() bool { return id.Email != "" }},
 		{"Email has @ sign", func() bool { return strings.Contains(id.Email, "@") }},
 		{"Phone non-empty", func() bool { return id.Phone != "" }},
-		{"Phone has 555", func() bool { return strings.HasPrefix(id.Phone, "(555) ") }},
+		{"Phone has 555-01", func() bool { return strings.Contains(id.Phone, ") 555-01") }},
 		{"Street non-empty", func() bool { return id.Street != "" }},
 		{"City non-empty", func() bool { return id.City != "" }},
 		{"State length", func() bool { return len(id.State) == 2 }},
 		{"Zip length", func() bool { return len(id.Zip) == 5 }},
 		{"DOB non-zero", func() bool { return !id.DOB.IsZero() }},
 		{"CreatedAt non-zero", func() bool { return !id.CreatedAt.IsZero() }},
+		{"Username is a handle", func() bool { return regexp.MustCompile(`^[a-z]+\d{4}$`).MatchString(id.Username) }},
+		{"Company non-empty", func() bool { return id.Company != "" }},
+		{"JobTitle non-empty", func() bool { return id.JobTitle != "" }},
+		{"SecurityAnswers count", func() bool { return len(id.SecurityAnswers) == numSecurityAnswers }},
 	}
 
 	for _, tt := range tests {
@@ -277,11 +282,11 @@ func TestNameRandomness(t *testing.T) {
 
 func TestPhone(t *testing.T) {
 	g := New()
-	re := regexp.MustCompile(`^\(555\) \d{3}-\d{4}$`)
+	re := regexp.MustCompile(`^\(\d{3}\) 555-01\d{2}$`)
 	for range 20 {
 		id := g.Generate("")
 		if !re.MatchString(id.Phone) {
-			t.Errorf("phone %q does not match (555) XXX-XXXX pattern", id.Phone)
+			t.Errorf("phone %q does not match (XXX) 555-01XX pattern", id.Phone)
 		}
 	}
 }
@@ -353,3 +358,35 @@ func TestGenerateEmailUsesName(t *testing.T) {
 		t.Errorf("expected most emails to contain identity name, got %d/100", nameInEmail)
 	}
 }
+
+func TestSecurityAnswers(t *testing.T) {
+	g := New()
+	for range 50 {
+		id := g.Generate("")
+		seen := make(map[string]bool)
+		for _, a := range id.SecurityAnswers {
+			if a.Topic == "" || a.Question == "" || a.Answer == "" {
+				t.Fatalf("incomplete security answer: %+v", a)
+			}
+			if seen[a.Topic] {
+				t.Fatalf("question %q asked twice: %+v", a.Topic, id.SecurityAnswers)
+			}
+			seen[a.Topic] = true
+		}
+	}
+}
+
+func TestSecurityAnswersUseLocale(t *testing.T) {
+	de, err := LookupLocale("DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := New()
+	for range 50 {
+		for _, a := range g.Generate("", WithLocale(de)).SecurityAnswers {
+			if a.Topic == "maiden name" && !slices.Contains(deLastNames, a.Answer) {
+				t.Errorf("maiden name %q is not a German surname", a.Answer)
+			}
+		}
+	}
+}
@@ -1,8 +1,13 @@
 // Package identity generates disposable personal data.
-// All generation uses crypto/rand — no math/rand, no side effects.
+// Generation uses crypto/rand by default; a seeded generator makes the
+// output reproducible for fixtures. No side effects.
 package identity
 
-import "time"
+import (
+	"slices"
+	"strings"
+	"time"
+)
 
 // Identity holds a complete generated persona.
 type Identity struct {
@@ -15,6 +20,54 @@ type Identity struct {
 	City      string    `json:"city"`
 	State     string    `json:"state"`
 	Zip       string    `json:"zip"`
+	Country   string    `json:"country,omitempty"` // ISO 3166-1 alpha-2; empty means US
 	DOB       time.Time `json:"dob"`
 	CreatedAt time.Time `json:"created_at"`
+
+	Username        string           `json:"username,omitempty"` // stable handle for signup forms
+	Company         string           `json:"company,omitempty"`
+	JobTitle        string           `json:"job_title,omitempty"`
+	SecurityAnswers []SecurityAnswer `json:"security_answers,omitempty"`
+
+	// Template names the template the Fields came from, if any.
+	Template string  `json:"template,omitempty"`
+	Fields   []Field `json:"fields,omitempty"`
+
+	// Label, Tags and Notes are set by the user to record what the
+	// identity is for, e.g. the vendor trial it signed up to.
+	Label string   `json:"label,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+	Notes string   `json:"notes,omitempty"`
+}
+
+// ParseTags splits a comma or space separated tag list into lowercase,
+// sorted, unique tags.
+func ParseTags(s string) []string {
+	var tags []string
+	for _, t := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		t = strings.ToLower(strings.TrimPrefix(t, "#"))
+		if t != "" && !slices.Contains(tags, t) {
+			tags = append(tags, t)
+		}
+	}
+	slices.Sort(tags)
+	return tags
+}
+
+// HasTag reports whether the identity carries tag, ignoring case.
+func (id Identity) HasTag(tag string) bool {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	return slices.ContainsFunc(id.Tags, func(t string) bool { return strings.EqualFold(t, tag) })
+}
+
+// Meta renders the identity's label and tags, e.g. "Acme trial #acme #trial".
+func (id Identity) Meta() string {
+	parts := make([]string, 0, len(id.Tags)+1)
+	if id.Label != "" {
+		parts = append(parts, id.Label)
+	}
+	for _, t := range id.Tags {
+		parts = append(parts, "#"+t)
+	}
+	return strings.Join(parts, " ")
 }
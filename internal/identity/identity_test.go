@@ -0,0 +1,45 @@
+package identity
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"trial", []string{"trial"}},
+		{"Trial, acme  #beta,trial", []string{"acme", "beta", "trial"}},
+		{" , ", nil},
+	}
+	for _, tt := range tests {
+		if got := ParseTags(tt.in); !slices.Equal(got, tt.want) {
+			t.Errorf("ParseTags(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestHasTag(t *testing.T) {
+	id := Identity{Tags: []string{"acme", "trial"}}
+	for _, tag := range []string{"acme", "TRIAL", "#trial", " acme "} {
+		if !id.HasTag(tag) {
+			t.Errorf("HasTag(%q) = false", tag)
+		}
+	}
+	if id.HasTag("beta") || id.HasTag("") {
+		t.Error("HasTag matched a missing tag")
+	}
+}
+
+func TestMeta(t *testing.T) {
+	id := Identity{Label: "Acme trial", Tags: []string{"acme", "trial"}}
+	if got := id.Meta(); got != "Acme trial #acme #trial" {
+		t.Errorf("Meta = %q", got)
+	}
+	if got := (Identity{}).Meta(); got != "" {
+		t.Errorf("empty Meta = %q", got)
+	}
+}
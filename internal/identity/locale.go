@@ -0,0 +1,182 @@
+package identity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultLocale is the locale used when none is requested.
+const DefaultLocale = "US"
+
+// Locale is the data set used to generate identities for one country.
+type Locale struct {
+	Country    string // ISO 3166-1 alpha-2 code, e.g. "GB"
+	Name       string // English country name
+	FirstNames []string
+	LastNames  []string
+	Places     []Place
+
+	// PostcodeLetters are the letters '@' in a postcode pattern draws from.
+	PostcodeLetters string
+
+	Street func(*Rand) string        // house number and street name in local order
+	Phone  func(*Rand, Place) string // fictional number in local or international format
+}
+
+// locales holds the registered data sets keyed by country code.
+var locales = map[string]Locale{}
+
+// RegisterLocale adds or replaces the data set for l.Country.
+func RegisterLocale(l Locale) {
+	locales[strings.ToUpper(l.Country)] = l
+}
+
+// localeAliases maps common non-ISO codes to their ISO equivalent.
+var localeAliases = map[string]string{
+	"UK": "GB",
+}
+
+// LookupLocale returns the data set for a country code, case-insensitively.
+func LookupLocale(country string) (Locale, error) {
+	code := strings.ToUpper(strings.TrimSpace(country))
+	if iso, ok := localeAliases[code]; ok {
+		code = iso
+	}
+	l, ok := locales[code]
+	if !ok {
+		return Locale{}, fmt.Errorf("unknown locale %q (available: %s)", country, strings.Join(Locales(), ", "))
+	}
+	return l, nil
+}
+
+// Locales returns the registered country codes in sorted order.
+func Locales() []string {
+	codes := make([]string, 0, len(locales))
+	for c := range locales {
+		codes = append(codes, c)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Option configures a single Generate call.
+type Option func(*options)
+
+type options struct {
+	locale   Locale
+	template *Template
+}
+
+// WithLocale generates the identity from the given locale's data set.
+func WithLocale(l Locale) Option {
+	return func(o *options) {
+		o.locale = l
+	}
+}
+
+func init() {
+	RegisterLocale(Locale{
+		Country:    "US",
+		Name:       "United States",
+		FirstNames: firstNames,
+		LastNames:  lastNames,
+		Places:     usPlaces,
+		Street: func(r *Rand) string {
+			return fmt.Sprintf("%d %s %s", 100+r.Intn(9900), r.Pick(streetNames), r.Pick(streetSuffixes))
+		},
+		Phone: func(r *Rand, p Place) string {
+			// 555-0100 through 555-0199 are reserved for fiction in every area code
+			return fmt.Sprintf("(%s) 555-01%02d", p.AreaCode, r.Intn(100))
+		},
+	})
+
+	RegisterLocale(Locale{
+		Country:    "GB",
+		Name:       "United Kingdom",
+		FirstNames: gbFirstNames,
+		LastNames:  gbLastNames,
+		Places:     gbPlaces,
+		// letters allowed in the inward code
+		PostcodeLetters: "ABDEFGHJLNPQRSTUWXYZ",
+		Street: func(r *Rand) string {
+			return fmt.Sprintf("%d %s %s", 1+r.Intn(250), r.Pick(gbStreetNames), r.Pick(gbStreetSuffixes))
+		},
+		Phone: func(r *Rand, _ Place) string {
+			// Ofcom reserves 07700 900000-900999 for drama
+			return fmt.Sprintf("+44 7700 900%03d", r.Intn(1000))
+		},
+	})
+
+	RegisterLocale(Locale{
+		Country:    "DE",
+		Name:       "Germany",
+		FirstNames: deFirstNames,
+		LastNames:  deLastNames,
+		Places:     dePlaces,
+		Street: func(r *Rand) string {
+			return fmt.Sprintf("%s %d", r.Pick(deStreetNames), 1+r.Intn(150))
+		},
+		Phone: func(r *Rand, p Place) string {
+			// the Bundesnetzagentur reserves 1000 numbers in five cities for
+			// film and television; places elsewhere borrow one of them
+			prefix := deDramaRanges[r.Intn(len(deDramaRanges))].prefix
+			for _, d := range deDramaRanges {
+				if d.city == p.City {
+					prefix = d.prefix
+				}
+			}
+			return fmt.Sprintf("+49 %s %03d", prefix, r.Intn(1000))
+		},
+	})
+
+	RegisterLocale(Locale{
+		Country:    "FR",
+		Name:       "France",
+		FirstNames: frFirstNames,
+		LastNames:  frLastNames,
+		Places:     frPlaces,
+		Street: func(r *Rand) string {
+			return fmt.Sprintf("%d %s", 1+r.Intn(200), r.Pick(frStreetNames))
+		},
+		Phone: func(r *Rand, _ Place) string {
+			// ARCEP reserves 06 39 98 xx xx for fiction
+			return fmt.Sprintf("+33 6 39 98 %02d %02d", r.Intn(100), r.Intn(100))
+		},
+	})
+}
+
+// deDramaRanges are the German fixed-line blocks reserved for fiction, as
+// dialling code and subscriber prefix.
+var deDramaRanges = []struct{ city, prefix string }{
+	{"Berlin", "30 23125"},
+	{"Hamburg", "40 66969"},
+	{"Frankfurt am Main", "69 90009"},
+	{"Köln", "221 4710"},
+	{"München", "89 99998"},
+}
+
+// defaultPostcodeLetters is used when a locale sets no PostcodeLetters.
+const defaultPostcodeLetters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// postcode fills one of the place's postcode patterns.
+func (l Locale) postcode(r *Rand, p Place) string {
+	letters := l.PostcodeLetters
+	if letters == "" {
+		letters = defaultPostcodeLetters
+	}
+
+	pattern := r.Pick(p.Postcodes)
+	out := make([]byte, 0, len(pattern))
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '#':
+			out = append(out, byte('0'+r.Intn(10)))
+		case '@':
+			out = append(out, letters[r.Intn(len(letters))])
+		default:
+			out = append(out, pattern[i])
+		}
+	}
+	return string(out)
+}
@@ -0,0 +1,84 @@
+package identity
+
+// United Kingdom
+
+var gbFirstNames = []string{
+	"Oliver", "Amelia", "George", "Isla", "Harry", "Ava", "Jack", "Emily",
+	"Charlie", "Sophie", "Thomas", "Grace", "Oscar", "Lily", "William", "Freya",
+	"James", "Evie", "Henry", "Poppy", "Alfie", "Ella", "Leo", "Chloe",
+	"Joshua", "Jessica", "Freddie", "Ruby", "Archie", "Daisy", "Ethan", "Alice",
+	"Isaac", "Holly", "Alexander", "Charlotte", "Joseph", "Millie", "Edward", "Lucy",
+	"Samuel", "Eleanor", "Max", "Hannah", "Daniel", "Phoebe", "Arthur", "Imogen",
+}
+
+var gbLastNames = []string{
+	"Smith", "Jones", "Taylor", "Brown", "Williams", "Wilson", "Johnson", "Davies",
+	"Robinson", "Wright", "Thompson", "Evans", "Walker", "White", "Roberts", "Green",
+	"Hall", "Wood", "Jackson", "Clarke", "Patel", "Khan", "Lewis", "James",
+	"Phillips", "Mason", "Mitchell", "Rose", "Davis", "Rodgers", "Cooper", "Morris",
+	"Ward", "Hughes", "Edwards", "Turner", "Hill", "Moore", "Harris", "Baker",
+}
+
+var gbStreetNames = []string{
+	"High", "Station", "Church", "Victoria", "Park", "Mill", "Queen's", "King's",
+	"London", "Manor", "Green", "Grange", "York", "Albert", "New", "School",
+	"North", "South", "West", "Springfield", "Windsor", "Kingsway", "Chapel",
+	"Orchard", "Highfield", "Alexandra", "Cromwell", "Richmond", "Meadow", "Elm",
+}
+
+var gbStreetSuffixes = []string{
+	"Street", "Road", "Lane", "Avenue", "Close", "Drive", "Crescent", "Gardens",
+	"Way", "Grove", "Terrace", "Place",
+}
+
+// Germany
+
+var deFirstNames = []string{
+	"Lukas", "Anna", "Leon", "Lea", "Finn", "Lena", "Jonas", "Hannah",
+	"Paul", "Marie", "Felix", "Sophie", "Maximilian", "Emma", "Elias", "Mia",
+	"Noah", "Laura", "Ben", "Julia", "Luis", "Lara", "Tim", "Katharina",
+	"Jan", "Johanna", "Niklas", "Clara", "Moritz", "Sarah", "Tobias", "Lina",
+	"Florian", "Franziska", "Stefan", "Sabine", "Andreas", "Petra", "Jürgen", "Jörg",
+}
+
+var deLastNames = []string{
+	"Müller", "Schmidt", "Schneider", "Fischer", "Weber", "Meyer", "Wagner", "Becker",
+	"Schulz", "Hoffmann", "Schäfer", "Koch", "Bauer", "Richter", "Klein", "Wolf",
+	"Schröder", "Neumann", "Schwarz", "Zimmermann", "Braun", "Krüger", "Hofmann", "Hartmann",
+	"Lange", "Schmitt", "Werner", "Krause", "Meier", "Lehmann", "Schmid", "Schulze",
+	"Maier", "Köhler", "Herrmann", "König", "Walter", "Mayer", "Huber", "Kaiser",
+}
+
+var deStreetNames = []string{
+	"Hauptstraße", "Schulstraße", "Gartenstraße", "Bahnhofstraße", "Dorfstraße",
+	"Bergstraße", "Birkenweg", "Lindenstraße", "Kirchstraße", "Waldstraße",
+	"Ringstraße", "Schillerstraße", "Goethestraße", "Jahnstraße", "Wiesenweg",
+	"Am Sportplatz", "Mühlenweg", "Friedhofstraße", "Feldstraße", "Rosenstraße",
+	"Buchenweg", "Eichenweg", "Mozartstraße", "Talstraße", "Parkstraße",
+}
+
+// France
+
+var frFirstNames = []string{
+	"Gabriel", "Louise", "Léo", "Jade", "Raphaël", "Emma", "Louis", "Alice",
+	"Lucas", "Chloé", "Hugo", "Léa", "Arthur", "Manon", "Jules", "Camille",
+	"Adam", "Inès", "Nathan", "Zoé", "Théo", "Juliette", "Paul", "Lina",
+	"Antoine", "Sarah", "Maxime", "Clara", "Thomas", "Margaux", "Nicolas", "Élise",
+	"Julien", "Mathilde", "Pierre", "Céline", "Olivier", "Sophie", "François", "Amélie",
+}
+
+var frLastNames = []string{
+	"Martin", "Bernard", "Thomas", "Petit", "Robert", "Richard", "Durand", "Dubois",
+	"Moreau", "Laurent", "Simon", "Michel", "Lefebvre", "Leroy", "Roux", "David",
+	"Bertrand", "Morel", "Fournier", "Girard", "Bonnet", "Dupont", "Lambert", "Fontaine",
+	"Rousseau", "Vincent", "Muller", "Lefèvre", "Faure", "André", "Mercier", "Blanc",
+	"Guérin", "Boyer", "Garnier", "Chevalier", "François", "Legrand", "Gauthier", "Garcia",
+}
+
+var frStreetNames = []string{
+	"rue de la République", "rue Victor Hugo", "avenue Jean Jaurès", "rue de la Paix",
+	"boulevard Gambetta", "rue Pasteur", "place de la Mairie", "rue de l'Église",
+	"rue du Moulin", "avenue de la Gare", "rue des Écoles", "rue Jules Ferry",
+	"rue du Château", "impasse des Lilas", "chemin des Vignes", "rue de la Liberté",
+	"avenue Foch", "rue Nationale", "rue du Général de Gaulle", "allée des Tilleuls",
+}
@@ -0,0 +1,229 @@
+package identity
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestLookupLocale(t *testing.T) {
+	for _, code := range []string{"US", "gb", " De ", "FR", "uk"} {
+		if _, err := LookupLocale(code); err != nil {
+			t.Errorf("LookupLocale(%q): %v", code, err)
+		}
+	}
+
+	_, err := LookupLocale("XX")
+	if err == nil {
+		t.Fatal("expected error for unknown locale")
+	}
+	if !strings.Contains(err.Error(), "DE, FR, GB, US") {
+		t.Errorf("error should list available locales: %v", err)
+	}
+}
+
+func TestLocales(t *testing.T) {
+	got := strings.Join(Locales(), ",")
+	if got != "DE,FR,GB,US" {
+		t.Errorf("Locales() = %s, want DE,FR,GB,US", got)
+	}
+}
+
+func TestGenerateLocaleFormats(t *testing.T) {
+	tests := []struct {
+		country  string
+		phone    *regexp.Regexp
+		postcode *regexp.Regexp
+		street   *regexp.Regexp
+	}{
+		{
+			country:  "US",
+			phone:    regexp.MustCompile(`^\(\d{3}\) 555-01\d{2}$`),
+			postcode: regexp.MustCompile(`^\d{5}$`),
+			street:   regexp.MustCompile(`^\d+ \S+ \S+$`),
+		},
+		{
+			country:  "GB",
+			phone:    regexp.MustCompile(`^\+44 7700 900\d{3}$`),
+			postcode: regexp.MustCompile(`^[A-Z]{1,2}\d{1,2} \d[ABD-HJLNP-UW-Z]{2}$`),
+			street:   regexp.MustCompile(`^\d+ .+ \S+$`),
+		},
+		{
+			country:  "DE",
+			phone:    regexp.MustCompile(`^\+49 (30 23125|40 66969|69 90009|221 4710|89 99998) \d{3}$`),
+			postcode: regexp.MustCompile(`^\d{5}$`),
+			street:   regexp.MustCompile(`^.+ \d+$`),
+		},
+		{
+			country:  "FR",
+			phone:    regexp.MustCompile(`^\+33 6 39 98 \d{2} \d{2}$`),
+			postcode: regexp.MustCompile(`^\d{5}$`),
+			street:   regexp.MustCompile(`^\d+ .+$`),
+		},
+	}
+
+	g := New()
+	for _, tt := range tests {
+		t.Run(tt.country, func(t *testing.T) {
+			l, err := LookupLocale(tt.country)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for range 20 {
+				id := g.Generate("", WithLocale(l))
+				if id.Country != tt.country {
+					t.Errorf("country = %q, want %q", id.Country, tt.country)
+				}
+				if !tt.phone.MatchString(id.Phone) {
+					t.Errorf("phone %q does not match %s", id.Phone, tt.phone)
+				}
+				if !tt.postcode.MatchString(id.Zip) {
+					t.Errorf("postcode %q does not match %s", id.Zip, tt.postcode)
+				}
+				if !tt.street.MatchString(id.Street) {
+					t.Errorf("street %q does not match %s", id.Street, tt.street)
+				}
+				if _, ok := findPlace(l, id); !ok {
+					t.Errorf("city/region %q/%q not from the %s data set", id.City, id.State, tt.country)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateDefaultLocale(t *testing.T) {
+	id := New().Generate("")
+	if id.Country != DefaultLocale {
+		t.Errorf("country = %q, want %q", id.Country, DefaultLocale)
+	}
+}
+
+func TestEmailASCII(t *testing.T) {
+	re := regexp.MustCompile(`^[a-z0-9.\-]+@zburn\.id$`)
+	g := New()
+	for range 50 {
+		email := g.Email("Élise", "Müller-Schäfer", "")
+		if !re.MatchString(email) {
+			t.Errorf("email %q is not ASCII", email)
+		}
+	}
+}
+
+func TestEmailPart(t *testing.T) {
+	tests := map[string]string{
+		"Müller":   "mueller",
+		"Jürgen":   "juergen",
+		"Chloé":    "chloe",
+		"Lefèvre":  "lefevre",
+		"Élise":    "elise",
+		"Queen's":  "queens",
+		"Jean-Luc": "jean-luc",
+	}
+	for in, want := range tests {
+		if got := emailPart(in); got != want {
+			t.Errorf("emailPart(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGenerateAddressCoherent(t *testing.T) {
+	g := New()
+	for _, code := range Locales() {
+		l, err := LookupLocale(code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for range 50 {
+			id := g.Generate("", WithLocale(l))
+			p, ok := findPlace(l, id)
+			if !ok {
+				t.Fatalf("%s: no place %q, %q", code, id.City, id.State)
+			}
+			if !matchesAny(p.Postcodes, id.Zip) {
+				t.Errorf("%s: postcode %q does not belong to %s (%v)", code, id.Zip, p.City, p.Postcodes)
+			}
+			if p.AreaCode != "" && !strings.HasPrefix(id.Phone, "("+p.AreaCode+")") {
+				t.Errorf("%s: phone %q should use %s area code %s", code, id.Phone, p.City, p.AreaCode)
+			}
+		}
+	}
+}
+
+func TestDEPhoneMatchesCity(t *testing.T) {
+	l, err := LookupLocale("DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		city string
+		want string
+	}{
+		{"Berlin", "+49 30 23125 "},
+		{"Köln", "+49 221 4710 "},
+		{"München", "+49 89 99998 "},
+	}
+
+	r := SeededRand("drama")
+	for _, tt := range tests {
+		if got := l.Phone(r, Place{City: tt.city}); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("%s phone = %q, want prefix %q", tt.city, got, tt.want)
+		}
+	}
+}
+
+func TestPlacesData(t *testing.T) {
+	for _, code := range Locales() {
+		l, _ := LookupLocale(code)
+		if len(l.Places) == 0 {
+			t.Errorf("%s: no places", code)
+		}
+		for _, p := range l.Places {
+			if p.City == "" || p.Region == "" || len(p.Postcodes) == 0 {
+				t.Errorf("%s: incomplete place %+v", code, p)
+			}
+		}
+	}
+}
+
+func TestUSPlacesHaveAreaCodes(t *testing.T) {
+	for _, p := range usPlaces {
+		if len(p.AreaCode) != 3 || len(p.Region) != 2 {
+			t.Errorf("place %+v needs a 3-digit area code and 2-letter state", p)
+		}
+	}
+}
+
+// findPlace returns the locale place matching the identity's city and region.
+func findPlace(l Locale, id Identity) (Place, bool) {
+	for _, p := range l.Places {
+		if p.City == id.City && p.Region == id.State {
+			return p, true
+		}
+	}
+	return Place{}, false
+}
+
+// matchesAny reports whether s fits one of the postcode patterns.
+func matchesAny(patterns []string, s string) bool {
+	for _, pat := range patterns {
+		if len(pat) != len(s) {
+			continue
+		}
+		ok := true
+		for i := 0; i < len(pat); i++ {
+			switch pat[i] {
+			case '#':
+				ok = ok && s[i] >= '0' && s[i] <= '9'
+			case '@':
+				ok = ok && s[i] >= 'A' && s[i] <= 'Z'
+			default:
+				ok = ok && s[i] == pat[i]
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,262 @@
+package identity
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+)
+
+// password character classes
+const (
+	lowerChars     = "abcdefghijklmnopqrstuvwxyz"
+	upperChars     = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars     = "0123456789"
+	DefaultSymbols = "!@#$%^&*()-_=+[]{}|;:,.<>?"
+)
+
+// password limits
+const (
+	DefaultPasswordLength = 20
+	MinPasswordLength     = 4
+	MaxPasswordLength     = 256
+
+	DefaultPassphraseWords = 5
+	MinPassphraseWords     = 3
+	MaxPassphraseWords     = 20
+)
+
+// PasswordPolicy describes the passwords a site accepts. Character
+// passwords include at least one character from each enabled class; a
+// passphrase joins random words and ignores the character settings.
+type PasswordPolicy struct {
+	Length  int  `json:"length"`
+	Lower   bool `json:"lower"`
+	Upper   bool `json:"upper"`
+	Digits  bool `json:"digits"`
+	Symbols bool `json:"symbols"`
+
+	// SymbolSet replaces DefaultSymbols, for sites that only accept some.
+	SymbolSet string `json:"symbol_set,omitempty"`
+	// Exclude lists characters never to use, e.g. look-alikes such as "l1O0".
+	Exclude string `json:"exclude,omitempty"`
+
+	Passphrase bool   `json:"passphrase,omitempty"`
+	Words      int    `json:"words,omitempty"`
+	Separator  string `json:"separator,omitempty"`
+}
+
+// DefaultPasswordPolicy is a 20 character password using every class.
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		Length:  DefaultPasswordLength,
+		Lower:   true,
+		Upper:   true,
+		Digits:  true,
+		Symbols: true,
+	}
+}
+
+// PassphrasePolicy is a diceware-style passphrase of the given word count.
+func PassphrasePolicy(words int) PasswordPolicy {
+	p := DefaultPasswordPolicy()
+	p.Passphrase = true
+	p.Words = words
+	p.Separator = "-"
+	return p
+}
+
+// classes returns the enabled character classes with excluded characters
+// removed.
+func (p PasswordPolicy) classes() []string {
+	symbols := DefaultSymbols
+	if p.SymbolSet != "" {
+		symbols = p.SymbolSet
+	}
+
+	var out []string
+	for _, c := range []struct {
+		on    bool
+		chars string
+	}{
+		{p.Lower, lowerChars},
+		{p.Upper, upperChars},
+		{p.Digits, digitChars},
+		{p.Symbols, symbols},
+	} {
+		if !c.on {
+			continue
+		}
+		out = append(out, strings.Map(func(r rune) rune {
+			if strings.ContainsRune(p.Exclude, r) {
+				return -1
+			}
+			return r
+		}, c.chars))
+	}
+	return out
+}
+
+// Validate reports a policy that cannot produce a password.
+func (p PasswordPolicy) Validate() error {
+	if p.Passphrase {
+		if p.Words < MinPassphraseWords || p.Words > MaxPassphraseWords {
+			return fmt.Errorf("passphrase words must be between %d and %d", MinPassphraseWords, MaxPassphraseWords)
+		}
+		return nil
+	}
+
+	if p.Length < MinPasswordLength || p.Length > MaxPasswordLength {
+		return fmt.Errorf("password length must be between %d and %d", MinPasswordLength, MaxPasswordLength)
+	}
+	classes := p.classes()
+	if len(classes) == 0 {
+		return fmt.Errorf("password policy enables no character classes")
+	}
+	for _, c := range classes {
+		if c == "" {
+			return fmt.Errorf("excluded characters leave a character class empty")
+		}
+	}
+	for _, r := range p.SymbolSet {
+		if r > '~' || r <= ' ' || strings.ContainsRune(lowerChars+upperChars+digitChars, r) {
+			return fmt.Errorf("symbol set may only contain printable ASCII symbols, got %q", r)
+		}
+	}
+	if len(classes) > p.Length {
+		return fmt.Errorf("password length %d is too short for %d character classes", p.Length, len(classes))
+	}
+	return nil
+}
+
+// String summarises the policy, e.g. "20 chars, a-z A-Z 0-9 symbols".
+func (p PasswordPolicy) String() string {
+	if p.Passphrase {
+		return fmt.Sprintf("%d-word passphrase", p.Words)
+	}
+
+	var classes []string
+	for _, c := range []struct {
+		on   bool
+		name string
+	}{
+		{p.Lower, "a-z"},
+		{p.Upper, "A-Z"},
+		{p.Digits, "0-9"},
+		{p.Symbols, "symbols"},
+	} {
+		if c.on {
+			classes = append(classes, c.name)
+		}
+	}
+	s := fmt.Sprintf("%d chars, %s", p.Length, strings.Join(classes, " "))
+	if p.Symbols && p.SymbolSet != "" {
+		s += " (" + p.SymbolSet + ")"
+	}
+	if p.Exclude != "" {
+		s += ", excluding " + p.Exclude
+	}
+	return s
+}
+
+// Entropy estimates the strength of a password from p in bits, assuming
+// an attacker knows the policy but not the random choices.
+func (p PasswordPolicy) Entropy() float64 {
+	if p.Passphrase {
+		return float64(p.Words) * math.Log2(float64(len(passphraseWords)))
+	}
+
+	chars := make(map[rune]bool)
+	for _, c := range p.classes() {
+		for _, r := range c {
+			chars[r] = true
+		}
+	}
+	if len(chars) == 0 {
+		return 0
+	}
+	return float64(p.Length) * math.Log2(float64(len(chars)))
+}
+
+// generate builds a password from r. Invalid policies are clamped to
+// something usable rather than failing; call Validate on user input.
+func (p PasswordPolicy) generate(r *Rand) string {
+	if p.Passphrase {
+		return p.passphrase(r)
+	}
+
+	classes := p.classes()
+	var charset string
+	for _, c := range classes {
+		charset += c
+	}
+	if charset == "" {
+		classes = DefaultPasswordPolicy().classes()
+		charset = strings.Join(classes, "")
+	}
+
+	length := max(p.Length, MinPasswordLength, len(classes))
+	buf := make([]byte, length)
+
+	// guarantee one from each class, fill the rest from the full charset
+	n := 0
+	for _, c := range classes {
+		if c != "" {
+			buf[n] = c[r.Intn(len(c))]
+			n++
+		}
+	}
+	for i := n; i < length; i++ {
+		buf[i] = charset[r.Intn(len(charset))]
+	}
+
+	// Fisher-Yates shuffle so the guaranteed characters move
+	for i := length - 1; i > 0; i-- {
+		j := r.Intn(i + 1)
+		buf[i], buf[j] = buf[j], buf[i]
+	}
+	return string(buf)
+}
+
+func (p PasswordPolicy) passphrase(r *Rand) string {
+	n := p.Words
+	if n < MinPassphraseWords {
+		n = DefaultPassphraseWords
+	}
+	words := make([]string, n)
+	for i := range words {
+		words[i] = r.Pick(passphraseWords)
+	}
+	return strings.Join(words, p.Separator)
+}
+
+// NewPassword generates a password that satisfies p.
+func NewPassword(p PasswordPolicy) string {
+	return p.generate(defaultRand)
+}
+
+// PasswordPreset is a password policy saved for one site.
+type PasswordPreset struct {
+	Site   string         `json:"site"`
+	Policy PasswordPolicy `json:"policy"`
+}
+
+// PresetKey is the store key for the preset of a site. Sites are
+// normalised with SiteName, so a login URL finds its site's preset.
+func PresetKey(site string) string {
+	return hashKey(SiteName(site))
+}
+
+// SiteName reduces a URL or host name to the bare host, e.g.
+// "https://www.Example.com/login" becomes "example.com".
+func SiteName(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Hostname() == "" {
+		return strings.TrimPrefix(strings.TrimPrefix(s, "https://"), "www.")
+	}
+	return strings.TrimPrefix(u.Hostname(), "www.")
+}
@@ -0,0 +1,142 @@
+package identity
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestPasswordForPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy PasswordPolicy
+		allow  string
+	}{
+		{"default", DefaultPasswordPolicy(), lowerChars + upperChars + digitChars + DefaultSymbols},
+		{"no symbols", PasswordPolicy{Length: 12, Lower: true, Upper: true, Digits: true}, lowerChars + upperChars + digitChars},
+		{"digits only", PasswordPolicy{Length: 6, Digits: true}, digitChars},
+		{"symbol set", PasswordPolicy{Length: 16, Lower: true, Symbols: true, SymbolSet: "!_"}, lowerChars + "!_"},
+		{"exclude", PasswordPolicy{Length: 64, Lower: true, Digits: true, Exclude: "l10o"}, "abcdefghijkmnpqrstuvwxyz23456789"},
+	}
+
+	g := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			for range 20 {
+				pw := g.PasswordFor(tt.policy)
+				if len(pw) != tt.policy.Length {
+					t.Fatalf("length = %d, want %d", len(pw), tt.policy.Length)
+				}
+				for _, r := range pw {
+					if !strings.ContainsRune(tt.allow, r) {
+						t.Fatalf("password %q contains %q", pw, r)
+					}
+				}
+				for _, class := range tt.policy.classes() {
+					if !strings.ContainsAny(pw, class) {
+						t.Fatalf("password %q has nothing from %q", pw, class)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPassphrase(t *testing.T) {
+	pw := New().PasswordFor(PassphrasePolicy(6))
+	words := strings.Split(pw, "-")
+	if len(words) != 6 {
+		t.Fatalf("passphrase %q has %d words, want 6", pw, len(words))
+	}
+	for _, w := range words {
+		if len(w) < 3 {
+			t.Errorf("unexpected word %q in %q", w, pw)
+		}
+	}
+
+	a := NewSeeded("pp").PasswordFor(PassphrasePolicy(4))
+	b := NewSeeded("pp").PasswordFor(PassphrasePolicy(4))
+	if a != b {
+		t.Errorf("seeded passphrases differ: %q, %q", a, b)
+	}
+}
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy PasswordPolicy
+		want   string
+	}{
+		{"default", DefaultPasswordPolicy(), ""},
+		{"passphrase", PassphrasePolicy(5), ""},
+		{"too short", PasswordPolicy{Length: 3, Lower: true}, "between"},
+		{"too long", PasswordPolicy{Length: 300, Lower: true}, "between"},
+		{"no classes", PasswordPolicy{Length: 10}, "no character classes"},
+		{"class excluded away", PasswordPolicy{Length: 10, Digits: true, Exclude: digitChars}, "empty"},
+		{"letters in symbol set", PasswordPolicy{Length: 10, Symbols: true, SymbolSet: "!a"}, "symbol set"},
+		{"too few words", PassphrasePolicy(2), "words"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPasswordPolicyString(t *testing.T) {
+	p := PasswordPolicy{Length: 12, Lower: true, Digits: true, Exclude: "0O"}
+	if got := p.String(); got != "12 chars, a-z 0-9, excluding 0O" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := PassphrasePolicy(5).String(); got != "5-word passphrase" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestPasswordEntropy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy PasswordPolicy
+		want   float64
+	}{
+		{"digits", PasswordPolicy{Length: 6, Digits: true}, 6 * math.Log2(10)},
+		{"lower excluding", PasswordPolicy{Length: 10, Lower: true, Exclude: "abcdefghijklmnop"}, 10 * math.Log2(10)},
+		{"passphrase", PassphrasePolicy(5), 55},
+		{"no classes", PasswordPolicy{Length: 10}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.Entropy(); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: Entropy() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSiteName(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"example.com", "example.com"},
+		{"https://www.Example.com/login?next=/", "example.com"},
+		{"http://accounts.example.com:8443", "accounts.example.com"},
+		{"  GitHub.com ", "github.com"},
+	}
+	for _, tt := range tests {
+		if got := SiteName(tt.in); got != tt.want {
+			t.Errorf("SiteName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	if PresetKey("https://www.example.com/x") != PresetKey("example.com") {
+		t.Error("a URL and its host should share a preset key")
+	}
+}
@@ -0,0 +1,164 @@
+package identity
+
+// Place is a city with the region, postcodes and area code that belong to it,
+// so a generated address passes the consistency checks signup forms run.
+type Place struct {
+	City   string
+	Region string
+	// Postcodes are patterns for the city's postcodes: '#' is replaced by a
+	// digit and '@' by one of the locale's postcode letters.
+	Postcodes []string
+	AreaCode  string // dialling code, for locales whose phone format uses it
+}
+
+var usPlaces = []Place{
+	{"New York", "NY", []string{"100##", "101##"}, "212"},
+	{"Los Angeles", "CA", []string{"900##"}, "213"},
+	{"Chicago", "IL", []string{"606##"}, "312"},
+	{"Houston", "TX", []string{"770##"}, "713"},
+	{"Phoenix", "AZ", []string{"850##"}, "602"},
+	{"Philadelphia", "PA", []string{"191##"}, "215"},
+	{"San Antonio", "TX", []string{"782##"}, "210"},
+	{"San Diego", "CA", []string{"921##"}, "619"},
+	{"Dallas", "TX", []string{"752##"}, "214"},
+	{"San Jose", "CA", []string{"951##"}, "408"},
+	{"Austin", "TX", []string{"787##"}, "512"},
+	{"Jacksonville", "FL", []string{"322##"}, "904"},
+	{"Fort Worth", "TX", []string{"761##"}, "817"},
+	{"Columbus", "OH", []string{"432##"}, "614"},
+	{"Indianapolis", "IN", []string{"462##"}, "317"},
+	{"Charlotte", "NC", []string{"282##"}, "704"},
+	{"San Francisco", "CA", []string{"941##"}, "415"},
+	{"Seattle", "WA", []string{"981##"}, "206"},
+	{"Denver", "CO", []string{"802##"}, "303"},
+	{"Nashville", "TN", []string{"372##"}, "615"},
+	{"Oklahoma City", "OK", []string{"731##"}, "405"},
+	{"El Paso", "TX", []string{"799##"}, "915"},
+	{"Boston", "MA", []string{"021##"}, "617"},
+	{"Portland", "OR", []string{"972##"}, "503"},
+	{"Las Vegas", "NV", []string{"891##"}, "702"},
+	{"Memphis", "TN", []string{"381##"}, "901"},
+	{"Louisville", "KY", []string{"402##"}, "502"},
+	{"Baltimore", "MD", []string{"212##"}, "410"},
+	{"Milwaukee", "WI", []string{"532##"}, "414"},
+	{"Albuquerque", "NM", []string{"871##"}, "505"},
+	{"Tucson", "AZ", []string{"857##"}, "520"},
+	{"Fresno", "CA", []string{"937##"}, "559"},
+	{"Sacramento", "CA", []string{"958##"}, "916"},
+	{"Mesa", "AZ", []string{"852##"}, "480"},
+	{"Kansas City", "MO", []string{"641##"}, "816"},
+	{"Atlanta", "GA", []string{"303##"}, "404"},
+	{"Omaha", "NE", []string{"681##"}, "402"},
+	{"Raleigh", "NC", []string{"276##"}, "919"},
+	{"Miami", "FL", []string{"331##"}, "305"},
+	{"Minneapolis", "MN", []string{"554##"}, "612"},
+	{"Tampa", "FL", []string{"336##"}, "813"},
+	{"New Orleans", "LA", []string{"701##"}, "504"},
+	{"Cleveland", "OH", []string{"441##"}, "216"},
+	{"Pittsburgh", "PA", []string{"152##"}, "412"},
+	{"Cincinnati", "OH", []string{"452##"}, "513"},
+	{"St. Louis", "MO", []string{"631##"}, "314"},
+	{"Orlando", "FL", []string{"328##"}, "407"},
+	{"Richmond", "VA", []string{"232##"}, "804"},
+	{"Salt Lake City", "UT", []string{"841##"}, "801"},
+	{"Honolulu", "HI", []string{"968##"}, "808"},
+}
+
+var gbPlaces = []Place{
+	{"London", "Greater London", []string{"E1 #@@", "N1 #@@", "NW1 #@@", "SE1 #@@", "SW1 #@@", "W1 #@@", "EC1 #@@", "WC1 #@@"}, ""},
+	{"Birmingham", "West Midlands", []string{"B1 #@@", "B5 #@@", "B15 #@@"}, ""},
+	{"Manchester", "Greater Manchester", []string{"M1 #@@", "M4 #@@", "M14 #@@"}, ""},
+	{"Leeds", "West Yorkshire", []string{"LS1 #@@", "LS6 #@@", "LS11 #@@"}, ""},
+	{"Glasgow", "Glasgow City", []string{"G1 #@@", "G3 #@@", "G12 #@@"}, ""},
+	{"Liverpool", "Merseyside", []string{"L1 #@@", "L8 #@@", "L17 #@@"}, ""},
+	{"Bristol", "Bristol", []string{"BS1 #@@", "BS6 #@@", "BS8 #@@"}, ""},
+	{"Sheffield", "South Yorkshire", []string{"S1 #@@", "S10 #@@", "S11 #@@"}, ""},
+	{"Edinburgh", "City of Edinburgh", []string{"EH1 #@@", "EH3 #@@", "EH9 #@@"}, ""},
+	{"Cardiff", "Cardiff", []string{"CF10 #@@", "CF11 #@@", "CF24 #@@"}, ""},
+	{"Leicester", "Leicestershire", []string{"LE1 #@@", "LE2 #@@"}, ""},
+	{"Nottingham", "Nottinghamshire", []string{"NG1 #@@", "NG7 #@@"}, ""},
+	{"Newcastle upon Tyne", "Tyne and Wear", []string{"NE1 #@@", "NE6 #@@"}, ""},
+	{"Belfast", "County Antrim", []string{"BT1 #@@", "BT7 #@@", "BT9 #@@"}, ""},
+	{"Brighton", "East Sussex", []string{"BN1 #@@", "BN2 #@@"}, ""},
+	{"Southampton", "Hampshire", []string{"SO14 #@@", "SO15 #@@"}, ""},
+	{"Plymouth", "Devon", []string{"PL1 #@@", "PL4 #@@"}, ""},
+	{"Reading", "Berkshire", []string{"RG1 #@@", "RG2 #@@"}, ""},
+	{"Oxford", "Oxfordshire", []string{"OX1 #@@", "OX4 #@@"}, ""},
+	{"Cambridge", "Cambridgeshire", []string{"CB1 #@@", "CB2 #@@"}, ""},
+	{"York", "North Yorkshire", []string{"YO1 #@@", "YO10 #@@"}, ""},
+	{"Bath", "Somerset", []string{"BA1 #@@", "BA2 #@@"}, ""},
+	{"Norwich", "Norfolk", []string{"NR1 #@@", "NR2 #@@"}, ""},
+	{"Exeter", "Devon", []string{"EX1 #@@", "EX4 #@@"}, ""},
+	{"Aberdeen", "City of Aberdeen", []string{"AB10 #@@", "AB24 #@@"}, ""},
+	{"Swansea", "West Glamorgan", []string{"SA1 #@@", "SA2 #@@"}, ""},
+	{"Coventry", "West Midlands", []string{"CV1 #@@", "CV5 #@@"}, ""},
+	{"Derby", "Derbyshire", []string{"DE1 #@@", "DE22 #@@"}, ""},
+	{"Hull", "East Riding of Yorkshire", []string{"HU1 #@@", "HU5 #@@"}, ""},
+	{"Sunderland", "Tyne and Wear", []string{"SR1 #@@", "SR2 #@@"}, ""},
+}
+
+var dePlaces = []Place{
+	{"Berlin", "Berlin", []string{"101##", "104##", "107##", "120##", "130##"}, ""},
+	{"Hamburg", "Hamburg", []string{"200##", "201##", "220##"}, ""},
+	{"München", "Bayern", []string{"803##", "806##", "813##"}, ""},
+	{"Köln", "Nordrhein-Westfalen", []string{"506##", "509##", "510##"}, ""},
+	{"Frankfurt am Main", "Hessen", []string{"603##", "604##", "605##"}, ""},
+	{"Stuttgart", "Baden-Württemberg", []string{"701##", "703##", "705##"}, ""},
+	{"Düsseldorf", "Nordrhein-Westfalen", []string{"402##", "404##", "405##"}, ""},
+	{"Leipzig", "Sachsen", []string{"041##", "043##"}, ""},
+	{"Dortmund", "Nordrhein-Westfalen", []string{"441##", "443##"}, ""},
+	{"Essen", "Nordrhein-Westfalen", []string{"451##", "452##"}, ""},
+	{"Bremen", "Bremen", []string{"281##", "282##"}, ""},
+	{"Dresden", "Sachsen", []string{"010##", "011##", "013##"}, ""},
+	{"Hannover", "Niedersachsen", []string{"301##", "304##", "306##"}, ""},
+	{"Nürnberg", "Bayern", []string{"904##", "905##"}, ""},
+	{"Duisburg", "Nordrhein-Westfalen", []string{"470##", "471##"}, ""},
+	{"Bochum", "Nordrhein-Westfalen", []string{"447##", "448##"}, ""},
+	{"Wuppertal", "Nordrhein-Westfalen", []string{"421##", "422##"}, ""},
+	{"Bielefeld", "Nordrhein-Westfalen", []string{"336##", "337##"}, ""},
+	{"Bonn", "Nordrhein-Westfalen", []string{"531##"}, ""},
+	{"Münster", "Nordrhein-Westfalen", []string{"481##"}, ""},
+	{"Mannheim", "Baden-Württemberg", []string{"681##", "682##"}, ""},
+	{"Karlsruhe", "Baden-Württemberg", []string{"761##"}, ""},
+	{"Augsburg", "Bayern", []string{"861##"}, ""},
+	{"Wiesbaden", "Hessen", []string{"651##"}, ""},
+	{"Freiburg im Breisgau", "Baden-Württemberg", []string{"790##", "791##"}, ""},
+	{"Kiel", "Schleswig-Holstein", []string{"241##"}, ""},
+	{"Rostock", "Mecklenburg-Vorpommern", []string{"180##", "181##"}, ""},
+	{"Heidelberg", "Baden-Württemberg", []string{"691##"}, ""},
+	{"Potsdam", "Brandenburg", []string{"144##"}, ""},
+	{"Regensburg", "Bayern", []string{"930##"}, ""},
+}
+
+var frPlaces = []Place{
+	{"Paris", "Île-de-France", []string{"7500#", "7501#"}, ""},
+	{"Marseille", "Provence-Alpes-Côte d'Azur", []string{"1300#", "1301#"}, ""},
+	{"Lyon", "Auvergne-Rhône-Alpes", []string{"6900#"}, ""},
+	{"Toulouse", "Occitanie", []string{"31000", "31100", "31200", "31300", "31400", "31500"}, ""},
+	{"Nice", "Provence-Alpes-Côte d'Azur", []string{"06000", "06100", "06200", "06300"}, ""},
+	{"Nantes", "Pays de la Loire", []string{"44000", "44100", "44200", "44300"}, ""},
+	{"Montpellier", "Occitanie", []string{"34000", "34070", "34080", "34090"}, ""},
+	{"Strasbourg", "Grand Est", []string{"67000", "67100", "67200"}, ""},
+	{"Bordeaux", "Nouvelle-Aquitaine", []string{"33000", "33100", "33200", "33300", "33800"}, ""},
+	{"Lille", "Hauts-de-France", []string{"59000", "59160", "59260", "59800"}, ""},
+	{"Rennes", "Bretagne", []string{"35000", "35200", "35700"}, ""},
+	{"Reims", "Grand Est", []string{"51100"}, ""},
+	{"Toulon", "Provence-Alpes-Côte d'Azur", []string{"83000", "83100", "83200"}, ""},
+	{"Saint-Étienne", "Auvergne-Rhône-Alpes", []string{"42000", "42100"}, ""},
+	{"Le Havre", "Normandie", []string{"76600", "76610", "76620"}, ""},
+	{"Grenoble", "Auvergne-Rhône-Alpes", []string{"38000", "38100"}, ""},
+	{"Dijon", "Bourgogne-Franche-Comté", []string{"21000"}, ""},
+	{"Angers", "Pays de la Loire", []string{"49000", "49100"}, ""},
+	{"Nîmes", "Occitanie", []string{"30000", "30900"}, ""},
+	{"Clermont-Ferrand", "Auvergne-Rhône-Alpes", []string{"63000", "63100"}, ""},
+	{"Aix-en-Provence", "Provence-Alpes-Côte d'Azur", []string{"13090", "13100"}, ""},
+	{"Brest", "Bretagne", []string{"29200"}, ""},
+	{"Tours", "Centre-Val de Loire", []string{"37000", "37100", "37200"}, ""},
+	{"Amiens", "Hauts-de-France", []string{"80000", "80080", "80090"}, ""},
+	{"Limoges", "Nouvelle-Aquitaine", []string{"87000", "87100"}, ""},
+	{"Annecy", "Auvergne-Rhône-Alpes", []string{"74000"}, ""},
+	{"Perpignan", "Occitanie", []string{"66000", "66100"}, ""},
+	{"Metz", "Grand Est", []string{"57000", "57050", "57070"}, ""},
+	{"Besançon", "Bourgogne-Franche-Comté", []string{"25000"}, ""},
+	{"Orléans", "Centre-Val de Loire", []string{"45000", "45100"}, ""},
+}
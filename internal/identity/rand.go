@@ -0,0 +1,63 @@
+package identity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	mrand "math/rand/v2"
+	"sync"
+)
+
+// Rand draws uniform values from a byte source. Generators use crypto/rand
+// unless they are built from a seed or an explicit source.
+type Rand struct {
+	mu  sync.Mutex
+	src io.Reader
+}
+
+// NewRand returns a Rand reading from src.
+func NewRand(src io.Reader) *Rand {
+	return &Rand{src: src}
+}
+
+// SeededRand returns a Rand whose output is fully determined by seed.
+// The seed is hashed into a ChaCha8 key, so any string works.
+func SeededRand(seed string) *Rand {
+	return NewRand(mrand.NewChaCha8(sha256.Sum256([]byte(seed))))
+}
+
+// defaultRand is the crypto/rand source used by New and the package-level
+// helpers.
+var defaultRand = NewRand(rand.Reader)
+
+// Intn returns a uniform int in [0, n). It panics if n <= 0.
+func (r *Rand) Intn(n int) int {
+	if n <= 0 {
+		panic("identity: Intn called with n <= 0")
+	}
+	bound := uint64(n)
+	// reject the low values that would bias the modulo
+	threshold := -bound % bound
+	var b [8]byte
+	for {
+		r.Read(b[:])
+		if v := binary.BigEndian.Uint64(b[:]); v >= threshold {
+			return int(v % bound)
+		}
+	}
+}
+
+// Pick returns a uniformly chosen element of s.
+func (r *Rand) Pick(s []string) string {
+	return s[r.Intn(len(s))]
+}
+
+// Read fills b from the source. A failing source is unrecoverable.
+func (r *Rand) Read(b []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := io.ReadFull(r.src, b); err != nil {
+		panic("identity: read random source: " + err.Error())
+	}
+}
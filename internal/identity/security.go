@@ -0,0 +1,65 @@
+package identity
+
+// numSecurityAnswers is how many security questions each identity answers.
+const numSecurityAnswers = 3
+
+// SecurityAnswer is a generated answer to a common signup security question.
+type SecurityAnswer struct {
+	Topic    string `json:"topic"` // short label, e.g. "first pet"
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+type securityQuestion struct {
+	topic    string
+	question string
+	answer   func(r *Rand, l Locale) string
+}
+
+// securityQuestions are the questions signup forms ask most often. Answers
+// come from the identity's locale where that makes them plausible.
+var securityQuestions = []securityQuestion{
+	{"maiden name", "What is your mother's maiden name?", func(r *Rand, l Locale) string {
+		return r.Pick(l.LastNames)
+	}},
+	{"first pet", "What was the name of your first pet?", func(r *Rand, _ Locale) string {
+		return r.Pick(petNames)
+	}},
+	{"birth city", "In what city were you born?", func(r *Rand, l Locale) string {
+		return l.Places[r.Intn(len(l.Places))].City
+	}},
+	{"first school", "What was the name of your first school?", func(r *Rand, _ Locale) string {
+		return r.Pick(companyPrefixes) + " School"
+	}},
+	{"first car", "What was the make of your first car?", func(r *Rand, _ Locale) string {
+		return r.Pick(carMakes)
+	}},
+	{"favourite food", "What is your favourite food?", func(r *Rand, _ Locale) string {
+		return r.Pick(foods)
+	}},
+	{"best friend", "What was the first name of your childhood best friend?", func(r *Rand, l Locale) string {
+		return r.Pick(l.FirstNames)
+	}},
+}
+
+// securityAnswers answers numSecurityAnswers distinct questions.
+func (g *Generator) securityAnswers(l Locale) []SecurityAnswer {
+	idx := make([]int, len(securityQuestions))
+	for i := range idx {
+		idx[i] = i
+	}
+
+	out := make([]SecurityAnswer, 0, numSecurityAnswers)
+	for i := range numSecurityAnswers {
+		// partial Fisher-Yates: choose without repeats
+		j := i + g.rand.Intn(len(idx)-i)
+		idx[i], idx[j] = idx[j], idx[i]
+		q := securityQuestions[idx[i]]
+		out = append(out, SecurityAnswer{
+			Topic:    q.topic,
+			Question: q.question,
+			Answer:   q.answer(g.rand, l),
+		})
+	}
+	return out
+}
@@ -0,0 +1,207 @@
+package identity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Field is a user-defined attribute added to an identity by a template.
+type Field struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Template declares extra fields to add to every identity generated with it.
+// Templates are saved in the encrypted store so they can be reused.
+type Template struct {
+	Name   string          `json:"name"`
+	Fields []TemplateField `json:"fields"`
+}
+
+// TemplateField is one declared field: a fixed Value, or a value drawn from
+// the named Generator each time an identity is generated.
+type TemplateField struct {
+	Name      string   `json:"name"`
+	Generator string   `json:"generator,omitempty"`
+	Value     string   `json:"value,omitempty"`
+	Choices   []string `json:"choices,omitempty"` // for the "choice" generator
+}
+
+// TemplateKey is the store key for a template name. Names are matched
+// case-insensitively and hashed so they do not appear in file names.
+func TemplateKey(name string) string {
+	return hashKey(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// hashKey turns a user-chosen name into a store key that does not reveal it.
+func hashKey(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:8])
+}
+
+// fieldGenerator produces a field value for an identity being generated.
+type fieldGenerator func(r *Rand, id Identity, f TemplateField) string
+
+// fieldGenerators are the generators a TemplateField may name.
+var fieldGenerators = map[string]fieldGenerator{
+	"username": func(r *Rand, _ Identity, _ TemplateField) string {
+		return handle(r)
+	},
+	"company": func(r *Rand, _ Identity, _ TemplateField) string {
+		return company(r)
+	},
+	"job_title": func(r *Rand, _ Identity, _ TemplateField) string {
+		return jobTitle(r)
+	},
+	"security_answer": func(r *Rand, _ Identity, _ TemplateField) string {
+		return r.Pick(adjectives) + " " + r.Pick(nouns) + " " + fmt.Sprintf("%02d", r.Intn(100))
+	},
+	"gender": func(r *Rand, _ Identity, _ TemplateField) string {
+		return r.Pick(genders)
+	},
+	"colour": func(r *Rand, _ Identity, _ TemplateField) string {
+		return r.Pick(colours)
+	},
+	"word": func(r *Rand, _ Identity, _ TemplateField) string {
+		return r.Pick(adjectives) + r.Pick(nouns)
+	},
+	"digits": func(r *Rand, _ Identity, _ TemplateField) string {
+		return fmt.Sprintf("%06d", r.Intn(1000000))
+	},
+	"choice": func(r *Rand, _ Identity, f TemplateField) string {
+		if len(f.Choices) == 0 {
+			return ""
+		}
+		return r.Pick(f.Choices)
+	},
+}
+
+// generatorAliases maps alternative spellings to a generator name.
+var generatorAliases = map[string]string{
+	"color":    "colour",
+	"handle":   "username",
+	"jobtitle": "job_title",
+	"title":    "job_title",
+	"security": "security_answer",
+}
+
+// FieldGenerators returns the generator names a template field may use.
+func FieldGenerators() []string {
+	names := make([]string, 0, len(fieldGenerators))
+	for n := range fieldGenerators {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// canonicalGenerator resolves aliases and case.
+func canonicalGenerator(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if c, ok := generatorAliases[name]; ok {
+		return c
+	}
+	return name
+}
+
+// Validate checks that the template has a name, its field names are set and
+// unique, and each field names a known generator or has a fixed value.
+func (t Template) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("template name is required")
+	}
+	if len(t.Fields) == 0 {
+		return fmt.Errorf("template %q has no fields", t.Name)
+	}
+
+	seen := make(map[string]bool, len(t.Fields))
+	for _, f := range t.Fields {
+		name := strings.ToLower(strings.TrimSpace(f.Name))
+		if name == "" {
+			return fmt.Errorf("template %q: field name is required", t.Name)
+		}
+		if seen[name] {
+			return fmt.Errorf("template %q: duplicate field %q", t.Name, f.Name)
+		}
+		seen[name] = true
+
+		if f.Generator == "" {
+			continue
+		}
+		gen := canonicalGenerator(f.Generator)
+		if _, ok := fieldGenerators[gen]; !ok {
+			return fmt.Errorf("field %q: unknown generator %q (available: %s)", f.Name, f.Generator, strings.Join(FieldGenerators(), ", "))
+		}
+		if gen == "choice" && len(f.Choices) == 0 {
+			return fmt.Errorf("field %q: choice generator needs at least one choice", f.Name)
+		}
+	}
+	return nil
+}
+
+// WithTemplate adds the template's fields to the generated identity.
+func WithTemplate(t Template) Option {
+	return func(o *options) {
+		o.template = &t
+	}
+}
+
+// ApplyTemplate returns id with its template fields replaced by freshly
+// generated values from t. Unknown generators produce an empty value;
+// call Template.Validate before saving a template.
+func (g *Generator) ApplyTemplate(id Identity, t Template) Identity {
+	fields := make([]Field, 0, len(t.Fields))
+	for _, tf := range t.Fields {
+		v := tf.Value
+		if gen, ok := fieldGenerators[canonicalGenerator(tf.Generator)]; ok {
+			v = gen(g.rand, id, tf)
+		}
+		fields = append(fields, Field{Name: strings.TrimSpace(tf.Name), Value: v})
+	}
+	id.Template = t.Name
+	id.Fields = fields
+	return id
+}
+
+// FieldValue returns the value of the named template field, matched
+// case-insensitively.
+func (id Identity) FieldValue(name string) (string, bool) {
+	for _, f := range id.Fields {
+		if strings.EqualFold(f.Name, name) {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+// ParseTemplateField parses a command-line field spec:
+//
+//	name=value               fixed value
+//	name:generator           generated, e.g. username:username
+//	name:choice=a,b,c        one of the listed values
+func ParseTemplateField(spec string) (TemplateField, error) {
+	nameEnd := strings.IndexAny(spec, "=:")
+	if nameEnd <= 0 {
+		return TemplateField{}, fmt.Errorf("invalid field %q: want name=value or name:generator", spec)
+	}
+
+	name := strings.TrimSpace(spec[:nameEnd])
+	rest := spec[nameEnd+1:]
+	if spec[nameEnd] == '=' {
+		return TemplateField{Name: name, Value: rest}, nil
+	}
+
+	gen, choices, hasChoices := strings.Cut(rest, "=")
+	f := TemplateField{Name: name, Generator: canonicalGenerator(gen)}
+	if hasChoices {
+		for _, c := range strings.Split(choices, ",") {
+			if c = strings.TrimSpace(c); c != "" {
+				f.Choices = append(f.Choices, c)
+			}
+		}
+	}
+	return f, nil
+}
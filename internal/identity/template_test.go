@@ -0,0 +1,127 @@
+package identity
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func testTemplate() Template {
+	return Template{
+		Name: "signup",
+		Fields: []TemplateField{
+			{Name: "username", Generator: "username"},
+			{Name: "company", Generator: "company"},
+			{Name: "colour", Generator: "color"},
+			{Name: "tier", Generator: "choice", Choices: []string{"free", "pro"}},
+			{Name: "source", Value: "qa"},
+		},
+	}
+}
+
+func TestGenerateWithTemplate(t *testing.T) {
+	id := New().Generate("", WithTemplate(testTemplate()))
+
+	if id.Template != "signup" {
+		t.Errorf("Template = %q, want signup", id.Template)
+	}
+	if len(id.Fields) != 5 {
+		t.Fatalf("got %d fields, want 5: %+v", len(id.Fields), id.Fields)
+	}
+	for _, f := range id.Fields {
+		if f.Value == "" {
+			t.Errorf("field %q is empty", f.Name)
+		}
+	}
+	if v, _ := id.FieldValue("SOURCE"); v != "qa" {
+		t.Errorf("fixed field = %q, want qa", v)
+	}
+	if v, _ := id.FieldValue("tier"); v != "free" && v != "pro" {
+		t.Errorf("choice field = %q, want free or pro", v)
+	}
+	if v, _ := id.FieldValue("colour"); !slices.Contains(colours, v) {
+		t.Errorf("colour alias produced %q", v)
+	}
+}
+
+func TestGenerateWithoutTemplate(t *testing.T) {
+	id := New().Generate("")
+	if id.Template != "" || id.Fields != nil {
+		t.Errorf("plain identity should have no template fields: %+v", id)
+	}
+
+	b, err := json.Marshal(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), `"fields"`) {
+		t.Errorf("empty fields should be omitted: %s", b)
+	}
+}
+
+func TestApplyTemplateSeeded(t *testing.T) {
+	a := NewSeeded("tpl").Generate("", WithTemplate(testTemplate()))
+	b := NewSeeded("tpl").Generate("", WithTemplate(testTemplate()))
+	if !slices.Equal(a.Fields, b.Fields) {
+		t.Errorf("seeded template fields differ:\n%+v\n%+v", a.Fields, b.Fields)
+	}
+}
+
+func TestTemplateValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		tpl  Template
+		want string
+	}{
+		{"valid", testTemplate(), ""},
+		{"no name", Template{Fields: []TemplateField{{Name: "a", Value: "b"}}}, "name is required"},
+		{"no fields", Template{Name: "x"}, "no fields"},
+		{"empty field name", Template{Name: "x", Fields: []TemplateField{{Value: "b"}}}, "field name is required"},
+		{"duplicate", Template{Name: "x", Fields: []TemplateField{{Name: "a"}, {Name: "A"}}}, "duplicate"},
+		{"unknown generator", Template{Name: "x", Fields: []TemplateField{{Name: "a", Generator: "nope"}}}, "unknown generator"},
+		{"choice without choices", Template{Name: "x", Fields: []TemplateField{{Name: "a", Generator: "choice"}}}, "at least one choice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.tpl.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTemplateField(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    TemplateField
+		wantErr bool
+	}{
+		{"source=qa run", TemplateField{Name: "source", Value: "qa run"}, false},
+		{"handle:username", TemplateField{Name: "handle", Generator: "username"}, false},
+		{"fav colour:Color", TemplateField{Name: "fav colour", Generator: "colour"}, false},
+		{"tier:choice=free, pro", TemplateField{Name: "tier", Generator: "choice", Choices: []string{"free", "pro"}}, false},
+		{"url=https://a.b/c?d=e", TemplateField{Name: "url", Value: "https://a.b/c?d=e"}, false},
+		{"novalue", TemplateField{}, true},
+		{"=x", TemplateField{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseTemplateField(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTemplateField(%q) error = %v", tt.spec, err)
+			continue
+		}
+		if got.Name != tt.want.Name || got.Generator != tt.want.Generator || got.Value != tt.want.Value || !slices.Equal(got.Choices, tt.want.Choices) {
+			t.Errorf("ParseTemplateField(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+}
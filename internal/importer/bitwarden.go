@@ -0,0 +1,59 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// bitwardenLogin is the Bitwarden item type for logins.
+const bitwardenLogin = 1
+
+type bitwardenExport struct {
+	Encrypted bool            `json:"encrypted"`
+	Items     []bitwardenItem `json:"items"`
+}
+
+type bitwardenItem struct {
+	Type  int    `json:"type"`
+	Name  string `json:"name"`
+	Notes string `json:"notes"`
+	Login *struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		TOTP     string `json:"totp"`
+		URIs     []struct {
+			URI string `json:"uri"`
+		} `json:"uris"`
+	} `json:"login"`
+}
+
+func parseBitwarden(data []byte) ([]Entry, int, error) {
+	var exp bitwardenExport
+	if err := json.Unmarshal(data, &exp); err != nil {
+		return nil, 0, fmt.Errorf("parse bitwarden export: %w", err)
+	}
+	if exp.Encrypted {
+		return nil, 0, fmt.Errorf("parse bitwarden export: encrypted exports are not supported, export as unencrypted json")
+	}
+
+	var entries []Entry
+	skipped := 0
+	for _, it := range exp.Items {
+		if it.Type != bitwardenLogin || it.Login == nil {
+			skipped++
+			continue
+		}
+		e := Entry{
+			Title:    it.Name,
+			Username: it.Login.Username,
+			Password: it.Login.Password,
+			TOTP:     it.Login.TOTP,
+			Notes:    it.Notes,
+		}
+		if len(it.Login.URIs) > 0 {
+			e.URL = it.Login.URIs[0].URI
+		}
+		entries = append(entries, e)
+	}
+	return entries, skipped, nil
+}
@@ -0,0 +1,62 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+)
+
+const bitwardenExportJSON = `{
+  "encrypted": false,
+  "folders": [],
+  "items": [
+    {
+      "type": 1,
+      "name": "GitHub",
+      "notes": "work account",
+      "login": {
+        "username": "jane@zburn.id",
+        "password": "s3cret",
+        "totp": "otpauth://totp/GitHub:jane?secret=JBSWY3DPEHPK3PXP&issuer=GitHub",
+        "uris": [{"match": null, "uri": "https://github.com/login"}]
+      }
+    },
+    {"type": 2, "name": "a secure note", "notes": "hello", "secureNote": {"type": 0}},
+    {"type": 3, "name": "Visa", "card": {"number": "4111"}},
+    {"type": 1, "name": "Forum", "login": {"username": "janed", "password": "pw", "uris": []}}
+  ]
+}`
+
+func TestParseBitwarden(t *testing.T) {
+	entries, skipped, err := Parse(Bitwarden, []byte(bitwardenExportJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skipped != 2 {
+		t.Errorf("skipped = %d, want 2", skipped)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("entries = %d, want 2", len(entries))
+	}
+
+	want := Entry{
+		Title:    "GitHub",
+		URL:      "https://github.com/login",
+		Username: "jane@zburn.id",
+		Password: "s3cret",
+		TOTP:     "otpauth://totp/GitHub:jane?secret=JBSWY3DPEHPK3PXP&issuer=GitHub",
+		Notes:    "work account",
+	}
+	if entries[0] != want {
+		t.Errorf("entry = %+v, want %+v", entries[0], want)
+	}
+	if entries[1].URL != "" || entries[1].Username != "janed" {
+		t.Errorf("entry = %+v", entries[1])
+	}
+}
+
+func TestParseBitwardenEncrypted(t *testing.T) {
+	_, _, err := Parse(Bitwarden, []byte(`{"encrypted": true, "items": []}`))
+	if err == nil || !strings.Contains(err.Error(), "encrypted") {
+		t.Errorf("err = %v, want encrypted export error", err)
+	}
+}
@@ -0,0 +1,252 @@
+// Package importer reads password-manager exports and plans how their
+// logins become zburn credentials.
+//
+// Parsing and planning have no side effects: the caller shows the plan as
+// a dry-run report and saves the identities and credentials it lists.
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zarlcorp/zburn/internal/credential"
+	"github.com/zarlcorp/zburn/internal/identity"
+)
+
+// Format is a supported export format.
+type Format string
+
+const (
+	Bitwarden    Format = "bitwarden"     // Bitwarden unencrypted .json export
+	OnePassword  Format = "1password-csv" // 1Password .csv export
+	OnePasswordX Format = "1pux"          // 1Password .1pux export
+	KeePass      Format = "keepass"       // KeePass 2 / KeePassXC .xml export
+)
+
+// Formats lists the supported formats in the order they are documented.
+var Formats = []Format{Bitwarden, OnePassword, OnePasswordX, KeePass}
+
+// Entry is a login read from an export, before it is matched to an
+// identity.
+type Entry struct {
+	Title    string
+	URL      string
+	Username string
+	Password string
+	TOTP     string // base32 secret or otpauth:// URI
+	Notes    string
+}
+
+// Detect guesses the format of an export from its file name, falling back
+// to its content.
+func Detect(name string, data []byte) (Format, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return Bitwarden, nil
+	case ".csv":
+		return OnePassword, nil
+	case ".1pux":
+		return OnePasswordX, nil
+	case ".xml":
+		return KeePass, nil
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		return OnePasswordX, nil
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return Bitwarden, nil
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return KeePass, nil
+	}
+	return "", fmt.Errorf("cannot tell the format of %s; use --format", name)
+}
+
+// ParseFormat validates a --format value.
+func ParseFormat(s string) (Format, error) {
+	f := Format(strings.ToLower(strings.TrimSpace(s)))
+	if !slices.Contains(Formats, f) {
+		return "", fmt.Errorf("unknown format %q", s)
+	}
+	return f, nil
+}
+
+// Parse reads the logins in an export. Items that are not logins, such as
+// cards or secure notes, are counted in skipped.
+func Parse(f Format, data []byte) (entries []Entry, skipped int, err error) {
+	switch f {
+	case Bitwarden:
+		return parseBitwarden(data)
+	case OnePassword:
+		return parseOnePasswordCSV(data)
+	case OnePasswordX:
+		return parseOnePUX(data)
+	case KeePass:
+		return parseKeePass(data)
+	}
+	return nil, 0, fmt.Errorf("unknown format %q", f)
+}
+
+// Action is what an import does with one entry.
+type Action string
+
+const (
+	ActionAdd  Action = "add"
+	ActionSkip Action = "skip"
+)
+
+// Item is the plan for one entry.
+type Item struct {
+	Action      Action
+	Reason      string // why the entry is skipped
+	Warning     string // a problem that did not stop the import
+	Credential  credential.Credential
+	Identity    identity.Identity
+	NewIdentity bool
+}
+
+// Plan is the dry-run report of an import.
+type Plan struct {
+	Items         []Item
+	NewIdentities []identity.Identity
+}
+
+// Count returns the number of items with action a.
+func (p Plan) Count(a Action) int {
+	n := 0
+	for _, it := range p.Items {
+		if it.Action == a {
+			n++
+		}
+	}
+	return n
+}
+
+// Credentials returns the credentials the plan adds.
+func (p Plan) Credentials() []credential.Credential {
+	var out []credential.Credential
+	for _, it := range p.Items {
+		if it.Action == ActionAdd {
+			out = append(out, it.Credential)
+		}
+	}
+	return out
+}
+
+// Options controls how entries are matched to identities.
+type Options struct {
+	// Fallback receives entries that match no identity. When nil, such
+	// entries get a new identity from NewIdentity, one per username.
+	Fallback *identity.Identity
+	// NewIdentity generates an identity for an unmatched username, which
+	// may be an email address. Entries without a username are skipped when
+	// it is needed.
+	NewIdentity func(username string) identity.Identity
+	// Now stamps the new credentials.
+	Now time.Time
+}
+
+// PlanImport matches entries to identities by email or username, ignoring
+// case, and skips entries already saved with the same label and username.
+func PlanImport(entries []Entry, ids []identity.Identity, existing []credential.Credential, opts Options) Plan {
+	var plan Plan
+	created := make(map[string]identity.Identity) // new identities by lowercased username
+
+	for _, e := range entries {
+		c := credential.Credential{
+			ID:        credential.NewID(),
+			Label:     strings.TrimSpace(e.Title),
+			URL:       strings.TrimSpace(e.URL),
+			Username:  strings.TrimSpace(e.Username),
+			Password:  e.Password,
+			Notes:     strings.TrimSpace(e.Notes),
+			CreatedAt: opts.Now,
+			UpdatedAt: opts.Now,
+		}
+		if c.Label == "" {
+			c.Label = labelFromURL(c.URL)
+		}
+		item := Item{Action: ActionAdd}
+		if err := c.SetTOTP(e.TOTP); err != nil {
+			item.Warning = "totp not imported: " + err.Error()
+		}
+
+		if c.Label == "" {
+			item.Action, item.Reason = ActionSkip, "no title or url"
+			item.Credential = c
+			plan.Items = append(plan.Items, item)
+			continue
+		}
+
+		id, ok := matchIdentity(ids, c.Username)
+		switch {
+		case ok:
+		case opts.Fallback != nil:
+			id = *opts.Fallback
+		case c.Username == "" || opts.NewIdentity == nil:
+			item.Action, item.Reason = ActionSkip, "no identity matches and no username to create one from"
+			item.Credential = c
+			plan.Items = append(plan.Items, item)
+			continue
+		default:
+			key := strings.ToLower(c.Username)
+			id, ok = created[key]
+			if !ok {
+				id = opts.NewIdentity(c.Username)
+				created[key] = id
+				plan.NewIdentities = append(plan.NewIdentities, id)
+			}
+			item.NewIdentity = true
+		}
+
+		c.IdentityID = id.ID
+		item.Credential = c
+		item.Identity = id
+
+		if isDuplicate(c, existing) || isDuplicate(c, plan.Credentials()) {
+			item.Action, item.Reason = ActionSkip, "already saved"
+		}
+		plan.Items = append(plan.Items, item)
+	}
+	return plan
+}
+
+// matchIdentity finds the identity whose email or username is username.
+func matchIdentity(ids []identity.Identity, username string) (identity.Identity, bool) {
+	if username == "" {
+		return identity.Identity{}, false
+	}
+	for _, id := range ids {
+		if strings.EqualFold(id.Email, username) {
+			return id, true
+		}
+	}
+	for _, id := range ids {
+		if id.Username != "" && strings.EqualFold(id.Username, username) {
+			return id, true
+		}
+	}
+	return identity.Identity{}, false
+}
+
+// isDuplicate reports whether creds already hold c for the same identity.
+func isDuplicate(c credential.Credential, creds []credential.Credential) bool {
+	return slices.ContainsFunc(creds, func(o credential.Credential) bool {
+		return o.IdentityID == c.IdentityID &&
+			strings.EqualFold(o.Label, c.Label) &&
+			strings.EqualFold(o.Username, c.Username)
+	})
+}
+
+// labelFromURL names an untitled entry after its site.
+func labelFromURL(u string) string {
+	if u == "" {
+		return ""
+	}
+	return identity.SiteName(u)
+}
@@ -0,0 +1,178 @@
+package importer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zarlcorp/zburn/internal/credential"
+	"github.com/zarlcorp/zburn/internal/identity"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want Format
+	}{
+		{"export.json", "", Bitwarden},
+		{"1Password.CSV", "", OnePassword},
+		{"export.1pux", "", OnePasswordX},
+		{"vault.xml", "", KeePass},
+		{"export", "PK\x03\x04rest", OnePasswordX},
+		{"export", "  {\"items\": []}", Bitwarden},
+		{"export", "<?xml version=\"1.0\"?>", KeePass},
+	}
+	for _, tt := range tests {
+		got, err := Detect(tt.name, []byte(tt.data))
+		if err != nil || got != tt.want {
+			t.Errorf("Detect(%q) = %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+	}
+
+	if _, err := Detect("export.txt", []byte("hello")); err == nil {
+		t.Error("expected error for unknown content")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := ParseFormat(" KeePass "); err != nil || f != KeePass {
+		t.Errorf("ParseFormat = %q, %v", f, err)
+	}
+	if _, err := ParseFormat("lastpass"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func planIdentities() []identity.Identity {
+	return []identity.Identity{
+		{ID: "jane", Email: "jane@zburn.id", Username: "janed"},
+		{ID: "bob", Email: "bob@zburn.id"},
+	}
+}
+
+func newIdentity(username string) identity.Identity {
+	return identity.Identity{ID: "new-" + username, Email: username}
+}
+
+func TestPlanImportMatching(t *testing.T) {
+	entries := []Entry{
+		{Title: "GitHub", Username: "JANE@zburn.id", Password: "a"},
+		{Title: "Forum", Username: "janed", Password: "b"},
+		{Title: "Shop", Username: "new@example.com", Password: "c"},
+		{Title: "Other shop", Username: "new@example.com", Password: "d"},
+		{URL: "https://www.untitled.example/login", Username: "bob@zburn.id"},
+		{Title: "No user", Password: "e"},
+		{Password: "f"},
+	}
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	plan := PlanImport(entries, planIdentities(), nil, Options{NewIdentity: newIdentity, Now: now})
+
+	want := []struct {
+		action   Action
+		identity string
+		label    string
+	}{
+		{ActionAdd, "jane", "GitHub"},
+		{ActionAdd, "jane", "Forum"},
+		{ActionAdd, "new-new@example.com", "Shop"},
+		{ActionAdd, "new-new@example.com", "Other shop"},
+		{ActionAdd, "bob", "untitled.example"},
+		{ActionSkip, "", "No user"},
+		{ActionSkip, "", ""},
+	}
+	if len(plan.Items) != len(want) {
+		t.Fatalf("items = %d, want %d", len(plan.Items), len(want))
+	}
+	for i, w := range want {
+		it := plan.Items[i]
+		if it.Action != w.action || it.Identity.ID != w.identity || it.Credential.Label != w.label {
+			t.Errorf("item %d = %s %q %q, want %s %q %q", i, it.Action, it.Identity.ID, it.Credential.Label, w.action, w.identity, w.label)
+		}
+		if it.Action == ActionAdd && (it.Credential.IdentityID != w.identity || !it.Credential.CreatedAt.Equal(now)) {
+			t.Errorf("item %d credential = %+v", i, it.Credential)
+		}
+	}
+
+	if len(plan.NewIdentities) != 1 || plan.NewIdentities[0].ID != "new-new@example.com" {
+		t.Errorf("new identities = %+v, want one for new@example.com", plan.NewIdentities)
+	}
+	if plan.Count(ActionAdd) != 5 || len(plan.Credentials()) != 5 {
+		t.Errorf("add count = %d, want 5", plan.Count(ActionAdd))
+	}
+}
+
+func TestPlanImportFallback(t *testing.T) {
+	fallback := identity.Identity{ID: "fallback"}
+	entries := []Entry{
+		{Title: "Shop", Username: "someone@else.com"},
+		{Title: "No user"},
+		{Title: "Mine", Username: "bob@zburn.id"},
+	}
+	plan := PlanImport(entries, planIdentities(), nil, Options{Fallback: &fallback, NewIdentity: newIdentity})
+
+	for i, want := range []string{"fallback", "fallback", "bob"} {
+		if got := plan.Items[i].Credential.IdentityID; got != want {
+			t.Errorf("item %d identity = %q, want %q", i, got, want)
+		}
+	}
+	if len(plan.NewIdentities) != 0 {
+		t.Errorf("fallback should not create identities, got %d", len(plan.NewIdentities))
+	}
+}
+
+func TestPlanImportDuplicates(t *testing.T) {
+	existing := []credential.Credential{
+		{ID: "c1", IdentityID: "jane", Label: "github", Username: "jane@zburn.id"},
+	}
+	entries := []Entry{
+		{Title: "GitHub", Username: "jane@zburn.id"},
+		{Title: "Forum", Username: "janed"},
+		{Title: "Forum", Username: "janed"},
+	}
+	plan := PlanImport(entries, planIdentities(), existing, Options{})
+
+	actions := []Action{ActionSkip, ActionAdd, ActionSkip}
+	for i, want := range actions {
+		if plan.Items[i].Action != want {
+			t.Errorf("item %d action = %s, want %s", i, plan.Items[i].Action, want)
+		}
+	}
+	if plan.Items[0].Reason != "already saved" {
+		t.Errorf("reason = %q", plan.Items[0].Reason)
+	}
+}
+
+func TestPlanImportTOTP(t *testing.T) {
+	entries := []Entry{
+		{Title: "URI", Username: "bob@zburn.id", TOTP: "otpauth://totp/Site:bob?secret=JBSWY3DPEHPK3PXP&digits=8&issuer=Site"},
+		{Title: "Raw", Username: "bob@zburn.id", TOTP: "jbsw y3dp ehpk 3pxp"},
+		{Title: "Steam", Username: "bob@zburn.id", TOTP: "steam://ABC!"},
+	}
+	plan := PlanImport(entries, planIdentities(), nil, Options{})
+
+	uri := plan.Items[0].Credential
+	if uri.TOTPSecret != "JBSWY3DPEHPK3PXP" || uri.TOTPDigits != 8 || uri.TOTPIssuer != "Site" {
+		t.Errorf("uri totp = %+v", uri)
+	}
+	if plan.Items[1].Credential.TOTPSecret == "" {
+		t.Error("raw secret should be imported")
+	}
+	steam := plan.Items[2]
+	if steam.Action != ActionAdd || steam.Credential.TOTPSecret != "" || steam.Warning == "" {
+		t.Errorf("invalid totp should import the login with a warning, got %+v", steam)
+	}
+}
+
+func TestPlanImportSharesNewIdentity(t *testing.T) {
+	entries := []Entry{
+		{Title: "Shop", Username: "new@example.com"},
+		{Title: "Shop", Username: "new@example.com"},
+	}
+	plan := PlanImport(entries, nil, nil, Options{NewIdentity: newIdentity})
+	if len(plan.NewIdentities) != 1 {
+		t.Errorf("new identities = %d, want 1", len(plan.NewIdentities))
+	}
+	if plan.Items[1].Action != ActionSkip {
+		t.Errorf("second entry should be skipped as a duplicate")
+	}
+}
@@ -0,0 +1,133 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// keePassTOTPKeys are the entry strings KeePass variants keep TOTP in:
+// KeePassXC's otpauth URI, the KeePass 2.47+ base32 secret and the older
+// KeeOtp/KeePassXC seed.
+var keePassTOTPKeys = []string{"otp", "TimeOtp-Secret-Base32", "TOTP Seed"}
+
+// keePassTOTPParams maps the strings KeePass 2.47+ keeps next to
+// TimeOtp-Secret-Base32 to otpauth URI parameters.
+var keePassTOTPParams = []struct{ key, param string }{
+	{"TimeOtp-Length", "digits"},
+	{"TimeOtp-Period", "period"},
+	{"TimeOtp-Algorithm", "algorithm"},
+}
+
+type keePassFile struct {
+	Root struct {
+		Groups []keePassGroup `xml:"Group"`
+	} `xml:"Root"`
+}
+
+type keePassGroup struct {
+	Name    string         `xml:"Name"`
+	Entries []keePassEntry `xml:"Entry"`
+	Groups  []keePassGroup `xml:"Group"`
+}
+
+// keePassEntry holds an entry's strings. Its History is not decoded, so
+// old revisions of an entry are not imported.
+type keePassEntry struct {
+	Strings []struct {
+		Key   string `xml:"Key"`
+		Value string `xml:"Value"`
+	} `xml:"String"`
+}
+
+func (e keePassEntry) get(key string) string {
+	for _, s := range e.Strings {
+		if s.Key == key {
+			return s.Value
+		}
+	}
+	return ""
+}
+
+// totp returns the entry's TOTP secret or URI. A KeePass 2.47+ secret with
+// its own digits, period or algorithm becomes an otpauth URI carrying them;
+// values zburn cannot use then fail the import of the TOTP with a warning
+// rather than producing wrong codes.
+func (e keePassEntry) totp() string {
+	for _, k := range keePassTOTPKeys {
+		v := e.get(k)
+		if v == "" {
+			continue
+		}
+		if k != "TimeOtp-Secret-Base32" {
+			return v
+		}
+
+		q := url.Values{}
+		for _, p := range keePassTOTPParams {
+			if pv := strings.TrimSpace(e.get(p.key)); pv != "" {
+				q.Set(p.param, pv)
+			}
+		}
+		if len(q) == 0 {
+			return v
+		}
+		if alg := q.Get("algorithm"); alg != "" {
+			// KeePass names them HMAC-SHA-1, HMAC-SHA-256 and HMAC-SHA-512
+			q.Set("algorithm", strings.ReplaceAll(strings.TrimPrefix(strings.ToUpper(alg), "HMAC-"), "-", ""))
+		}
+		q.Set("secret", strings.ReplaceAll(v, " ", ""))
+		u := url.URL{Scheme: "otpauth", Host: "totp", Path: "/", RawQuery: q.Encode()}
+		return u.String()
+	}
+	return ""
+}
+
+func parseKeePass(data []byte) ([]Entry, int, error) {
+	var f keePassFile
+	if err := xml.NewDecoder(bytes.NewReader(data)).Decode(&f); err != nil {
+		return nil, 0, fmt.Errorf("parse keepass xml: %w", err)
+	}
+
+	var entries []Entry
+	skipped := 0
+	var walk func(g keePassGroup)
+	walk = func(g keePassGroup) {
+		if strings.EqualFold(g.Name, "Recycle Bin") {
+			skipped += countKeePassEntries(g)
+			return
+		}
+		for _, ke := range g.Entries {
+			e := Entry{
+				Title:    ke.get("Title"),
+				URL:      ke.get("URL"),
+				Username: ke.get("UserName"),
+				Password: ke.get("Password"),
+				Notes:    ke.get("Notes"),
+			}
+			e.TOTP = ke.totp()
+			if e.Username == "" && e.Password == "" {
+				skipped++
+				continue
+			}
+			entries = append(entries, e)
+		}
+		for _, sub := range g.Groups {
+			walk(sub)
+		}
+	}
+	for _, g := range f.Root.Groups {
+		walk(g)
+	}
+	return entries, skipped, nil
+}
+
+func countKeePassEntries(g keePassGroup) int {
+	n := len(g.Entries)
+	for _, sub := range g.Groups {
+		n += countKeePassEntries(sub)
+	}
+	return n
+}
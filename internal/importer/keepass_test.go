@@ -0,0 +1,143 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/zarlcorp/zburn/internal/otp"
+)
+
+const keePassXML = `<?xml version="1.0" encoding="utf-8" standalone="yes"?>
+<KeePassFile>
+  <Meta><Generator>KeePassXC</Generator></Meta>
+  <Root>
+    <Group>
+      <Name>Root</Name>
+      <Entry>
+        <String><Key>Title</Key><Value>GitHub</Value></String>
+        <String><Key>UserName</Key><Value>jane@zburn.id</Value></String>
+        <String><Key>Password</Key><Value ProtectInMemory="True">s3cret</Value></String>
+        <String><Key>URL</Key><Value>https://github.com</Value></String>
+        <String><Key>Notes</Key><Value>work</Value></String>
+        <String><Key>otp</Key><Value>otpauth://totp/GitHub?secret=JBSWY3DPEHPK3PXP</Value></String>
+        <History>
+          <Entry>
+            <String><Key>Title</Key><Value>GitHub (old)</Value></String>
+            <String><Key>Password</Key><Value>old</Value></String>
+          </Entry>
+        </History>
+      </Entry>
+      <Group>
+        <Name>Forums</Name>
+        <Entry>
+          <String><Key>Title</Key><Value>Forum</Value></String>
+          <String><Key>UserName</Key><Value>janed</Value></String>
+          <String><Key>Password</Key><Value>pw</Value></String>
+          <String><Key>TimeOtp-Secret-Base32</Key><Value>JBSWY3DPEHPK3PXP</Value></String>
+        </Entry>
+        <Entry>
+          <String><Key>Title</Key><Value>just a note</Value></String>
+        </Entry>
+      </Group>
+      <Group>
+        <Name>Recycle Bin</Name>
+        <Entry>
+          <String><Key>Title</Key><Value>Deleted</Value></String>
+          <String><Key>Password</Key><Value>x</Value></String>
+        </Entry>
+      </Group>
+    </Group>
+  </Root>
+</KeePassFile>`
+
+func TestParseKeePass(t *testing.T) {
+	entries, skipped, err := Parse(KeePass, []byte(keePassXML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skipped != 2 {
+		t.Errorf("skipped = %d, want 2", skipped)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("entries = %+v, want 2", entries)
+	}
+
+	want := Entry{
+		Title:    "GitHub",
+		URL:      "https://github.com",
+		Username: "jane@zburn.id",
+		Password: "s3cret",
+		TOTP:     "otpauth://totp/GitHub?secret=JBSWY3DPEHPK3PXP",
+		Notes:    "work",
+	}
+	if entries[0] != want {
+		t.Errorf("entry = %+v, want %+v", entries[0], want)
+	}
+	if entries[1].Title != "Forum" || entries[1].TOTP != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("nested entry = %+v", entries[1])
+	}
+}
+
+func TestKeePassTOTPParams(t *testing.T) {
+	entry := func(kv ...string) keePassEntry {
+		var e keePassEntry
+		for i := 0; i < len(kv); i += 2 {
+			e.Strings = append(e.Strings, struct {
+				Key   string `xml:"Key"`
+				Value string `xml:"Value"`
+			}{kv[i], kv[i+1]})
+		}
+		return e
+	}
+
+	tests := []struct {
+		name    string
+		entry   keePassEntry
+		want    otp.Params
+		wantErr bool
+	}{
+		{
+			name:  "all params",
+			entry: entry("TimeOtp-Secret-Base32", "JBSWY3DPEHPK3PXP", "TimeOtp-Length", "8", "TimeOtp-Period", "60", "TimeOtp-Algorithm", "HMAC-SHA-256"),
+			want:  otp.Params{Secret: "JBSWY3DPEHPK3PXP", Algorithm: "SHA256", Digits: 8, Period: 60},
+		},
+		{
+			name:  "period only",
+			entry: entry("TimeOtp-Secret-Base32", "JBSWY3DPEHPK3PXP", "TimeOtp-Period", "60"),
+			want:  otp.Params{Secret: "JBSWY3DPEHPK3PXP", Period: 60},
+		},
+		{
+			name:    "unsupported length",
+			entry:   entry("TimeOtp-Secret-Base32", "JBSWY3DPEHPK3PXP", "TimeOtp-Length", "10"),
+			wantErr: true,
+		},
+		{
+			name:    "unsupported algorithm",
+			entry:   entry("TimeOtp-Secret-Base32", "JBSWY3DPEHPK3PXP", "TimeOtp-Algorithm", "HMAC-MD5"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := otp.ParseURI(tt.entry.totp())
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("totp %q should not parse", tt.entry.totp())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("params = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseKeePassInvalid(t *testing.T) {
+	if _, _, err := Parse(KeePass, []byte("<KeePassFile><Root>")); err == nil {
+		t.Error("expected error for truncated xml")
+	}
+}
@@ -0,0 +1,187 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// onePasswordColumns maps entry fields to the CSV headers 1Password has
+// used for them across versions, lowercased.
+var onePasswordColumns = map[string][]string{
+	"title":    {"title", "name"},
+	"url":      {"url", "website", "login url"},
+	"username": {"username", "login username"},
+	"password": {"password", "login password"},
+	"totp":     {"otpauth", "one-time password", "totp"},
+	"notes":    {"notes", "notesplain"},
+}
+
+func parseOnePasswordCSV(data []byte) ([]Entry, int, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, 0, fmt.Errorf("parse 1password csv: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, 0, nil
+	}
+
+	col := make(map[string]int)
+	for i, h := range rows[0] {
+		h = strings.ToLower(strings.TrimSpace(h))
+		for field, names := range onePasswordColumns {
+			if _, seen := col[field]; !seen && slices.Contains(names, h) {
+				col[field] = i
+			}
+		}
+	}
+	if _, ok := col["password"]; !ok {
+		return nil, 0, fmt.Errorf("parse 1password csv: no password column in header %q", strings.Join(rows[0], ","))
+	}
+
+	get := func(row []string, field string) string {
+		i, ok := col[field]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return row[i]
+	}
+
+	var entries []Entry
+	skipped := 0
+	for _, row := range rows[1:] {
+		e := Entry{
+			Title:    get(row, "title"),
+			URL:      get(row, "url"),
+			Username: get(row, "username"),
+			Password: get(row, "password"),
+			TOTP:     get(row, "totp"),
+			Notes:    get(row, "notes"),
+		}
+		if e.Username == "" && e.Password == "" {
+			skipped++
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, skipped, nil
+}
+
+// onePUXLogin is the 1Password category UUID for logins.
+const onePUXLogin = "001"
+
+// onePUXData is the export.data document inside a .1pux archive.
+type onePUXData struct {
+	Accounts []struct {
+		Vaults []struct {
+			Items []onePUXItem `json:"items"`
+		} `json:"vaults"`
+	} `json:"accounts"`
+}
+
+type onePUXItem struct {
+	CategoryUUID string `json:"categoryUuid"`
+	State        string `json:"state"`
+	Overview     struct {
+		Title string `json:"title"`
+		URL   string `json:"url"`
+		URLs  []struct {
+			URL string `json:"url"`
+		} `json:"urls"`
+	} `json:"overview"`
+	Details struct {
+		LoginFields []struct {
+			Designation string `json:"designation"`
+			Value       string `json:"value"`
+		} `json:"loginFields"`
+		NotesPlain string `json:"notesPlain"`
+		Sections   []struct {
+			Fields []struct {
+				Value map[string]json.RawMessage `json:"value"`
+			} `json:"fields"`
+		} `json:"sections"`
+	} `json:"details"`
+}
+
+func parseOnePUX(data []byte) ([]Entry, int, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, 0, fmt.Errorf("parse 1pux: %w", err)
+	}
+
+	var doc onePUXData
+	found := false
+	for _, f := range zr.File {
+		if f.Name != "export.data" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, 0, fmt.Errorf("parse 1pux: %w", err)
+		}
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, 0, fmt.Errorf("parse 1pux: %w", err)
+		}
+		if err := json.Unmarshal(b, &doc); err != nil {
+			return nil, 0, fmt.Errorf("parse 1pux: %w", err)
+		}
+		found = true
+	}
+	if !found {
+		return nil, 0, fmt.Errorf("parse 1pux: export.data not found in archive")
+	}
+
+	var entries []Entry
+	skipped := 0
+	for _, acct := range doc.Accounts {
+		for _, vault := range acct.Vaults {
+			for _, it := range vault.Items {
+				if it.CategoryUUID != onePUXLogin || it.State == "archived" {
+					skipped++
+					continue
+				}
+				entries = append(entries, it.entry())
+			}
+		}
+	}
+	return entries, skipped, nil
+}
+
+func (it onePUXItem) entry() Entry {
+	e := Entry{
+		Title: it.Overview.Title,
+		URL:   it.Overview.URL,
+		Notes: it.Details.NotesPlain,
+	}
+	if e.URL == "" && len(it.Overview.URLs) > 0 {
+		e.URL = it.Overview.URLs[0].URL
+	}
+	for _, f := range it.Details.LoginFields {
+		switch f.Designation {
+		case "username":
+			e.Username = f.Value
+		case "password":
+			e.Password = f.Value
+		}
+	}
+	// the one-time password is a section field whose value is {"totp": "..."}
+	for _, s := range it.Details.Sections {
+		for _, f := range s.Fields {
+			if raw, ok := f.Value["totp"]; ok && e.TOTP == "" {
+				_ = json.Unmarshal(raw, &e.TOTP)
+			}
+		}
+	}
+	return e
+}
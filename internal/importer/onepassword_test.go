@@ -0,0 +1,126 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+)
+
+func TestParseOnePasswordCSV(t *testing.T) {
+	data := "\xef\xbb\xbf" + `Title,Url,Username,Password,OTPAuth,Favorite,Archived,Tags,Notes
+GitHub,https://github.com,jane@zburn.id,s3cret,otpauth://totp/GitHub?secret=JBSWY3DPEHPK3PXP,false,false,,"multi
+line"
+Wifi,,,,,false,false,,
+`
+	entries, skipped, err := Parse(OnePassword, []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skipped != 1 {
+		t.Errorf("skipped = %d, want 1", skipped)
+	}
+	want := Entry{
+		Title:    "GitHub",
+		URL:      "https://github.com",
+		Username: "jane@zburn.id",
+		Password: "s3cret",
+		TOTP:     "otpauth://totp/GitHub?secret=JBSWY3DPEHPK3PXP",
+		Notes:    "multi\nline",
+	}
+	if len(entries) != 1 || entries[0] != want {
+		t.Errorf("entries = %+v, want [%+v]", entries, want)
+	}
+}
+
+func TestParseOnePasswordCSVOldHeaders(t *testing.T) {
+	data := "title,website,username,password,notes\nForum,forum.example,janed,pw,\n"
+	entries, _, err := Parse(OnePassword, []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].URL != "forum.example" || entries[0].Title != "Forum" {
+		t.Errorf("entries = %+v", entries)
+	}
+}
+
+func TestParseOnePasswordCSVNoPasswordColumn(t *testing.T) {
+	if _, _, err := Parse(OnePassword, []byte("a,b\n1,2\n")); err == nil {
+		t.Error("expected error for a csv without a password column")
+	}
+}
+
+const onePUXExportData = `{
+  "accounts": [{
+    "vaults": [{
+      "items": [
+        {
+          "categoryUuid": "001",
+          "state": "active",
+          "overview": {"title": "GitHub", "url": "", "urls": [{"url": "https://github.com"}]},
+          "details": {
+            "loginFields": [
+              {"designation": "username", "value": "jane@zburn.id"},
+              {"designation": "password", "value": "s3cret"}
+            ],
+            "notesPlain": "work",
+            "sections": [{"fields": [{"title": "one-time password", "value": {"totp": "JBSWY3DPEHPK3PXP"}}]}]
+          }
+        },
+        {"categoryUuid": "001", "state": "archived", "overview": {"title": "Old"}, "details": {}},
+        {"categoryUuid": "003", "state": "active", "overview": {"title": "Note"}, "details": {}}
+      ]
+    }]
+  }]
+}`
+
+func onePUX(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, body := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseOnePUX(t *testing.T) {
+	data := onePUX(t, map[string]string{
+		"export.attributes": `{"version": 3}`,
+		"export.data":       onePUXExportData,
+	})
+
+	entries, skipped, err := Parse(OnePasswordX, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skipped != 2 {
+		t.Errorf("skipped = %d, want 2", skipped)
+	}
+	want := Entry{
+		Title:    "GitHub",
+		URL:      "https://github.com",
+		Username: "jane@zburn.id",
+		Password: "s3cret",
+		TOTP:     "JBSWY3DPEHPK3PXP",
+		Notes:    "work",
+	}
+	if len(entries) != 1 || entries[0] != want {
+		t.Errorf("entries = %+v, want [%+v]", entries, want)
+	}
+}
+
+func TestParseOnePUXMissingData(t *testing.T) {
+	data := onePUX(t, map[string]string{"export.attributes": `{}`})
+	if _, _, err := Parse(OnePasswordX, data); err == nil {
+		t.Error("expected error without export.data")
+	}
+}
@@ -5,6 +5,7 @@ package namecheap
 import (
 	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -15,6 +16,10 @@ import (
 
 const defaultBaseURL = "https://api.namecheap.com/xml.response"
 
+// ErrMailboxNotFound is returned by RemoveForwarding when the domain has no
+// rule for the mailbox.
+var ErrMailboxNotFound = errors.New("not found")
+
 // ForwardingRule maps a mailbox to a forwarding address.
 type ForwardingRule struct {
 	Mailbox   string // e.g. "john.doe" (the part before @)
@@ -164,7 +169,7 @@ func (c *Client) RemoveForwarding(ctx context.Context, domain, mailbox string) e
 	}
 
 	if len(filtered) == len(rules) {
-		return fmt.Errorf("remove forwarding: mailbox %q not found", mailbox)
+		return fmt.Errorf("remove forwarding: mailbox %q %w", mailbox, ErrMailboxNotFound)
 	}
 
 	if err := c.SetForwarding(ctx, domain, filtered); err != nil {
@@ -2,6 +2,7 @@ package namecheap
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -283,6 +284,9 @@ func TestRemoveForwardingNotFound(t *testing.T) {
 	if !strings.Contains(err.Error(), `mailbox "nonexistent" not found`) {
 		t.Errorf("error message: got %q, want contains mailbox not found", err.Error())
 	}
+	if !errors.Is(err, ErrMailboxNotFound) {
+		t.Errorf("error should wrap ErrMailboxNotFound: %v", err)
+	}
 }
 
 func TestAPIError(t *testing.T) {
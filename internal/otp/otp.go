@@ -0,0 +1,241 @@
+// Package otp generates RFC 6238 TOTP codes and parses otpauth:// URIs.
+//
+// Unlike zcrypto.TOTPCode, which is fixed to SHA1, 6 digits and a 30-second
+// period, codes here honour the parameters a site publishes in its URI.
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults used when a parameter is absent.
+const (
+	DefaultAlgorithm = "SHA1"
+	DefaultDigits    = 6
+	DefaultPeriod    = 30
+)
+
+// Params describes a TOTP configuration. Zero values mean the default.
+type Params struct {
+	Secret    string // base32
+	Issuer    string
+	Account   string
+	Algorithm string // SHA1, SHA256 or SHA512
+	Digits    int
+	Period    int // seconds
+}
+
+// IsURI reports whether s looks like an otpauth URI rather than a raw secret.
+func IsURI(s string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(s)), "otpauth://")
+}
+
+// ParseURI parses an otpauth://totp/ URI.
+func ParseURI(raw string) (Params, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return Params{}, fmt.Errorf("parse otpauth uri: %w", err)
+	}
+	if !strings.EqualFold(u.Scheme, "otpauth") {
+		return Params{}, fmt.Errorf("parse otpauth uri: scheme %q, want otpauth", u.Scheme)
+	}
+	if !strings.EqualFold(u.Host, "totp") {
+		return Params{}, fmt.Errorf("parse otpauth uri: type %q not supported, want totp", u.Host)
+	}
+
+	q := u.Query()
+	p := Params{
+		Secret: strings.ToUpper(strings.ReplaceAll(q.Get("secret"), " ", "")),
+		Issuer: q.Get("issuer"),
+	}
+	if p.Secret == "" {
+		return Params{}, fmt.Errorf("parse otpauth uri: missing secret")
+	}
+
+	// label is "issuer:account" or just "account"
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, account, ok := strings.Cut(label, ":"); ok {
+		p.Account = strings.TrimSpace(account)
+		if p.Issuer == "" {
+			p.Issuer = issuer
+		}
+	} else {
+		p.Account = label
+	}
+
+	if v := q.Get("algorithm"); v != "" {
+		p.Algorithm = strings.ToUpper(v)
+	}
+	if v := q.Get("digits"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return Params{}, fmt.Errorf("parse otpauth uri: digits %q", v)
+		}
+		p.Digits = n
+	}
+	if v := q.Get("period"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return Params{}, fmt.Errorf("parse otpauth uri: period %q", v)
+		}
+		p.Period = n
+	}
+
+	if err := p.Validate(); err != nil {
+		return Params{}, fmt.Errorf("parse otpauth uri: %w", err)
+	}
+
+	return p, nil
+}
+
+// URI formats the params as an otpauth://totp/ URI, omitting defaults.
+func (p Params) URI() string {
+	label := p.Account
+	if p.Issuer != "" {
+		label = p.Issuer + ":" + p.Account
+	}
+
+	q := url.Values{}
+	q.Set("secret", p.Secret)
+	if p.Issuer != "" {
+		q.Set("issuer", p.Issuer)
+	}
+	if alg := p.algorithm(); alg != DefaultAlgorithm {
+		q.Set("algorithm", alg)
+	}
+	if d := p.digits(); d != DefaultDigits {
+		q.Set("digits", strconv.Itoa(d))
+	}
+	if per := p.period(); per != DefaultPeriod {
+		q.Set("period", strconv.Itoa(per))
+	}
+
+	u := url.URL{Scheme: "otpauth", Host: "totp", Path: "/" + label, RawQuery: q.Encode()}
+	return u.String()
+}
+
+// Validate checks the secret decodes and the parameters are supported.
+func (p Params) Validate() error {
+	if _, err := DecodeSecret(p.Secret); err != nil {
+		return fmt.Errorf("invalid secret (must be base32)")
+	}
+	if newHash(p.algorithm()) == nil {
+		return fmt.Errorf("unsupported algorithm %q", p.Algorithm)
+	}
+	if d := p.digits(); d < 6 || d > 8 {
+		return fmt.Errorf("unsupported digits %d (must be 6-8)", d)
+	}
+	if p.period() <= 0 {
+		return fmt.Errorf("invalid period %d", p.Period)
+	}
+	return nil
+}
+
+// Code returns the TOTP code at time t.
+func (p Params) Code(t time.Time) (string, error) {
+	key, err := DecodeSecret(p.Secret)
+	if err != nil {
+		return "", fmt.Errorf("decode secret: %w", err)
+	}
+
+	h := newHash(p.algorithm())
+	if h == nil {
+		return "", fmt.Errorf("unsupported algorithm %q", p.Algorithm)
+	}
+
+	digits := p.digits()
+	if digits < 6 || digits > 8 {
+		return "", fmt.Errorf("unsupported digits %d", digits)
+	}
+
+	counter := uint64(t.Unix()) / uint64(p.period())
+
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], counter)
+
+	mac := hmac.New(h, key)
+	mac.Write(buf[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for range digits {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, code%mod), nil
+}
+
+// Remaining returns how long the code at time t stays valid.
+func (p Params) Remaining(t time.Time) time.Duration {
+	period := int64(p.period())
+	return time.Duration(period-t.Unix()%period) * time.Second
+}
+
+// Normalized returns p with defaults filled in for unset parameters.
+func (p Params) Normalized() Params {
+	p.Algorithm = p.algorithm()
+	p.Digits = p.digits()
+	p.Period = p.period()
+	return p
+}
+
+func (p Params) period() int {
+	if p.Period == 0 {
+		return DefaultPeriod
+	}
+	return p.Period
+}
+
+func (p Params) digits() int {
+	if p.Digits == 0 {
+		return DefaultDigits
+	}
+	return p.Digits
+}
+
+func (p Params) algorithm() string {
+	if p.Algorithm == "" {
+		return DefaultAlgorithm
+	}
+	return strings.ToUpper(p.Algorithm)
+}
+
+func newHash(alg string) func() hash.Hash {
+	switch alg {
+	case "SHA1":
+		return sha1.New
+	case "SHA256":
+		return sha256.New
+	case "SHA512":
+		return sha512.New
+	}
+	return nil
+}
+
+// DecodeSecret decodes a base32 secret, handling case, spaces and missing
+// padding, as authenticator apps display secrets that way.
+func DecodeSecret(secret string) ([]byte, error) {
+	s := strings.ToUpper(strings.TrimSpace(secret))
+	s = strings.ReplaceAll(s, " ", "")
+	if s == "" {
+		return nil, fmt.Errorf("empty secret")
+	}
+	if pad := len(s) % 8; pad != 0 {
+		s += strings.Repeat("=", 8-pad)
+	}
+	return base32.StdEncoding.DecodeString(s)
+}
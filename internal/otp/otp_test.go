@@ -0,0 +1,178 @@
+package otp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+func b32(s string) string {
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte(s))
+}
+
+// RFC 6238 appendix B test vectors.
+func TestCodeRFC6238(t *testing.T) {
+	seed20 := "12345678901234567890"
+	seed32 := seed20 + "123456789012"
+	seed64 := strings.Repeat(seed20, 3) + "1234"
+
+	tests := []struct {
+		unix int64
+		alg  string
+		seed string
+		want string
+	}{
+		{59, "SHA1", seed20, "94287082"},
+		{59, "SHA256", seed32, "46119246"},
+		{59, "SHA512", seed64, "90693936"},
+		{1111111109, "SHA1", seed20, "07081804"},
+		{1111111109, "SHA256", seed32, "68084774"},
+		{1111111109, "SHA512", seed64, "25091201"},
+		{20000000000, "SHA1", seed20, "65353130"},
+	}
+
+	for _, tt := range tests {
+		p := Params{Secret: b32(tt.seed), Algorithm: tt.alg, Digits: 8}
+		got, err := p.Code(time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("%s at %d: %v", tt.alg, tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s at %d: got %s, want %s", tt.alg, tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestCodeDefaults(t *testing.T) {
+	p := Params{Secret: b32("12345678901234567890")}
+	got, err := p.Code(time.Unix(59, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// last six digits of the 8-digit SHA1 vector
+	if got != "287082" {
+		t.Errorf("got %s, want 287082", got)
+	}
+}
+
+func TestCodeInvalidSecret(t *testing.T) {
+	if _, err := (Params{Secret: "!!!"}).Code(time.Now()); err == nil {
+		t.Fatal("expected error for invalid secret")
+	}
+}
+
+func TestRemaining(t *testing.T) {
+	tests := []struct {
+		period int
+		unix   int64
+		want   time.Duration
+	}{
+		{0, 0, 30 * time.Second},
+		{0, 59, 1 * time.Second},
+		{60, 75, 45 * time.Second},
+	}
+
+	for _, tt := range tests {
+		got := Params{Period: tt.period}.Remaining(time.Unix(tt.unix, 0))
+		if got != tt.want {
+			t.Errorf("period %d at %d: got %v, want %v", tt.period, tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestParseURI(t *testing.T) {
+	raw := "otpauth://totp/Acme:jane@zburn.id?secret=JBSWY3DPEHPK3PXP&issuer=Acme&algorithm=SHA256&digits=8&period=60"
+	p, err := ParseURI(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Params{
+		Secret:    "JBSWY3DPEHPK3PXP",
+		Issuer:    "Acme",
+		Account:   "jane@zburn.id",
+		Algorithm: "SHA256",
+		Digits:    8,
+		Period:    60,
+	}
+	if p != want {
+		t.Errorf("got %+v, want %+v", p, want)
+	}
+}
+
+func TestParseURIIssuerFromLabel(t *testing.T) {
+	p, err := ParseURI("otpauth://totp/Example%20Co:jane?secret=jbswy3dpehpk3pxp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Issuer != "Example Co" || p.Account != "jane" {
+		t.Errorf("issuer/account = %q/%q, want Example Co/jane", p.Issuer, p.Account)
+	}
+	if p.Secret != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("secret = %q, want upper-cased", p.Secret)
+	}
+	if n := p.Normalized(); n.Algorithm != "SHA1" || n.Digits != 6 || n.Period != 30 {
+		t.Errorf("normalized = %+v, want defaults", n)
+	}
+}
+
+func TestParseURIErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{"wrong scheme", "https://totp/x?secret=JBSWY3DPEHPK3PXP"},
+		{"hotp", "otpauth://hotp/x?secret=JBSWY3DPEHPK3PXP&counter=1"},
+		{"missing secret", "otpauth://totp/x"},
+		{"bad secret", "otpauth://totp/x?secret=!!!"},
+		{"bad algorithm", "otpauth://totp/x?secret=JBSWY3DPEHPK3PXP&algorithm=MD5"},
+		{"bad digits", "otpauth://totp/x?secret=JBSWY3DPEHPK3PXP&digits=12"},
+		{"non-numeric period", "otpauth://totp/x?secret=JBSWY3DPEHPK3PXP&period=abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseURI(tt.raw); err == nil {
+				t.Errorf("ParseURI(%q) should fail", tt.raw)
+			}
+		})
+	}
+}
+
+func TestURIRoundTrip(t *testing.T) {
+	p := Params{
+		Secret:    "JBSWY3DPEHPK3PXP",
+		Issuer:    "Acme",
+		Account:   "jane@zburn.id",
+		Algorithm: "SHA512",
+		Digits:    8,
+		Period:    60,
+	}
+
+	got, err := ParseURI(p.URI())
+	if err != nil {
+		t.Fatalf("parse %q: %v", p.URI(), err)
+	}
+	if got != p {
+		t.Errorf("round trip = %+v, want %+v", got, p)
+	}
+}
+
+func TestURIOmitsDefaults(t *testing.T) {
+	uri := Params{Secret: "JBSWY3DPEHPK3PXP", Account: "jane"}.URI()
+	for _, k := range []string{"algorithm", "digits", "period", "issuer"} {
+		if strings.Contains(uri, k+"=") {
+			t.Errorf("uri %q should omit %s", uri, k)
+		}
+	}
+}
+
+func TestIsURI(t *testing.T) {
+	if !IsURI("  OTPAUTH://totp/x?secret=A") {
+		t.Error("IsURI should accept upper-case scheme")
+	}
+	if IsURI("JBSWY3DPEHPK3PXP") {
+		t.Error("IsURI should reject a raw secret")
+	}
+}
@@ -0,0 +1,148 @@
+// Package rekey re-encrypts a zburn vault under a new master password.
+//
+// zstore derives every collection key from the master password, so a new
+// password means rewriting every entry. The entries are written to a
+// staging directory next to the vault and read back before the two
+// directories are swapped; a failure at any point before the swap leaves
+// the vault untouched.
+package rekey
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/zarlcorp/core/pkg/zfilesystem"
+	"github.com/zarlcorp/core/pkg/zstore"
+	"github.com/zarlcorp/zburn/internal/backup"
+)
+
+// ErrSamePassword is returned when the new password matches the current one.
+var ErrSamePassword = errors.New("new password is the same as the current one")
+
+// ErrEmptyPassword is returned when the new password is empty.
+var ErrEmptyPassword = errors.New("new password cannot be empty")
+
+// Result describes a completed re-key.
+type Result struct {
+	// Snapshot holds the entries that were re-encrypted.
+	Snapshot backup.Snapshot
+	// Leftover is set when the vault encrypted under the old password
+	// could not be removed after the swap; it should be deleted by hand.
+	Leftover string
+}
+
+// Rekey re-encrypts every collection of the vault in dir, unlocked with
+// oldPass, under newPass. On error the vault is unchanged.
+func Rekey(dir, oldPass, newPass string) (Result, error) {
+	if newPass == "" {
+		return Result{}, ErrEmptyPassword
+	}
+	if newPass == oldPass {
+		return Result{}, ErrSamePassword
+	}
+	dir = filepath.Clean(dir)
+	if _, err := os.Stat(filepath.Join(dir, "salt")); err != nil {
+		return Result{}, fmt.Errorf("no vault in %s", dir)
+	}
+
+	snap, err := read(dir, oldPass)
+	if err != nil {
+		return Result{}, err
+	}
+
+	staging, err := os.MkdirTemp(filepath.Dir(dir), "."+filepath.Base(dir)+"-rekey-*")
+	if err != nil {
+		return Result{}, fmt.Errorf("create staging dir: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	if err := write(staging, newPass, snap); err != nil {
+		return Result{}, err
+	}
+	if err := verify(staging, newPass, snap); err != nil {
+		return Result{}, err
+	}
+
+	leftover, err := swap(dir, staging)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{Snapshot: snap, Leftover: leftover}, nil
+}
+
+// read takes every entry of the vault in dir, unlocked with pass.
+func read(dir, pass string) (backup.Snapshot, error) {
+	fsys := zfilesystem.NewOSFileSystem(dir)
+	s, err := zstore.Open(fsys, []byte(pass))
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+
+	snap, err := backup.Take(s, fsys)
+	if err != nil {
+		return nil, fmt.Errorf("read vault: %w", err)
+	}
+	return snap, nil
+}
+
+// write creates a new store in dir and fills it with snap.
+func write(dir, pass string, snap backup.Snapshot) error {
+	fsys := zfilesystem.NewOSFileSystem(dir)
+	s, err := zstore.Open(fsys, []byte(pass))
+	if err != nil {
+		return fmt.Errorf("create store: %w", err)
+	}
+	defer s.Close()
+
+	if _, err := backup.Restore(s, fsys, snap, backup.Replace); err != nil {
+		return fmt.Errorf("re-encrypt: %w", err)
+	}
+	return nil
+}
+
+// verify reopens the store in dir with pass and checks it holds exactly
+// the entries of want.
+func verify(dir, pass string, want backup.Snapshot) error {
+	got, err := read(dir, pass)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+
+	a, err := json.Marshal(want)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+	b, err := json.Marshal(got)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+	if !bytes.Equal(a, b) {
+		return errors.New("verify: re-encrypted vault does not match")
+	}
+	return nil
+}
+
+// swap moves staging into place of dir. If the second rename fails the
+// first is undone. The old vault is only removed once the new one is in
+// place; its path is returned if that removal fails.
+func swap(dir, staging string) (string, error) {
+	prev := staging + "-old"
+	if err := os.Rename(dir, prev); err != nil {
+		return "", fmt.Errorf("move old vault: %w", err)
+	}
+	if err := os.Rename(staging, dir); err != nil {
+		if rerr := os.Rename(prev, dir); rerr != nil {
+			return "", fmt.Errorf("move new vault: %w (old vault left at %s: %v)", err, prev, rerr)
+		}
+		return "", fmt.Errorf("move new vault: %w", err)
+	}
+	if err := os.RemoveAll(prev); err != nil {
+		return prev, nil
+	}
+	return "", nil
+}
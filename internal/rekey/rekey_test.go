@@ -0,0 +1,144 @@
+package rekey
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zarlcorp/core/pkg/zfilesystem"
+	"github.com/zarlcorp/core/pkg/zstore"
+	"github.com/zarlcorp/zburn/internal/credential"
+	"github.com/zarlcorp/zburn/internal/identity"
+)
+
+// newVault creates a vault under a fresh parent directory with one
+// identity and one credential.
+func newVault(t *testing.T, pass string) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "zburn")
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := zstore.Open(zfilesystem.NewOSFileSystem(dir), []byte(pass))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	ids, err := zstore.NewCollection[identity.Identity](s, "identities")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ids.Put("abc", identity.Identity{ID: "abc", Email: "jane@zburn.id"}); err != nil {
+		t.Fatal(err)
+	}
+	creds, err := zstore.NewCollection[credential.Credential](s, "credentials")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := creds.Put("c1", credential.Credential{ID: "c1", IdentityID: "abc", Password: "s3cret"}); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestRekey(t *testing.T) {
+	dir := newVault(t, "old")
+
+	res, err := Rekey(dir, "old", "new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Snapshot.Count("identities") != 1 || res.Snapshot.Count("credentials") != 1 || res.Leftover != "" {
+		t.Errorf("result = %+v", res)
+	}
+
+	fsys := zfilesystem.NewOSFileSystem(dir)
+	if _, err := zstore.Open(fsys, []byte("old")); !errors.Is(err, zstore.ErrWrongPassword) {
+		t.Errorf("open with old password: err = %v, want ErrWrongPassword", err)
+	}
+	s, err := zstore.Open(fsys, []byte("new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	creds, err := zstore.NewCollection[credential.Credential](s, "credentials")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := creds.Get("c1")
+	if err != nil || c.Password != "s3cret" {
+		t.Errorf("credential = %+v, %v", c, err)
+	}
+
+	// only the vault itself is left next to it
+	entries, err := os.ReadDir(filepath.Dir(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("parent dir holds %v, want only the vault", names)
+	}
+}
+
+func TestRekeyErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		old     string
+		new     string
+		wantErr error
+	}{
+		{"wrong password", "nope", "new", zstore.ErrWrongPassword},
+		{"same password", "old", "old", ErrSamePassword},
+		{"empty password", "old", "", ErrEmptyPassword},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := newVault(t, "old")
+			if _, err := Rekey(dir, tt.old, tt.new); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			s, err := zstore.Open(zfilesystem.NewOSFileSystem(dir), []byte("old"))
+			if err != nil {
+				t.Fatalf("vault no longer opens with the old password: %v", err)
+			}
+			s.Close()
+		})
+	}
+}
+
+func TestRekeyNoVault(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Rekey(dir, "old", "new"); err == nil {
+		t.Fatal("expected error for a directory without a vault")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "salt")); err == nil {
+		t.Error("rekey created a vault")
+	}
+}
+
+func TestSwapRollsBack(t *testing.T) {
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "zburn")
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "salt"), []byte("old"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// a missing staging dir makes the second rename fail
+	if _, err := swap(dir, filepath.Join(parent, "missing")); err == nil {
+		t.Fatal("expected error")
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "salt"))
+	if err != nil || string(b) != "old" {
+		t.Errorf("vault not restored: %q, %v", b, err)
+	}
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/zarlcorp/core/pkg/zcrypto"
 	"github.com/zarlcorp/core/pkg/zstyle"
 	"github.com/zarlcorp/zburn/internal/credential"
 )
@@ -36,7 +35,7 @@ func (m *credentialDetailModel) refreshTOTP() {
 		m.totpErr = ""
 		return
 	}
-	code, err := zcrypto.TOTPCode(m.credential.TOTPSecret)
+	code, err := m.credential.TOTP().Code(time.Now())
 	if err != nil {
 		m.totpErr = err.Error()
 		m.totpCode = ""
@@ -110,7 +109,7 @@ func (m credentialDetailModel) handleKey(msg tea.KeyMsg) (credentialDetailModel,
 		if m.credential.TOTPSecret == "" {
 			return m, nil
 		}
-		code, err := zcrypto.TOTPCode(m.credential.TOTPSecret)
+		code, err := m.credential.TOTP().Code(time.Now())
 		if err != nil {
 			m.flash = "totp: " + err.Error()
 			return m, clearFlashAfter()
@@ -147,7 +146,7 @@ func (m credentialDetailModel) handleConfirm(msg tea.KeyMsg) (credentialDetailMo
 }
 
 func (m credentialDetailModel) totpCountdown() int {
-	return 30 - int(time.Now().Unix()%30)
+	return int(m.credential.TOTP().Remaining(time.Now()) / time.Second)
 }
 
 func (m credentialDetailModel) View() string {
@@ -171,6 +170,9 @@ func (m credentialDetailModel) View() string {
 			countdown := m.totpCountdown()
 			s += m.fieldLine("totp", fmt.Sprintf("%s (%ds)", m.totpCode, countdown))
 		}
+		if m.credential.TOTPIssuer != "" {
+			s += m.fieldLine("issuer", m.credential.TOTPIssuer)
+		}
 	}
 
 	if m.credential.Notes != "" {
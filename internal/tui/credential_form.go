@@ -1,9 +1,6 @@
 package tui
 
 import (
-	"crypto/rand"
-	"encoding/base32"
-	"encoding/hex"
 	"fmt"
 	"strings"
 	"time"
@@ -12,7 +9,6 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/zarlcorp/core/pkg/zcrypto"
 	"github.com/zarlcorp/core/pkg/zstyle"
 	"github.com/zarlcorp/zburn/internal/credential"
 	"github.com/zarlcorp/zburn/internal/identity"
@@ -33,7 +29,7 @@ var fieldLabels = [fieldCount]string{
 	"url",
 	"username",
 	"password",
-	"totp secret",
+	"totp secret/uri",
 	"notes",
 }
 
@@ -61,6 +57,29 @@ type credentialFormModel struct {
 	generatedUser   string // current generated username value (for esc restore)
 	passwordMode    fieldMode
 	generatedPW     string
+
+	// password policy for generated passwords: built-ins then site presets
+	policies     []formPolicy
+	policyIdx    int
+	policyPicked bool // chosen with ctrl+p, so site presets no longer apply
+}
+
+// formPolicy is a password policy the form can generate with.
+type formPolicy struct {
+	name   string
+	site   string // set for saved presets
+	policy identity.PasswordPolicy
+}
+
+// builtinPolicies are always offered by the credential form.
+func builtinPolicies() []formPolicy {
+	noSymbols := identity.DefaultPasswordPolicy()
+	noSymbols.Symbols = false
+	return []formPolicy{
+		{name: "default", policy: identity.DefaultPasswordPolicy()},
+		{name: "no symbols", policy: noSymbols},
+		{name: "passphrase", policy: identity.PassphrasePolicy(identity.DefaultPassphraseWords)},
+	}
 }
 
 // saveCredentialMsg requests saving a credential.
@@ -85,6 +104,7 @@ func newCredentialFormModel(id identity.Identity, existing *credential.Credentia
 	m := credentialFormModel{
 		inputs:   inputs,
 		identity: id,
+		policies: builtinPolicies(),
 	}
 
 	if existing != nil {
@@ -104,7 +124,7 @@ func newCredentialFormModel(id identity.Identity, existing *credential.Credentia
 		m.generatedUser = m.usernameOptions[0]
 		m.inputs[fieldUsername].SetValue(m.generatedUser)
 
-		m.generatedPW = zcrypto.GeneratePassword(20)
+		m.generatedPW = identity.NewPassword(m.policies[0].policy)
 		m.passwordMode = modeCycle
 		m.inputs[fieldPassword].SetValue(m.generatedPW)
 		// show password in cycle mode
@@ -124,13 +144,16 @@ func buildUsernameOptions(id identity.Identity) []string {
 		initial = string(first[0])
 	}
 
-	return []string{
-		id.Email,
-		first + "." + last,
-		initial + last,
-		first + last,
-		// random handle is generated fresh each time via cycling
+	opts := []string{id.Email}
+	if id.Username != "" {
+		opts = append(opts, id.Username)
 	}
+	return append(opts,
+		first+"."+last,
+		initial+last,
+		first+last,
+		// random handle is generated fresh each time via cycling
+	)
 }
 
 func (m credentialFormModel) Init() tea.Cmd {
@@ -165,13 +188,21 @@ func (m credentialFormModel) handleKey(msg tea.KeyMsg) (credentialFormModel, tea
 		m.inputs[m.focus].Blur()
 		m.focus = (m.focus + 1) % fieldCount
 		m.inputs[m.focus].Focus()
-		return m, textinput.Blink
+		return m.matchPreset(), textinput.Blink
 
 	case "shift+tab":
 		m.inputs[m.focus].Blur()
 		m.focus = (m.focus - 1 + fieldCount) % fieldCount
 		m.inputs[m.focus].Focus()
-		return m, textinput.Blink
+		return m.matchPreset(), textinput.Blink
+
+	case "ctrl+p":
+		if !m.editing && m.passwordMode == modeCycle {
+			m.policyIdx = (m.policyIdx + 1) % len(m.policies)
+			m.policyPicked = true
+			return m.cyclePassword(), nil
+		}
+		return m, nil
 	}
 
 	if key.Matches(msg, zstyle.KeyEnter) {
@@ -253,11 +284,46 @@ func (m credentialFormModel) cycleUsername() credentialFormModel {
 }
 
 func (m credentialFormModel) cyclePassword() credentialFormModel {
-	m.generatedPW = zcrypto.GeneratePassword(20)
+	m.generatedPW = identity.NewPassword(m.policies[m.policyIdx].policy)
 	m.inputs[fieldPassword].SetValue(m.generatedPW)
 	return m
 }
 
+// withPresets adds saved site presets to the policies the form offers.
+func (m credentialFormModel) withPresets(presets []identity.PasswordPreset) credentialFormModel {
+	for _, p := range presets {
+		m.policies = append(m.policies, formPolicy{name: p.Site, site: p.Site, policy: p.Policy})
+	}
+	return m
+}
+
+// matchPreset switches a generated password to the preset saved for the
+// site in the url or label field, unless a policy was picked by hand.
+func (m credentialFormModel) matchPreset() credentialFormModel {
+	if m.editing || m.passwordMode != modeCycle || m.policyPicked {
+		return m
+	}
+
+	site := strings.TrimSpace(m.inputs[fieldURL].Value())
+	if site == "" {
+		site = strings.TrimSpace(m.inputs[fieldLabel].Value())
+	}
+	site = identity.SiteName(site)
+
+	idx := 0
+	for i, p := range m.policies {
+		if p.site != "" && p.site == site {
+			idx = i
+			break
+		}
+	}
+	if idx != m.policyIdx {
+		m.policyIdx = idx
+		m = m.cyclePassword()
+	}
+	return m
+}
+
 func (m credentialFormModel) updateInput(msg tea.Msg) (credentialFormModel, tea.Cmd) {
 	var cmd tea.Cmd
 	m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
@@ -271,13 +337,7 @@ func (m credentialFormModel) submit() (credentialFormModel, tea.Cmd) {
 		return m, clearFlashAfter()
 	}
 
-	totpSecret := strings.TrimSpace(m.inputs[fieldTOTPSecret].Value())
-	if totpSecret != "" {
-		if !isValidBase32(totpSecret) {
-			m.flash = "invalid totp secret (must be base32)"
-			return m, clearFlashAfter()
-		}
-	}
+	totpInput := strings.TrimSpace(m.inputs[fieldTOTPSecret].Value())
 
 	now := time.Now().UTC()
 
@@ -296,7 +356,13 @@ func (m credentialFormModel) submit() (credentialFormModel, tea.Cmd) {
 	c.URL = strings.TrimSpace(m.inputs[fieldURL].Value())
 	c.Username = strings.TrimSpace(m.inputs[fieldUsername].Value())
 	c.Password = m.inputs[fieldPassword].Value()
-	c.TOTPSecret = totpSecret
+	// an unchanged secret keeps the parameters it was imported with
+	if !m.editing || totpInput != m.existing.TOTPSecret {
+		if err := c.SetTOTP(totpInput); err != nil {
+			m.flash = err.Error()
+			return m, clearFlashAfter()
+		}
+	}
 	c.Notes = strings.TrimSpace(m.inputs[fieldNotes].Value())
 
 	return m, func() tea.Msg { return saveCredentialMsg{credential: c} }
@@ -335,6 +401,12 @@ func (m credentialFormModel) View() string {
 		}
 
 		s += fmt.Sprintf("  %s%s %s\n", cursor, label, fieldView)
+
+		if i == fieldPassword && !m.editing && m.passwordMode == modeCycle {
+			p := m.policies[m.policyIdx]
+			hint := fmt.Sprintf("%-12s %s · %s  ctrl+p to change", "", p.name, p.policy)
+			s += "    " + zstyle.MutedText.Render(hint) + "\n"
+		}
 	}
 
 	s += "\n"
@@ -349,19 +421,9 @@ func (m credentialFormModel) View() string {
 }
 
 func isValidBase32(s string) bool {
-	s = strings.ToUpper(strings.TrimSpace(s))
-	s = strings.ReplaceAll(s, " ", "")
-	if pad := len(s) % 8; pad != 0 {
-		s += strings.Repeat("=", 8-pad)
-	}
-	_, err := base32.StdEncoding.DecodeString(s)
-	return err == nil
+	return credential.ValidTOTPSecret(s)
 }
 
 func credentialHexID() string {
-	b := make([]byte, 4)
-	if _, err := rand.Read(b); err != nil {
-		panic("crypto/rand: " + err.Error())
-	}
-	return hex.EncodeToString(b)
+	return credential.NewID()
 }
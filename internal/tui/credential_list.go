@@ -12,13 +12,17 @@ import (
 	"github.com/zarlcorp/zburn/internal/identity"
 )
 
-// credentialListModel displays credentials for a single identity.
+// credentialListModel displays credentials for a single identity,
+// searchable by label, username and URL.
 type credentialListModel struct {
 	identity    identity.Identity
 	credentials []credential.Credential
-	cursor      int
+	cursor      int // index into matches
 	flash       string
 	confirm     bool
+
+	search  searchModel
+	matches []searchMatch
 }
 
 // viewCredentialMsg requests viewing a specific credential.
@@ -45,10 +49,30 @@ func newCredentialListModel(id identity.Identity, creds []credential.Credential)
 	sort.Slice(creds, func(i, j int) bool {
 		return creds[i].Label < creds[j].Label
 	})
-	return credentialListModel{
+	m := credentialListModel{
 		identity:    id,
 		credentials: creds,
+		search:      newSearchModel(),
+	}
+	return m.filter()
+}
+
+// filter re-runs the search and resets the cursor.
+func (m credentialListModel) filter() credentialListModel {
+	m.matches = fuzzySearch(m.search.query(), len(m.credentials), func(i int) ([]string, []string) {
+		c := m.credentials[i]
+		return []string{c.Label, c.Username, c.URL}, nil
+	})
+	m.cursor = 0
+	return m
+}
+
+// selected returns the credential under the cursor.
+func (m credentialListModel) selected() (credential.Credential, bool) {
+	if m.cursor >= len(m.matches) {
+		return credential.Credential{}, false
 	}
+	return m.credentials[m.matches[m.cursor].index], true
 }
 
 func (m credentialListModel) Init() tea.Cmd {
@@ -73,11 +97,19 @@ func (m credentialListModel) handleKey(msg tea.KeyMsg) (credentialListModel, tea
 		return m.handleConfirm(msg)
 	}
 
+	if m.search.active {
+		return m.handleSearchKey(msg)
+	}
+
 	if key.Matches(msg, zstyle.KeyQuit) {
 		return m, tea.Quit
 	}
 
 	if key.Matches(msg, zstyle.KeyBack) {
+		if m.search.query() != "" {
+			m.search = m.search.clear()
+			return m.filter(), nil
+		}
 		return m, func() tea.Msg { return navigateMsg{view: viewDetail} }
 	}
 
@@ -89,27 +121,32 @@ func (m credentialListModel) handleKey(msg tea.KeyMsg) (credentialListModel, tea
 	}
 
 	if key.Matches(msg, zstyle.KeyDown) {
-		if len(m.credentials) > 0 && m.cursor < len(m.credentials)-1 {
+		if m.cursor < len(m.matches)-1 {
 			m.cursor++
 		}
 		return m, nil
 	}
 
 	if key.Matches(msg, zstyle.KeyEnter) {
-		if len(m.credentials) == 0 {
+		c, ok := m.selected()
+		if !ok {
 			return m, nil
 		}
-		c := m.credentials[m.cursor]
 		return m, func() tea.Msg { return viewCredentialMsg{credential: c} }
 	}
 
 	switch msg.String() {
+	case "/":
+		var cmd tea.Cmd
+		m.search, cmd = m.search.start()
+		return m, cmd
+
 	case "a":
 		id := m.identity
 		return m, func() tea.Msg { return addCredentialMsg{identity: id} }
 
 	case "d":
-		if len(m.credentials) == 0 {
+		if _, ok := m.selected(); !ok {
 			return m, nil
 		}
 		m.confirm = true
@@ -119,12 +156,44 @@ func (m credentialListModel) handleKey(msg tea.KeyMsg) (credentialListModel, tea
 	return m, nil
 }
 
+// handleSearchKey handles keys while typing a search: up, down and enter
+// act on the results, esc clears the search, everything else edits it.
+func (m credentialListModel) handleSearchKey(msg tea.KeyMsg) (credentialListModel, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.search = m.search.clear()
+		return m.filter(), nil
+	case tea.KeyEnter:
+		m.search = m.search.stop()
+		if c, ok := m.selected(); ok {
+			return m, func() tea.Msg { return viewCredentialMsg{credential: c} }
+		}
+		return m, nil
+	case tea.KeyUp:
+		if m.cursor > 0 {
+			m.cursor--
+		}
+		return m, nil
+	case tea.KeyDown:
+		if m.cursor < len(m.matches)-1 {
+			m.cursor++
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.search, cmd = m.search.update(msg)
+	return m.filter(), cmd
+}
+
 func (m credentialListModel) handleConfirm(msg tea.KeyMsg) (credentialListModel, tea.Cmd) {
 	switch msg.String() {
 	case "y":
-		id := m.credentials[m.cursor].ID
+		c, _ := m.selected()
 		m.confirm = false
-		return m, func() tea.Msg { return deleteCredentialMsg{id: id} }
+		return m, func() tea.Msg { return deleteCredentialMsg{id: c.ID} }
 	default:
 		m.confirm = false
 		return m, nil
@@ -144,7 +213,14 @@ func (m credentialListModel) View() string {
 		return s
 	}
 
-	for i, c := range m.credentials {
+	s += m.search.View(len(m.matches), len(m.credentials))
+
+	if len(m.matches) == 0 {
+		s += "  " + zstyle.MutedText.Render("no matches") + "\n"
+	}
+
+	for i, match := range m.matches {
+		c := m.credentials[match.index]
 		line := fmt.Sprintf("%-20s %-30s %s",
 			truncate(c.Label, 18), truncate(c.Username, 28), truncate(c.URL, 40))
 
@@ -158,7 +234,8 @@ func (m credentialListModel) View() string {
 	s += "\n"
 
 	if m.confirm {
-		label := m.credentials[m.cursor].Label
+		c, _ := m.selected()
+		label := c.Label
 		s += "  " + zstyle.StatusWarn.Render(fmt.Sprintf("delete credential %q? this cannot be undone. (y/n)", label)) + "\n"
 	} else if m.flash != "" {
 		s += "  " + zstyle.StatusOK.Render(m.flash) + "\n"
@@ -662,6 +662,55 @@ func TestCredentialFormSubmitValidBase32TOTP(t *testing.T) {
 	}
 }
 
+func TestCredentialFormSubmitOTPAuthURI(t *testing.T) {
+	m := newCredentialFormModel(testIdentity(), nil)
+	m.inputs[fieldLabel].SetValue("Acme")
+	m.inputs[fieldTOTPSecret].SetValue("otpauth://totp/Acme:jane?secret=JBSWY3DPEHPK3PXP&issuer=Acme&digits=8")
+
+	_, cmd := m.Update(enterKey())
+	if cmd == nil {
+		t.Fatal("enter should produce save command")
+	}
+	save, ok := cmd().(saveCredentialMsg)
+	if !ok {
+		t.Fatal("should emit saveCredentialMsg")
+	}
+	if save.credential.TOTPSecret != "JBSWY3DPEHPK3PXP" || save.credential.TOTPIssuer != "Acme" || save.credential.TOTPDigits != 8 {
+		t.Errorf("credential = %+v", save.credential)
+	}
+}
+
+func TestCredentialFormEditKeepsTOTPParams(t *testing.T) {
+	c := testCredential()
+	c.TOTPIssuer = "Acme"
+	c.TOTPDigits = 8
+	m := newCredentialFormModel(testIdentity(), &c)
+	m.inputs[fieldLabel].SetValue("Renamed")
+
+	_, cmd := m.Update(enterKey())
+	if cmd == nil {
+		t.Fatal("enter should produce save command")
+	}
+	save := cmd().(saveCredentialMsg)
+	if save.credential.TOTPIssuer != "Acme" || save.credential.TOTPDigits != 8 {
+		t.Errorf("unchanged secret should keep uri params, got %+v", save.credential)
+	}
+}
+
+func TestCredentialDetailTOTPDigits(t *testing.T) {
+	c := testCredential()
+	c.TOTPDigits = 8
+	c.TOTPIssuer = "Acme"
+	m := newCredentialDetailModel(c)
+
+	if len(m.totpCode) != 8 {
+		t.Errorf("totp code length = %d, want 8", len(m.totpCode))
+	}
+	if !strings.Contains(m.View(), "Acme") {
+		t.Error("view should show the issuer")
+	}
+}
+
 func TestCredentialFormBackFromAdd(t *testing.T) {
 	m := newCredentialFormModel(testIdentity(), nil)
 	_, cmd := m.Update(escKey())
@@ -791,6 +840,19 @@ func TestCredentialFormUsernameCycle(t *testing.T) {
 	}
 }
 
+func TestBuildUsernameOptionsIncludesIdentityUsername(t *testing.T) {
+	id := testIdentity()
+	if got := buildUsernameOptions(id); len(got) != 4 {
+		t.Errorf("without username got %d options, want 4: %v", len(got), got)
+	}
+
+	id.Username = "quietfox1234"
+	got := buildUsernameOptions(id)
+	if len(got) != 5 || got[0] != "jane@zburn.id" || got[1] != "quietfox1234" {
+		t.Errorf("identity username should follow email: %v", got)
+	}
+}
+
 func TestCredentialFormPasswordGenerated(t *testing.T) {
 	m := newCredentialFormModel(testIdentity(), nil)
 
@@ -828,6 +890,46 @@ func TestCredentialFormPasswordCycle(t *testing.T) {
 	}
 }
 
+func TestCredentialFormPolicyCycle(t *testing.T) {
+	m := newCredentialFormModel(testIdentity(), nil)
+	for m.focus != fieldPassword {
+		m, _ = m.Update(tabKey())
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	if pw := m.inputs[fieldPassword].Value(); strings.ContainsAny(pw, identity.DefaultSymbols) {
+		t.Errorf("no symbols policy generated %q", pw)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	if pw := m.inputs[fieldPassword].Value(); strings.Count(pw, "-") != identity.DefaultPassphraseWords-1 {
+		t.Errorf("passphrase policy generated %q", pw)
+	}
+	if !strings.Contains(m.View(), "5-word passphrase") {
+		t.Error("view should show the selected policy")
+	}
+}
+
+func TestCredentialFormSitePreset(t *testing.T) {
+	preset := identity.PasswordPreset{
+		Site:   "bank.example",
+		Policy: identity.PasswordPolicy{Length: 8, Digits: true},
+	}
+	m := newCredentialFormModel(testIdentity(), nil).withPresets([]identity.PasswordPreset{preset})
+
+	// type a url, then tab away from the url field
+	m, _ = m.Update(tabKey())
+	for _, r := range "https://www.bank.example/login" {
+		m, _ = m.Update(keyMsg(r))
+	}
+	m, _ = m.Update(tabKey())
+
+	pw := m.inputs[fieldPassword].Value()
+	if len(pw) != 8 || strings.Trim(pw, "0123456789") != "" {
+		t.Errorf("site preset should apply, got %q", pw)
+	}
+}
+
 func TestCredentialFormEditModeNoGeneration(t *testing.T) {
 	c := testCredential()
 	m := newCredentialFormModel(testIdentity(), &c)
@@ -100,10 +100,26 @@ func (m detailModel) handleKey(msg tea.KeyMsg) (detailModel, tea.Cmd) {
 		m.flash = "copied all!"
 		return m, clearFlashAfter()
 
+	case "e":
+		id := m.identity
+		return m, func() tea.Msg { return editIdentityMsg{identity: id} }
+
 	case "w":
 		id := m.identity
 		return m, func() tea.Msg { return viewCredentialsMsg{identity: id} }
 
+	case "i":
+		id := m.identity
+		return m, func() tea.Msg { return viewInboxMsg{identity: id} }
+
+	case "m":
+		id := m.identity
+		return m, func() tea.Msg { return viewSMSMsg{identity: id} }
+
+	case "p":
+		id := m.identity
+		return m, func() tea.Msg { return provisionPhoneMsg{identity: id} }
+
 	case "d":
 		id := m.identity
 		return m, func() tea.Msg { return burnStartMsg{identity: id} }
@@ -117,6 +133,7 @@ func (m detailModel) allFieldsText() string {
 	for _, f := range m.fields {
 		fmt.Fprintf(&b, "%s: %s\n", f.label, f.value)
 	}
+
 	return b.String()
 }
 
@@ -127,11 +144,12 @@ func (m detailModel) View() string {
 	name := zstyle.Subtitle.Render(m.identity.FirstName + " " + m.identity.LastName)
 	s := "\n  " + name + "\n\n"
 
+	width := labelWidth(m.fields)
 	for i, f := range m.fields {
-		if sectionBreaks[i] {
+		if f.section {
 			s += "\n"
 		}
-		label := zstyle.MutedText.Render(fmt.Sprintf("%-10s", f.label))
+		label := zstyle.MutedText.Render(fmt.Sprintf("%-*s", width, f.label))
 		if i == m.cursor {
 			s += "  " + accentStyle.Render("▸") + " " + label + " " + f.value + "\n"
 		} else {
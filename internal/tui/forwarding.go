@@ -5,19 +5,15 @@ import (
 	"fmt"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zarlcorp/zburn/internal/forwarding"
+	"github.com/zarlcorp/zburn/internal/identity"
 	"github.com/zarlcorp/zburn/internal/namecheap"
 )
 
-// excludedDomains are org-owned domains that should never get catch-all forwarding.
-var excludedDomains = []string{
-	"zarlcorp.com",
-	"zarl.dev",
-}
-
 // forwardingResultMsg carries the outcome of a catch-all forwarding batch.
 type forwardingResultMsg struct {
-	successes int
-	failures  int
+	domains  []string // domains whose catch-all was set
+	failures int
 }
 
 // forwardingSetter abstracts the SetForwarding call for testing.
@@ -26,49 +22,86 @@ type forwardingSetter interface {
 }
 
 // setupCatchAllForwarding sets wildcard forwarding on each domain, skipping excluded ones.
-// It continues past individual failures and returns the count of successes and failures.
-func setupCatchAllForwarding(ctx context.Context, setter forwardingSetter, domains []string, gmailAddress string) (successes, failures int) {
-	excluded := buildExclusionSet(excludedDomains)
-	rule := []namecheap.ForwardingRule{{Mailbox: "*", ForwardTo: gmailAddress}}
+// It continues past individual failures and returns the domains that were set
+// and the count of failures.
+func setupCatchAllForwarding(ctx context.Context, setter forwardingSetter, domains []string, gmailAddress string) (set []string, failures int) {
+	rule := []namecheap.ForwardingRule{{Mailbox: forwarding.CatchAll, ForwardTo: gmailAddress}}
 
 	for _, d := range domains {
-		if excluded[d] {
+		if forwarding.Excluded(d) {
 			continue
 		}
 		if err := setter.SetForwarding(ctx, d, rule); err != nil {
 			failures++
 			continue
 		}
-		successes++
+		set = append(set, d)
 	}
 
-	return successes, failures
-}
-
-func buildExclusionSet(domains []string) map[string]bool {
-	m := make(map[string]bool, len(domains))
-	for _, d := range domains {
-		m[d] = true
-	}
-	return m
+	return set, failures
 }
 
 // forwardingCmd returns a tea.Cmd that runs catch-all forwarding setup in the background.
 func forwardingCmd(cfg namecheap.Config, domains []string, gmailAddress string) tea.Cmd {
 	return func() tea.Msg {
 		c := namecheap.NewClient(cfg)
-		ok, fail := setupCatchAllForwarding(context.Background(), c, domains, gmailAddress)
-		return forwardingResultMsg{successes: ok, failures: fail}
+		set, fail := setupCatchAllForwarding(context.Background(), c, domains, gmailAddress)
+		return forwardingResultMsg{domains: set, failures: fail}
 	}
 }
 
 // forwardingFlash builds the flash text for a forwarding result.
 func forwardingFlash(r forwardingResultMsg) string {
 	if r.failures == 0 {
-		return fmt.Sprintf("forwarding set up on %d domains", r.successes)
+		return fmt.Sprintf("forwarding set up on %d domains", len(r.domains))
 	}
-	if r.successes == 0 {
+	if len(r.domains) == 0 {
 		return fmt.Sprintf("forwarding: %d failed", r.failures)
 	}
-	return fmt.Sprintf("forwarding: %d ok, %d failed", r.successes, r.failures)
+	return fmt.Sprintf("forwarding: %d ok, %d failed", len(r.domains), r.failures)
+}
+
+// mailboxForwardingMsg carries the outcome of adding a rule for one identity.
+type mailboxForwardingMsg struct {
+	domain  string
+	mailbox string
+	err     error
+}
+
+// reconcileResultMsg carries the outcome of a forwarding reconciliation.
+type reconcileResultMsg struct {
+	changes []forwarding.Change
+	owned   *forwarding.Ledger // nil if reconciliation did not run
+	err     error
+}
+
+// addMailboxForwardingCmd returns a tea.Cmd that adds a forwarding rule for a
+// single mailbox.
+func addMailboxForwardingCmd(cfg namecheap.Config, domain, mailbox, forwardTo string) tea.Cmd {
+	return func() tea.Msg {
+		c := namecheap.NewClient(cfg)
+		err := c.AddForwarding(context.Background(), domain, mailbox, forwardTo)
+		return mailboxForwardingMsg{domain: domain, mailbox: mailbox, err: err}
+	}
+}
+
+// reconcileCmd returns a tea.Cmd that brings per-mailbox rules in line with
+// the saved identities, removing only rules recorded in created.
+func reconcileCmd(cfg namecheap.Config, domains []string, ids []identity.Identity, created forwarding.Ledger, forwardTo string) tea.Cmd {
+	return func() tea.Msg {
+		c := namecheap.NewClient(cfg)
+		changes, owned, err := forwarding.Reconcile(context.Background(), c, domains, ids, created, forwardTo, true)
+		return reconcileResultMsg{changes: changes, owned: &owned, err: err}
+	}
+}
+
+// reconcileFlash builds the flash text for a reconciliation result.
+func reconcileFlash(r reconcileResultMsg) string {
+	if r.err != nil {
+		return fmt.Sprintf("reconcile: %d fixed, %v", len(r.changes), r.err)
+	}
+	if len(r.changes) == 0 {
+		return "forwarding in sync"
+	}
+	return fmt.Sprintf("forwarding: %d rules fixed", len(r.changes))
 }
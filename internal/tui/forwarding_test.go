@@ -3,8 +3,12 @@ package tui
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
 
+	"github.com/zarlcorp/zburn/internal/config"
+	"github.com/zarlcorp/zburn/internal/forwarding"
+	"github.com/zarlcorp/zburn/internal/gmail"
 	"github.com/zarlcorp/zburn/internal/namecheap"
 )
 
@@ -95,10 +99,10 @@ func TestSetupCatchAllForwarding(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			f := &fakeForwardingSetter{errors: tt.errors}
 
-			ok, fail := setupCatchAllForwarding(context.Background(), f, tt.domains, "user@gmail.com")
+			set, fail := setupCatchAllForwarding(context.Background(), f, tt.domains, "user@gmail.com")
 
-			if ok != tt.wantOK {
-				t.Errorf("successes = %d, want %d", ok, tt.wantOK)
+			if len(set) != tt.wantOK {
+				t.Errorf("successes = %d, want %d", len(set), tt.wantOK)
 			}
 			if fail != tt.wantFail {
 				t.Errorf("failures = %d, want %d", fail, tt.wantFail)
@@ -148,22 +152,22 @@ func TestForwardingFlash(t *testing.T) {
 	}{
 		{
 			name: "all success",
-			msg:  forwardingResultMsg{successes: 8, failures: 0},
+			msg:  forwardingResultMsg{domains: make([]string, 8), failures: 0},
 			want: "forwarding set up on 8 domains",
 		},
 		{
 			name: "mixed",
-			msg:  forwardingResultMsg{successes: 6, failures: 2},
+			msg:  forwardingResultMsg{domains: make([]string, 6), failures: 2},
 			want: "forwarding: 6 ok, 2 failed",
 		},
 		{
 			name: "all failed",
-			msg:  forwardingResultMsg{successes: 0, failures: 3},
+			msg:  forwardingResultMsg{domains: make([]string, 0), failures: 3},
 			want: "forwarding: 3 failed",
 		},
 		{
 			name: "zero domains",
-			msg:  forwardingResultMsg{successes: 0, failures: 0},
+			msg:  forwardingResultMsg{domains: make([]string, 0), failures: 0},
 			want: "forwarding set up on 0 domains",
 		},
 	}
@@ -177,3 +181,106 @@ func TestForwardingFlash(t *testing.T) {
 		})
 	}
 }
+
+func TestForwardingModeToggle(t *testing.T) {
+	nc := NamecheapSettings{Username: "u", APIKey: "k", CachedDomains: []string{"burner.test"}}
+	gm := GmailSettings{Token: &gmail.Token{RefreshToken: "r"}, Email: "me@gmail.com"}
+
+	m := newForwardingModel(nc, gm)
+	m, cmd := m.Update(keyMsg('m'))
+	if cmd == nil {
+		t.Fatal("m should produce command")
+	}
+	msg, ok := cmd().(setForwardingModeMsg)
+	if !ok {
+		t.Fatal("should emit setForwardingModeMsg")
+	}
+	if msg.mode != config.ForwardMailbox {
+		t.Errorf("mode = %q, want %q", msg.mode, config.ForwardMailbox)
+	}
+	if !m.loading {
+		t.Error("toggle should set loading")
+	}
+}
+
+func TestForwardingReconcileRequiresMailboxMode(t *testing.T) {
+	nc := NamecheapSettings{Username: "u", APIKey: "k"}
+	gm := GmailSettings{Token: &gmail.Token{RefreshToken: "r"}, Email: "me@gmail.com"}
+
+	m := newForwardingModel(nc, gm)
+	m, _ = m.Update(keyMsg('r'))
+	if m.flash == "" {
+		t.Error("reconcile in catch-all mode should flash")
+	}
+
+	nc.ForwardingMode = config.ForwardMailbox
+	m = newForwardingModel(nc, gm)
+	_, cmd := m.Update(keyMsg('r'))
+	if cmd == nil {
+		t.Fatal("r should produce command")
+	}
+	if _, ok := cmd().(reconcileForwardingMsg); !ok {
+		t.Error("should emit reconcileForwardingMsg")
+	}
+}
+
+func TestRootSetForwardingModePersists(t *testing.T) {
+	m := setupModel(t)
+	m.ncConfig = NamecheapSettings{Username: "u", APIKey: "k", CachedDomains: []string{"burner.test"}}
+	m.gmConfig = GmailSettings{Token: &gmail.Token{RefreshToken: "r"}, Email: "me@gmail.com"}
+	m.active = viewForwarding
+
+	result, cmd := m.Update(setForwardingModeMsg{mode: config.ForwardMailbox})
+	rm := result.(Model)
+
+	if cmd == nil {
+		t.Error("mode switch should start reconciliation")
+	}
+	if !rm.ncConfig.PerMailbox() || !rm.forwarding.perMailbox {
+		t.Error("mode should be per-mailbox")
+	}
+	if saved := loadConfig[NamecheapSettings](rm.configs, "namecheap"); !saved.PerMailbox() {
+		t.Error("mode should be saved")
+	}
+
+	// saving credentials keeps the chosen mode
+	result, _ = rm.Update(saveNamecheapMsg{settings: NamecheapSettings{Username: "u2", APIKey: "k2"}})
+	rm = result.(Model)
+	if !rm.ncConfig.PerMailbox() {
+		t.Error("credentials save should keep forwarding mode")
+	}
+}
+
+func TestReconcileFlash(t *testing.T) {
+	if got := reconcileFlash(reconcileResultMsg{}); got != "forwarding in sync" {
+		t.Errorf("flash = %q", got)
+	}
+	changes := []forwarding.Change{{Action: forwarding.ActionAdd}}
+	if got := reconcileFlash(reconcileResultMsg{changes: changes}); got != "forwarding: 1 rules fixed" {
+		t.Errorf("flash = %q", got)
+	}
+}
+
+func TestForwardingLedgerRecorded(t *testing.T) {
+	m := setupModel(t)
+	ledger := func() forwarding.Ledger {
+		return loadConfig[forwarding.Ledger](m.configs, config.KeyForwarded)
+	}
+
+	m = processMsg(t, m, forwardingResultMsg{domains: []string{"a.test"}})
+	if !ledger().Has("a.test", forwarding.CatchAll) {
+		t.Errorf("catch-all not recorded: %+v", ledger())
+	}
+
+	m = processMsg(t, m, mailboxForwardingMsg{domain: "b.test", mailbox: "jane"})
+	m = processMsg(t, m, mailboxForwardingMsg{domain: "b.test", mailbox: "failed", err: fmt.Errorf("api error")})
+	if !ledger().Has("b.test", "jane") || ledger().Has("b.test", "failed") {
+		t.Errorf("only rules that were added should be recorded: %+v", ledger())
+	}
+
+	owned := forwarding.Ledger{Mailboxes: map[string][]string{"a.test": {"alice"}}}
+	m = processMsg(t, m, reconcileResultMsg{owned: &owned})
+	if got := ledger(); !reflect.DeepEqual(got, owned) {
+		t.Errorf("ledger = %+v, want %+v", got, owned)
+	}
+}
@@ -14,8 +14,9 @@ import (
 
 // identityField represents a labeled field for display and selection.
 type identityField struct {
-	label string
-	value string
+	label   string
+	value   string
+	section bool // starts a new visual section
 }
 
 // generateModel displays a generated identity with actions.
@@ -26,6 +27,9 @@ type generateModel struct {
 	flash    string
 	flashAt  time.Time
 	domain   string
+
+	// hasTemplates shows the template hint when saved templates exist
+	hasTemplates bool
 }
 
 // saveIdentityMsg requests saving the current identity.
@@ -39,6 +43,9 @@ type identitySavedMsg struct{}
 // cycleDomainMsg requests cycling to the next domain.
 type cycleDomainMsg struct{}
 
+// cycleTemplateMsg requests cycling to the next saved identity template.
+type cycleTemplateMsg struct{}
+
 // flashMsg clears the flash after a timeout.
 type flashMsg struct{}
 
@@ -49,14 +56,49 @@ func newGenerateModel(id identity.Identity, domain string) generateModel {
 }
 
 func identityFields(id identity.Identity) []identityField {
-	return []identityField{
-		{"email", id.Email},
-		{"name", id.FirstName + " " + id.LastName},
-		{"phone", id.Phone},
-		{"street", id.Street},
-		{"address", id.City + ", " + id.State + " " + id.Zip},
-		{"dob", id.DOB.Format("2006-01-02")},
+	address := id.City + ", " + id.State + " " + id.Zip
+	if id.Country != "" && id.Country != identity.DefaultLocale {
+		address += ", " + id.Country
+	}
+	// sections: contact, address, personal and work, security answers,
+	// template fields, then the user's label, tags and notes. Fields
+	// missing from older identities are skipped.
+	fields := []identityField{
+		{label: "email", value: id.Email},
+		{label: "name", value: id.FirstName + " " + id.LastName},
+		{label: "phone", value: id.Phone},
+		{label: "street", value: id.Street, section: true},
+		{label: "address", value: address},
+		{label: "dob", value: id.DOB.Format("2006-01-02"), section: true},
+	}
+	for _, f := range []identityField{
+		{label: "username", value: id.Username},
+		{label: "company", value: id.Company},
+		{label: "job title", value: id.JobTitle},
+	} {
+		if f.value != "" {
+			fields = append(fields, f)
+		}
+	}
+	for i, a := range id.SecurityAnswers {
+		fields = append(fields, identityField{label: a.Topic, value: a.Answer, section: i == 0})
 	}
+	for i, f := range id.Fields {
+		fields = append(fields, identityField{label: f.Name, value: f.Value, section: i == 0})
+	}
+	first := true
+	for _, f := range []identityField{
+		{label: "label", value: id.Label},
+		{label: "tags", value: strings.Join(id.Tags, ", ")},
+		{label: "notes", value: id.Notes},
+	} {
+		if f.value != "" {
+			f.section = first
+			first = false
+			fields = append(fields, f)
+		}
+	}
+	return fields
 }
 
 func (m generateModel) Init() tea.Cmd {
@@ -128,6 +170,9 @@ func (m generateModel) handleKey(msg tea.KeyMsg) (generateModel, tea.Cmd) {
 
 	case " ":
 		return m, func() tea.Msg { return cycleDomainMsg{} }
+
+	case "t":
+		return m, func() tea.Msg { return cycleTemplateMsg{} }
 	}
 
 	return m, nil
@@ -153,22 +198,26 @@ func (m generateModel) allFieldsText() string {
 	return b.String()
 }
 
-// sectionBreaks defines which field indices start a new visual section.
-// contact: email, name, phone (indices 0-2)
-// address: street, address (indices 3-4)
-// personal: dob (index 5)
-var sectionBreaks = map[int]bool{3: true, 5: true}
+// labelWidth is the column width that fits every field label, at least 10.
+func labelWidth(fields []identityField) int {
+	w := 10
+	for _, f := range fields {
+		w = max(w, len(f.label))
+	}
+	return w
+}
 
 func (m generateModel) View() string {
 	accentStyle := lipgloss.NewStyle().Foreground(zstyle.ZburnAccent).Bold(true)
 
 	s := "\n"
 
+	width := labelWidth(m.fields)
 	for i, f := range m.fields {
-		if sectionBreaks[i] {
+		if f.section {
 			s += "\n"
 		}
-		label := zstyle.MutedText.Render(fmt.Sprintf("%-10s", f.label))
+		label := zstyle.MutedText.Render(fmt.Sprintf("%-*s", width, f.label))
 		line := fmt.Sprintf("%s %s", label, f.value)
 		if f.label == "email" && m.domain != "" {
 			line += "  " + zstyle.MutedText.Render("["+m.domain+"]  space to cycle")
@@ -182,6 +231,14 @@ func (m generateModel) View() string {
 
 	s += "\n"
 
+	if m.hasTemplates {
+		tpl := m.identity.Template
+		if tpl == "" {
+			tpl = "none"
+		}
+		s += "  " + zstyle.MutedText.Render("template: "+tpl+"  t to cycle") + "\n\n"
+	}
+
 	// always reserve a line for flash to prevent layout shift
 	if m.flash != "" {
 		s += "  " + zstyle.StatusOK.Render(m.flash) + "\n"
@@ -0,0 +1,144 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zarlcorp/core/pkg/zstyle"
+	"github.com/zarlcorp/zburn/internal/identity"
+)
+
+const (
+	metaLabel = iota
+	metaTags
+	metaNotes
+	metaFieldCount
+)
+
+var metaLabels = [metaFieldCount]string{
+	"label",
+	"tags",
+	"notes",
+}
+
+// editIdentityMsg requests editing an identity's label, tags and notes.
+type editIdentityMsg struct {
+	identity identity.Identity
+}
+
+// updateIdentityMsg requests saving an edited identity.
+type updateIdentityMsg struct {
+	identity identity.Identity
+}
+
+// identityEditModel edits the user-set label, tags and notes of a saved
+// identity. The generated fields are not editable.
+type identityEditModel struct {
+	identity identity.Identity
+	inputs   [metaFieldCount]textinput.Model
+	focus    int
+	flash    string
+}
+
+func newIdentityEditModel(id identity.Identity) identityEditModel {
+	var inputs [metaFieldCount]textinput.Model
+	for i := range metaFieldCount {
+		ti := textinput.New()
+		ti.CharLimit = 256
+		ti.Width = 50
+		ti.Prompt = ""
+		inputs[i] = ti
+	}
+
+	inputs[metaLabel].Placeholder = "what this identity is for, e.g. acme trial"
+	inputs[metaLabel].SetValue(id.Label)
+	inputs[metaTags].Placeholder = "comma separated"
+	inputs[metaTags].SetValue(strings.Join(id.Tags, ", "))
+	inputs[metaNotes].CharLimit = 1024
+	inputs[metaNotes].SetValue(id.Notes)
+	inputs[metaLabel].Focus()
+
+	return identityEditModel{identity: id, inputs: inputs}
+}
+
+func (m identityEditModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m identityEditModel) Update(msg tea.Msg) (identityEditModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+
+		if key.Matches(msg, zstyle.KeyBack) {
+			id := m.identity
+			return m, func() tea.Msg { return viewIdentityMsg{identity: id} }
+		}
+
+		switch msg.String() {
+		case "tab", "down":
+			m.inputs[m.focus].Blur()
+			m.focus = (m.focus + 1) % metaFieldCount
+			m.inputs[m.focus].Focus()
+			return m, textinput.Blink
+
+		case "shift+tab", "up":
+			m.inputs[m.focus].Blur()
+			m.focus = (m.focus - 1 + metaFieldCount) % metaFieldCount
+			m.inputs[m.focus].Focus()
+			return m, textinput.Blink
+		}
+
+		if key.Matches(msg, zstyle.KeyEnter) {
+			return m, m.save()
+		}
+
+	case flashMsg:
+		m.flash = ""
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+	return m, cmd
+}
+
+func (m identityEditModel) save() tea.Cmd {
+	id := m.identity
+	id.Label = strings.TrimSpace(m.inputs[metaLabel].Value())
+	id.Tags = identity.ParseTags(m.inputs[metaTags].Value())
+	id.Notes = strings.TrimSpace(m.inputs[metaNotes].Value())
+	return func() tea.Msg { return updateIdentityMsg{identity: id} }
+}
+
+func (m identityEditModel) View() string {
+	accentStyle := lipgloss.NewStyle().Foreground(zstyle.ZburnAccent).Bold(true)
+
+	header := m.identity.FirstName + " " + m.identity.LastName + "  " + m.identity.Email
+	s := "\n  " + zstyle.MutedText.Render(header) + "\n\n"
+
+	for i := range metaFieldCount {
+		label := zstyle.MutedText.Render(fmt.Sprintf("  %-8s", metaLabels[i]))
+		cursor := "  "
+		if i == m.focus {
+			cursor = accentStyle.Render("▸") + " "
+		}
+		s += fmt.Sprintf("  %s%s %s\n", cursor, label, m.inputs[i].View())
+	}
+
+	s += "\n"
+
+	if m.flash != "" {
+		s += "  " + zstyle.StatusOK.Render(m.flash) + "\n"
+	} else {
+		s += "\n"
+	}
+
+	return s
+}
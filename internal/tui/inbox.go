@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zarlcorp/zburn/internal/codes"
+	"github.com/zarlcorp/zburn/internal/gmail"
+	"github.com/zarlcorp/zburn/internal/identity"
+)
+
+// inboxLimit caps how many messages are fetched per refresh.
+const inboxLimit = 20
+
+// viewInboxMsg requests the Gmail inbox for an identity.
+type viewInboxMsg struct {
+	identity identity.Identity
+}
+
+// inboxResultMsg carries messages fetched for the inbox view.
+type inboxResultMsg struct {
+	messages []inboxMessage
+	token    *gmail.Token // non-nil when the access token was refreshed
+	err      error
+}
+
+func (r inboxResultMsg) result() ([]inboxMessage, error) {
+	return r.messages, r.err
+}
+
+// inboxMessage pairs a fetched message with the codes found in its body.
+type inboxMessage struct {
+	message gmail.Message
+	codes   []codes.Code
+}
+
+// topCode returns the most likely verification code, or "" if none.
+func (im inboxMessage) topCode() string {
+	if len(im.codes) == 0 {
+		return ""
+	}
+	return im.codes[0].Value
+}
+
+// row renders the date, sender and subject of the message.
+func (im inboxMessage) row() string {
+	return fmt.Sprintf("%-12s %-24s %-32s",
+		messageDate(im.message.Date), truncate(im.message.From, 22), truncate(im.message.Subject, 30))
+}
+
+// mailReader abstracts the Gmail calls for testing.
+type mailReader interface {
+	ListMessages(ctx context.Context, query string, maxResults int) ([]gmail.Message, error)
+	GetMessage(ctx context.Context, messageID string) (*gmail.Message, error)
+}
+
+// inboxModel lists mail addressed to an identity with extracted codes.
+type inboxModel = messageList[inboxMessage]
+
+func newInboxModel(id identity.Identity) inboxModel {
+	return inboxModel{
+		identity: id,
+		title:    id.Email,
+		loading:  true,
+		refresh:  func(id identity.Identity) tea.Msg { return viewInboxMsg{identity: id} },
+	}
+}
+
+// fetchInboxCmd returns a tea.Cmd that loads the inbox for an email address,
+// refreshing the Gmail access token first when it is about to expire.
+func fetchInboxCmd(s GmailSettings, email string) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		tok, refreshed, err := s.AccessToken(ctx)
+		if err != nil {
+			return inboxResultMsg{err: err}
+		}
+
+		msgs, err := fetchInbox(ctx, gmail.NewClient(tok.AccessToken), email, inboxLimit)
+		res := inboxResultMsg{messages: msgs, err: err}
+		if refreshed {
+			res.token = tok
+		}
+		return res
+	}
+}
+
+// fetchInbox lists messages addressed to email and extracts codes from each.
+// Messages that fail to load are skipped so one bad message does not hide
+// the rest of the inbox.
+func fetchInbox(ctx context.Context, r mailReader, email string, limit int) ([]inboxMessage, error) {
+	refs, err := r.ListMessages(ctx, "to:"+email, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make([]inboxMessage, 0, len(refs))
+	for _, ref := range refs {
+		full, err := r.GetMessage(ctx, ref.ID)
+		if err != nil {
+			continue
+		}
+		msgs = append(msgs, inboxMessage{
+			message: *full,
+			codes:   codes.Extract(full.Subject + "\n" + full.Body),
+		})
+	}
+
+	return msgs, nil
+}
@@ -0,0 +1,253 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zarlcorp/zburn/internal/codes"
+	"github.com/zarlcorp/zburn/internal/gmail"
+)
+
+// fakeMailReader serves canned messages and records the list query.
+type fakeMailReader struct {
+	query    string
+	messages map[string]*gmail.Message
+	order    []string
+	listErr  error
+}
+
+func (f *fakeMailReader) ListMessages(_ context.Context, query string, _ int) ([]gmail.Message, error) {
+	f.query = query
+	if f.listErr != nil {
+		return nil, f.listErr
+	}
+	refs := make([]gmail.Message, len(f.order))
+	for i, id := range f.order {
+		refs[i] = gmail.Message{ID: id}
+	}
+	return refs, nil
+}
+
+func (f *fakeMailReader) GetMessage(_ context.Context, id string) (*gmail.Message, error) {
+	msg, ok := f.messages[id]
+	if !ok {
+		return nil, fmt.Errorf("not found")
+	}
+	return msg, nil
+}
+
+func testInboxMessages() []inboxMessage {
+	return []inboxMessage{
+		{
+			message: gmail.Message{
+				ID:      "m1",
+				From:    "Acme <no-reply@acme.com>",
+				Subject: "Your Acme code",
+				Date:    time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+			},
+			codes: []codes.Code{{Value: "482913", Type: "numeric"}},
+		},
+		{
+			message: gmail.Message{
+				ID:      "m2",
+				From:    "news@example.com",
+				Subject: "Weekly digest",
+			},
+		},
+	}
+}
+
+func TestFetchInbox(t *testing.T) {
+	r := &fakeMailReader{
+		order: []string{"m1", "m2", "missing"},
+		messages: map[string]*gmail.Message{
+			"m1": {ID: "m1", Subject: "Verify", Body: "Your verification code is 123456"},
+			"m2": {ID: "m2", Subject: "Hello", Body: "no codes here"},
+		},
+	}
+
+	msgs, err := fetchInbox(context.Background(), r, "jane@zburn.id", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.query != "to:jane@zburn.id" {
+		t.Errorf("query = %q, want %q", r.query, "to:jane@zburn.id")
+	}
+
+	// the missing message is skipped
+	if len(msgs) != 2 {
+		t.Fatalf("got %d messages, want 2", len(msgs))
+	}
+	if got := msgs[0].topCode(); got != "123456" {
+		t.Errorf("top code = %q, want %q", got, "123456")
+	}
+	if got := msgs[1].topCode(); got != "" {
+		t.Errorf("top code = %q, want empty", got)
+	}
+}
+
+func TestFetchInboxListError(t *testing.T) {
+	r := &fakeMailReader{listErr: fmt.Errorf("status 401")}
+
+	_, err := fetchInbox(context.Background(), r, "jane@zburn.id", 10)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestGmailAccessTokenValid(t *testing.T) {
+	s := GmailSettings{Token: &gmail.Token{
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+		Expiry:       time.Now().Add(time.Hour),
+	}}
+
+	tok, refreshed, err := s.AccessToken(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refreshed {
+		t.Error("valid token should not be refreshed")
+	}
+	if tok.AccessToken != "access" {
+		t.Errorf("access token = %q, want %q", tok.AccessToken, "access")
+	}
+}
+
+func TestGmailAccessTokenMissing(t *testing.T) {
+	if _, _, err := (GmailSettings{}).AccessToken(context.Background()); err == nil {
+		t.Error("expected error without token")
+	}
+}
+
+func TestInboxViewLoading(t *testing.T) {
+	m := newInboxModel(testIdentity())
+	if !strings.Contains(m.View(), "loading") {
+		t.Error("new inbox should show loading")
+	}
+}
+
+func TestInboxViewShowsMessages(t *testing.T) {
+	m := newInboxModel(testIdentity())
+	m, _ = m.Update(inboxResultMsg{messages: testInboxMessages()})
+
+	view := m.View()
+	for _, want := range []string{"jane@zburn.id", "Your Acme code", "482913", "Weekly digest"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q", want)
+		}
+	}
+}
+
+func TestInboxViewEmpty(t *testing.T) {
+	m := newInboxModel(testIdentity())
+	m, _ = m.Update(inboxResultMsg{})
+
+	if !strings.Contains(m.View(), "no messages") {
+		t.Error("empty inbox should say no messages")
+	}
+}
+
+func TestInboxViewError(t *testing.T) {
+	m := newInboxModel(testIdentity())
+	m, _ = m.Update(inboxResultMsg{err: fmt.Errorf("status 403")})
+
+	if !strings.Contains(m.View(), "status 403") {
+		t.Error("view should show fetch error")
+	}
+}
+
+func TestInboxNavigation(t *testing.T) {
+	m := newInboxModel(testIdentity())
+	m, _ = m.Update(inboxResultMsg{messages: testInboxMessages()})
+
+	m, _ = m.Update(keyMsg('j'))
+	if m.cursor != 1 {
+		t.Errorf("cursor = %d, want 1", m.cursor)
+	}
+
+	// clamped at the last message
+	m, _ = m.Update(keyMsg('j'))
+	if m.cursor != 1 {
+		t.Errorf("cursor = %d, want 1", m.cursor)
+	}
+
+	m, _ = m.Update(keyMsg('k'))
+	if m.cursor != 0 {
+		t.Errorf("cursor = %d, want 0", m.cursor)
+	}
+}
+
+func TestInboxCopyWithoutCode(t *testing.T) {
+	m := newInboxModel(testIdentity())
+	m, _ = m.Update(inboxResultMsg{messages: testInboxMessages()})
+	m.cursor = 1
+
+	m, _ = m.Update(enterKey())
+	if m.flash != "no code in message" {
+		t.Errorf("flash = %q, want %q", m.flash, "no code in message")
+	}
+}
+
+func TestInboxRefresh(t *testing.T) {
+	m := newInboxModel(testIdentity())
+	m, _ = m.Update(inboxResultMsg{})
+
+	m, cmd := m.Update(keyMsg('r'))
+	if cmd == nil {
+		t.Fatal("r should produce command")
+	}
+	if !m.loading {
+		t.Error("refresh should set loading")
+	}
+	if _, ok := cmd().(viewInboxMsg); !ok {
+		t.Error("refresh should emit viewInboxMsg")
+	}
+}
+
+func TestInboxBackToDetail(t *testing.T) {
+	m := newInboxModel(testIdentity())
+	_, cmd := m.Update(escKey())
+	if cmd == nil {
+		t.Fatal("esc should produce command")
+	}
+	nav, ok := cmd().(navigateMsg)
+	if !ok || nav.view != viewDetail {
+		t.Error("esc should navigate to detail")
+	}
+}
+
+func TestDetailINavigatesToInbox(t *testing.T) {
+	m := newDetailModel(testIdentity())
+	_, cmd := m.Update(keyMsg('i'))
+	if cmd == nil {
+		t.Fatal("i should produce command")
+	}
+	msg, ok := cmd().(viewInboxMsg)
+	if !ok {
+		t.Fatal("should emit viewInboxMsg")
+	}
+	if msg.identity.ID != "abc12345" {
+		t.Errorf("identity ID = %q, want %q", msg.identity.ID, "abc12345")
+	}
+}
+
+func TestRootInboxRequiresGmail(t *testing.T) {
+	m := New("1.0", t.TempDir(), nil, false)
+	m.active = viewDetail
+	m.detail = newDetailModel(testIdentity())
+
+	result, _ := m.Update(viewInboxMsg{identity: testIdentity()})
+	rm := result.(Model)
+
+	if rm.active != viewDetail {
+		t.Errorf("active = %d, want viewDetail", rm.active)
+	}
+	if rm.detail.flash != "gmail not connected" {
+		t.Errorf("flash = %q, want %q", rm.detail.flash, "gmail not connected")
+	}
+}
@@ -2,14 +2,18 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/zarlcorp/core/pkg/zfilesystem"
 	"github.com/zarlcorp/core/pkg/zstore"
 	"github.com/zarlcorp/zburn/internal/burn"
+	"github.com/zarlcorp/zburn/internal/config"
 	"github.com/zarlcorp/zburn/internal/credential"
+	"github.com/zarlcorp/zburn/internal/forwarding"
 	"github.com/zarlcorp/zburn/internal/gmail"
 	"github.com/zarlcorp/zburn/internal/identity"
 )
@@ -47,11 +51,17 @@ func setupModel(t *testing.T) Model {
 		t.Fatal(err)
 	}
 
+	phoneCol, err := zstore.NewCollection[burn.PhoneConfig](s, "phones")
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	m := New("1.0", t.TempDir(), identity.New(), false)
 	m.store = s
 	m.identities = idCol
 	m.credentials = credCol
 	m.configs = cfgCol
+	m.phones = phoneCol
 	m.active = viewMenu
 	return m
 }
@@ -186,6 +196,30 @@ func TestIntegrationCredentialEditPersists(t *testing.T) {
 	}
 }
 
+func TestIntegrationIdentityEditPersists(t *testing.T) {
+	m := setupModel(t)
+	id := testIdentity()
+	m = saveIdentity(t, m, id)
+
+	edited := id
+	edited.Label = "acme trial"
+	edited.Tags = []string{"acme"}
+	edited.Notes = "cancel before day 14"
+	m = processMsg(t, m, updateIdentityMsg{identity: edited})
+
+	if m.active != viewDetail || m.detail.identity.Label != "acme trial" {
+		t.Fatalf("should return to the updated detail view, active = %d", m.active)
+	}
+
+	got, err := m.identities.Get(id.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Notes != "cancel before day 14" || !got.HasTag("acme") {
+		t.Errorf("stored identity = %+v", got)
+	}
+}
+
 func TestIntegrationCredentialDeleteOthersRemain(t *testing.T) {
 	m := setupModel(t)
 	id := testIdentity()
@@ -624,14 +658,170 @@ func TestIntegrationBurnWithFailingExternalService(t *testing.T) {
 		t.Error("should have errors when external services fail")
 	}
 
-	// identity should still be deleted (best-effort)
-	ids, err := m.identities.List()
-	if err != nil {
+	// the identity is kept while its number is still provisioned
+	if _, err := m.identities.Get(id.ID); err != nil {
+		t.Errorf("identity should be kept when its phone is not released: %v", err)
+	}
+}
+
+func TestIntegrationBurnKeepsIdentityWithoutTwilio(t *testing.T) {
+	m := setupModel(t)
+
+	gen := identity.New()
+	id := gen.Generate("")
+	m = saveIdentity(t, m, id)
+
+	if err := m.phones.Put(id.ID, burn.PhoneConfig{NumberSID: "PN_stored", PhoneNumber: "+441234567890"}); err != nil {
 		t.Fatal(err)
 	}
-	for _, i := range ids {
-		if i.ID == id.ID {
-			t.Error("identity should still be deleted even when external services fail")
+
+	req := m.buildBurnRequest(id)
+	if req.Phone == nil || req.Releaser != nil {
+		t.Fatalf("stored number should be planned without a releaser: %+v", req)
+	}
+	result := burn.Execute(context.Background(), req)
+
+	if !result.HasErrors() {
+		t.Error("an unreleased number should be reported as a failed step")
+	}
+	if _, err := m.identities.Get(id.ID); err != nil {
+		t.Errorf("identity should be kept: %v", err)
+	}
+	if _, err := m.phones.Get(id.ID); err != nil {
+		t.Errorf("phone mapping should be kept: %v", err)
+	}
+}
+
+func TestIntegrationBurnReleasesStoredPhone(t *testing.T) {
+	m := setupModel(t)
+
+	gen := identity.New()
+	id := gen.Generate("")
+	m = saveIdentity(t, m, id)
+
+	if err := m.phones.Put(id.ID, burn.PhoneConfig{NumberSID: "PN_stored", PhoneNumber: "+441234567890"}); err != nil {
+		t.Fatal(err)
+	}
+
+	rel := &fakeReleaser{}
+	m.SetExternalServices(ExternalServices{Releaser: rel})
+
+	req := m.buildBurnRequest(id)
+	result := burn.Execute(context.Background(), req)
+
+	if result.HasErrors() {
+		t.Errorf("unexpected errors: %s", result.Summary())
+	}
+	if len(rel.calls) != 1 || rel.calls[0] != "PN_stored" {
+		t.Errorf("releaser calls = %v, want [PN_stored]", rel.calls)
+	}
+
+	// the mapping is gone once the number is released
+	if _, err := m.phones.Get(id.ID); !errors.Is(err, zstore.ErrNotFound) {
+		t.Errorf("phone mapping err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestIntegrationBurnWiresTwilioFromSettings(t *testing.T) {
+	m := setupModel(t)
+
+	gen := identity.New()
+	id := gen.Generate("")
+	m = saveIdentity(t, m, id)
+
+	if err := m.phones.Put(id.ID, burn.PhoneConfig{NumberSID: "PN_stored", PhoneNumber: "+441234567890"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// without twilio settings nothing can release the number
+	if req := m.buildBurnRequest(id); req.Releaser != nil {
+		t.Error("releaser should be nil without twilio settings")
+	}
+
+	m.twConfig = TwilioSettings{AccountSID: "AC123", AuthToken: "tok"}
+	req := m.buildBurnRequest(id)
+	if req.Releaser == nil {
+		t.Fatal("releaser should be built from twilio settings")
+	}
+	if req.Phone == nil || req.Phone.NumberSID != "PN_stored" {
+		t.Errorf("phone = %+v, want PN_stored", req.Phone)
+	}
+	if req.Phones == nil {
+		t.Error("phone store should be set so the mapping is removed")
+	}
+}
+
+type fakeForwardingRemover struct {
+	removed []string
+}
+
+func (f *fakeForwardingRemover) RemoveForwarding(_ context.Context, domain, mailbox string) error {
+	f.removed = append(f.removed, mailbox+"@"+domain)
+	return nil
+}
+
+func TestIntegrationBurnRemovesForwarding(t *testing.T) {
+	m := setupModel(t)
+	m.ncConfig = NamecheapSettings{Username: "user", APIKey: "key", CachedDomains: []string{"burner.test"}, ForwardingMode: config.ForwardMailbox}
+
+	gen := identity.New()
+	id := gen.Generate("burner.test")
+	m = saveIdentity(t, m, id)
+
+	fwd := &fakeForwardingRemover{}
+	m.SetExternalServices(ExternalServices{Forwarding: fwd})
+
+	// a rule zburn did not create is left alone
+	if req := m.buildBurnRequest(id); req.Forwarding != nil {
+		t.Fatal("forwarding remover should not be set for a manual rule")
+	}
+
+	mailbox, _, _ := strings.Cut(id.Email, "@")
+	if err := m.recordForwarding(func(l forwarding.Ledger) forwarding.Ledger { return l.Add("burner.test", mailbox) }); err != nil {
+		t.Fatal(err)
+	}
+
+	req := m.buildBurnRequest(id)
+	result := burn.Execute(context.Background(), req)
+
+	if result.HasErrors() {
+		t.Errorf("unexpected errors: %s", result.Summary())
+	}
+	if len(fwd.removed) != 1 || fwd.removed[0] != id.Email {
+		t.Errorf("removed = %v, want [%s]", fwd.removed, id.Email)
+	}
+	if loadConfig[forwarding.Ledger](m.configs, config.KeyForwarded).Has("burner.test", mailbox) {
+		t.Error("removed rule should be dropped from the ledger")
+	}
+}
+
+func TestIntegrationBurnSkipsUnmanagedDomain(t *testing.T) {
+	m := setupModel(t)
+	m.ncConfig = NamecheapSettings{Username: "user", APIKey: "key", CachedDomains: []string{"burner.test", "zarlcorp.com"}, ForwardingMode: config.ForwardMailbox}
+	m.SetExternalServices(ExternalServices{Forwarding: &fakeForwardingRemover{}})
+
+	gen := identity.New()
+	for _, domain := range []string{"other.test", "zarlcorp.com"} {
+		id := gen.Generate(domain)
+		if req := m.buildBurnRequest(id); req.Forwarding != nil {
+			t.Errorf("%s: forwarding remover should not be set", domain)
+		}
+	}
+}
+
+func TestIntegrationBurnCatchAllKeepsForwarding(t *testing.T) {
+	m := setupModel(t)
+	m.ncConfig = NamecheapSettings{Username: "user", APIKey: "key", CachedDomains: []string{"burner.test"}}
+	m.SetExternalServices(ExternalServices{Forwarding: &fakeForwardingRemover{}})
+
+	id := identity.New().Generate("burner.test")
+	req := m.buildBurnRequest(id)
+	if req.Forwarding != nil {
+		t.Error("catch-all mode has no per-identity rule to remove")
+	}
+	for _, step := range burn.Plan(req) {
+		if strings.Contains(step, "forwarding") {
+			t.Errorf("plan should not mention forwarding: %q", step)
 		}
 	}
 }
@@ -795,6 +985,42 @@ func TestIntegrationPasswordToStoreFlow(t *testing.T) {
 	}
 }
 
+func TestIntegrationChangeMasterPassword(t *testing.T) {
+	dir := t.TempDir()
+	m := New("1.0", dir, identity.New(), true)
+	m = processMsg(t, m, passwordSubmitMsg{password: "old"})
+	m = saveIdentity(t, m, testIdentity())
+	m = processMsg(t, m, navigateMsg{view: viewSettingsPassword})
+
+	result, cmd := m.Update(changePasswordMsg{current: "old", next: "new"})
+	m = result.(Model)
+	if cmd == nil {
+		t.Fatal("change should produce rekey command")
+	}
+	m = processMsg(t, m, cmd())
+
+	if m.active != viewSettingsPassword || m.settingsPassword.flash != "master password changed" {
+		t.Fatalf("active = %d, flash = %q", m.active, m.settingsPassword.flash)
+	}
+
+	// the reopened store works with the new keys
+	ids, err := m.identities.List()
+	if err != nil || len(ids) != 1 {
+		t.Fatalf("identities = %d, %v", len(ids), err)
+	}
+	m.Close()
+
+	fs := zfilesystem.NewOSFileSystem(dir)
+	if _, err := zstore.Open(fs, []byte("old")); err != zstore.ErrWrongPassword {
+		t.Errorf("old password: err = %v, want ErrWrongPassword", err)
+	}
+	s, err := zstore.Open(fs, []byte("new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+}
+
 // full user flow: password → save identity → browse → view → burn
 
 func TestIntegrationFullUserFlow(t *testing.T) {
@@ -911,3 +1137,30 @@ func TestIntegrationSettingsAffectBurnPlan(t *testing.T) {
 		t.Errorf("plan steps with external = %d, want 2", len(rm.burn.plan))
 	}
 }
+
+func TestIntegrationSearchFindsIdentityByCredential(t *testing.T) {
+	m := setupModel(t)
+	id := testIdentity()
+	m = saveIdentity(t, m, id)
+	m = saveIdentity(t, m, identity.Identity{ID: "other", FirstName: "Bob", LastName: "Smith", Email: "bob@zburn.id"})
+	m = saveCredential(t, m, testCredential())
+
+	m = processMsg(t, m, navigateMsg{view: viewList})
+	m = processMsg(t, m, keyMsg('/'))
+	for _, r := range "github" {
+		m = processMsg(t, m, keyMsg(r))
+	}
+	if len(m.list.matches) != 1 {
+		t.Fatalf("matches = %d, want 1", len(m.list.matches))
+	}
+
+	result, cmd := m.Update(enterKey())
+	m = result.(Model)
+	if cmd == nil {
+		t.Fatal("enter should open the identity")
+	}
+	m = processMsg(t, m, cmd())
+	if m.active != viewDetail || m.detail.identity.ID != id.ID {
+		t.Errorf("active = %d, identity = %q; want detail of %q", m.active, m.detail.identity.ID, id.ID)
+	}
+}
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -10,12 +11,18 @@ import (
 	"github.com/zarlcorp/zburn/internal/identity"
 )
 
-// listModel displays saved identities in a scrollable list.
+// listModel displays saved identities in a scrollable list. Search
+// covers each identity's name, email, label and tags, and the labels and
+// URLs of its credentials, so a site finds the identity that signed up.
 type listModel struct {
 	identities []identity.Identity
 	credCounts map[string]int
-	cursor     int
+	credSites  map[string][]string // credential labels and URLs by identity ID
+	cursor     int                 // index into matches
 	flash      string
+
+	search  searchModel
+	matches []searchMatch
 }
 
 // loadIdentitiesMsg carries identities loaded from the store.
@@ -42,7 +49,38 @@ type viewIdentityMsg struct {
 }
 
 func newListModel(ids []identity.Identity) listModel {
-	return listModel{identities: ids}
+	m := listModel{identities: ids, search: newSearchModel()}
+	return m.filter()
+}
+
+// withCredSites sets the credential sites searched for each identity.
+func (m listModel) withCredSites(sites map[string][]string) listModel {
+	m.credSites = sites
+	return m.filter()
+}
+
+// filter re-runs the search and resets the cursor.
+func (m listModel) filter() listModel {
+	m.matches = fuzzySearch(m.search.query(), len(m.identities), func(i int) ([]string, []string) {
+		id := m.identities[i]
+		own := []string{
+			id.FirstName + " " + id.LastName,
+			id.Email,
+			id.Label,
+			strings.Join(id.Tags, " "),
+		}
+		return own, m.credSites[id.ID]
+	})
+	m.cursor = 0
+	return m
+}
+
+// selected returns the identity under the cursor.
+func (m listModel) selected() (identity.Identity, bool) {
+	if m.cursor >= len(m.matches) {
+		return identity.Identity{}, false
+	}
+	return m.identities[m.matches[m.cursor].index], true
 }
 
 func (m listModel) Init() tea.Cmd {
@@ -56,8 +94,7 @@ func (m listModel) Update(msg tea.Msg) (listModel, tea.Cmd) {
 
 	case loadIdentitiesMsg:
 		m.identities = msg.identities
-		m.cursor = 0
-		return m, nil
+		return m.filter(), nil
 
 	case identityDeletedMsg:
 		m.flash = "deleted"
@@ -73,18 +110,22 @@ func (m listModel) Update(msg tea.Msg) (listModel, tea.Cmd) {
 }
 
 func (m listModel) handleKey(msg tea.KeyMsg) (listModel, tea.Cmd) {
+	if m.search.active {
+		return m.handleSearchKey(msg)
+	}
+
 	if key.Matches(msg, zstyle.KeyQuit) {
 		return m, tea.Quit
 	}
 
 	if key.Matches(msg, zstyle.KeyBack) {
+		if m.search.query() != "" {
+			m.search = m.search.clear()
+			return m.filter(), nil
+		}
 		return m, func() tea.Msg { return navigateMsg{view: viewMenu} }
 	}
 
-	if len(m.identities) == 0 {
-		return m, nil
-	}
-
 	if key.Matches(msg, zstyle.KeyUp) {
 		if m.cursor > 0 {
 			m.cursor--
@@ -93,25 +134,69 @@ func (m listModel) handleKey(msg tea.KeyMsg) (listModel, tea.Cmd) {
 	}
 
 	if key.Matches(msg, zstyle.KeyDown) {
-		if m.cursor < len(m.identities)-1 {
+		if m.cursor < len(m.matches)-1 {
 			m.cursor++
 		}
 		return m, nil
 	}
 
 	if key.Matches(msg, zstyle.KeyEnter) {
-		id := m.identities[m.cursor]
+		id, ok := m.selected()
+		if !ok {
+			return m, nil
+		}
 		return m, func() tea.Msg { return viewIdentityMsg{identity: id} }
 	}
 
-	if msg.String() == "d" {
-		id := m.identities[m.cursor]
+	switch msg.String() {
+	case "/":
+		var cmd tea.Cmd
+		m.search, cmd = m.search.start()
+		return m, cmd
+
+	case "d":
+		id, ok := m.selected()
+		if !ok {
+			return m, nil
+		}
 		return m, func() tea.Msg { return burnStartMsg{identity: id} }
 	}
 
 	return m, nil
 }
 
+// handleSearchKey handles keys while typing a search: up, down and enter
+// act on the results, esc clears the search, everything else edits it.
+func (m listModel) handleSearchKey(msg tea.KeyMsg) (listModel, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.search = m.search.clear()
+		return m.filter(), nil
+	case tea.KeyEnter:
+		m.search = m.search.stop()
+		if id, ok := m.selected(); ok {
+			return m, func() tea.Msg { return viewIdentityMsg{identity: id} }
+		}
+		return m, nil
+	case tea.KeyUp:
+		if m.cursor > 0 {
+			m.cursor--
+		}
+		return m, nil
+	case tea.KeyDown:
+		if m.cursor < len(m.matches)-1 {
+			m.cursor++
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.search, cmd = m.search.update(msg)
+	return m.filter(), cmd
+}
+
 func (m listModel) View() string {
 	accentStyle := lipgloss.NewStyle().Foreground(zstyle.ZburnAccent).Bold(true)
 
@@ -125,7 +210,14 @@ func (m listModel) View() string {
 		return s
 	}
 
-	for i, id := range m.identities {
+	s += m.search.View(len(m.matches), len(m.identities))
+
+	if len(m.matches) == 0 {
+		s += "  " + zstyle.MutedText.Render("no matches") + "\n"
+	}
+
+	for i, match := range m.matches {
+		id := m.identities[match.index]
 		name := truncate(id.FirstName+" "+id.LastName, 20)
 		email := truncate(id.Email, 30)
 		line := fmt.Sprintf("%-20s %-30s", name, email)
@@ -133,6 +225,13 @@ func (m listModel) View() string {
 		if n := m.credCounts[id.ID]; n > 0 {
 			line += "  " + zstyle.MutedText.Render(fmt.Sprintf("(%d)", n))
 		}
+		if meta := id.Meta(); meta != "" {
+			line += "  " + zstyle.MutedText.Render(truncate(meta, 40))
+		}
+		// name the credential when only a credential site matched
+		if match.siteOnly {
+			line += "  " + zstyle.MutedText.Render("via "+truncate(m.credSites[id.ID][match.site], 30))
+		}
 
 		if i == m.cursor {
 			s += "  " + accentStyle.Render("▸") + " " + line + "\n"
@@ -154,8 +253,9 @@ func (m listModel) View() string {
 }
 
 func truncate(s string, max int) string {
-	if len(s) <= max {
+	r := []rune(s)
+	if len(r) <= max {
 		return s
 	}
-	return s[:max-1] + "…"
+	return string(r[:max-1]) + "…"
 }
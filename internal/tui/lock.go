@@ -0,0 +1,139 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zarlcorp/zburn/internal/config"
+)
+
+// keyLock locks the vault from any view.
+var keyLock = key.NewBinding(key.WithKeys("ctrl+l"), key.WithHelp("ctrl+l", "lock"))
+
+// lockTimeouts are the idle timeouts settings cycles through; 0 is off.
+var lockTimeouts = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	15 * time.Minute,
+	30 * time.Minute,
+	time.Hour,
+	0,
+}
+
+// idleTickMsg fires when the vault may have been idle for the lock
+// timeout. Ticks from before the last unlock or timeout change carry an
+// old gen and are ignored.
+type idleTickMsg struct {
+	gen int
+}
+
+// setLockTimeoutMsg requests saving a new idle timeout.
+type setLockTimeoutMsg struct {
+	timeout time.Duration
+}
+
+// nextLockTimeout returns the timeout after d in lockTimeouts.
+func nextLockTimeout(d time.Duration) time.Duration {
+	i := slices.Index(lockTimeouts, d)
+	return lockTimeouts[(i+1)%len(lockTimeouts)]
+}
+
+// lockTimeoutLabel renders a timeout for settings, e.g. "15m" or "off".
+func lockTimeoutLabel(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return "off"
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+	return d.String()
+}
+
+// idleCheck schedules the next idle check, or nothing when auto-lock is off.
+func (m Model) idleCheck(after time.Duration) tea.Cmd {
+	if m.lockConfig.Timeout() <= 0 {
+		return nil
+	}
+	gen := m.idleGen
+	return tea.Tick(after, func(time.Time) tea.Msg { return idleTickMsg{gen: gen} })
+}
+
+// startIdleTimer restarts the idle timer, dropping any pending check.
+func (m Model) startIdleTimer() (Model, tea.Cmd) {
+	m.lastActivity = time.Now()
+	m.idleGen++
+	return m, m.idleCheck(m.lockConfig.Timeout())
+}
+
+func (m Model) handleIdleTick(msg idleTickMsg) (tea.Model, tea.Cmd) {
+	timeout := m.lockConfig.Timeout()
+	if msg.gen != m.idleGen || timeout <= 0 || m.store == nil {
+		return m, nil
+	}
+
+	idle := time.Since(m.lastActivity)
+	if idle >= timeout {
+		return m.requestLock("locked after " + lockTimeoutLabel(timeout) + " idle")
+	}
+	return m, m.idleCheck(timeout - idle)
+}
+
+func (m Model) handleSetLockTimeout(d time.Duration) (tea.Model, tea.Cmd) {
+	s := config.LockAfter(d)
+	if err := saveConfig(m.configs, config.KeyLock, s); err != nil {
+		m.settings.flash = "save: " + err.Error()
+		return m, clearFlashAfter()
+	}
+	m.lockConfig = s
+	m.settings.lock = s
+	return m.startIdleTimer()
+}
+
+// busy reports whether a command that changes the vault or pays for
+// something is in flight. Its result would be dropped by the locked prompt:
+// a rekey would leave the store open with keys from the old password, a
+// bought number would never be recorded, and a burn would lose its store.
+func (m Model) busy() bool {
+	return m.rekeying || m.buying || m.burning
+}
+
+// requestLock locks now, or once the busy commands have finished.
+func (m Model) requestLock(notice string) (tea.Model, tea.Cmd) {
+	if m.busy() {
+		m.pendingLock = notice
+		return m, nil
+	}
+	return m.lock(notice)
+}
+
+// resumeLock applies a lock deferred by requestLock once the result that
+// produced next has been handled and nothing else is in flight.
+func resumeLock(next tea.Model, cmd tea.Cmd) (tea.Model, tea.Cmd) {
+	m, ok := next.(Model)
+	if !ok || m.pendingLock == "" || m.busy() {
+		return next, cmd
+	}
+	notice := m.pendingLock
+	m.pendingLock = ""
+	return m.lock(notice)
+}
+
+// lock closes the store and returns to the password prompt with a fresh
+// model, so no identity, credential, cached config or view state read
+// from the vault survives.
+func (m Model) lock(notice string) (tea.Model, tea.Cmd) {
+	m.Close()
+
+	locked := New(m.version, m.dataDir, m.gen, false)
+	locked.external = m.external
+	locked.width = m.width
+	locked.height = m.height
+	locked.idleGen = m.idleGen + 1
+	locked.password.notice = notice
+	return locked, tea.Batch(tea.ClearScreen, locked.Init())
+}
@@ -0,0 +1,274 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zarlcorp/zburn/internal/config"
+	"github.com/zarlcorp/zburn/internal/twilio"
+)
+
+// unlockedModel opens a fresh store through the password flow.
+func unlockedModel(t *testing.T) Model {
+	t.Helper()
+	m := New("1.0", t.TempDir(), nil, true)
+	m = processMsg(t, m, passwordSubmitMsg{password: "testpass"})
+	if m.store == nil {
+		t.Fatal("store should be open")
+	}
+	t.Cleanup(m.Close)
+	return m
+}
+
+func assertLocked(t *testing.T, m Model, notice string) {
+	t.Helper()
+	if m.active != viewPassword {
+		t.Errorf("active = %d, want viewPassword", m.active)
+	}
+	if m.store != nil || m.identities != nil || m.credentials != nil || m.configs != nil {
+		t.Error("store and collections should be dropped")
+	}
+	if m.ncConfig.Configured() || m.gmConfig.Email != "" || m.twConfig.Configured() {
+		t.Error("cached config should be wiped")
+	}
+	if m.password.firstRun {
+		t.Error("locked prompt should unlock, not create, the store")
+	}
+	if m.password.notice != notice {
+		t.Errorf("notice = %q, want %q", m.password.notice, notice)
+	}
+}
+
+func TestLockKeyFromAnyView(t *testing.T) {
+	for _, view := range []viewID{viewMenu, viewDetail, viewSettings, viewCredentialForm} {
+		m := unlockedModel(t)
+		m.ncConfig = NamecheapSettings{Username: "u", APIKey: "k"}
+		m.twConfig = TwilioSettings{AccountSID: "sid", AuthToken: "tok"}
+		m.active = view
+
+		m = processMsg(t, m, tea.KeyMsg{Type: tea.KeyCtrlL})
+		assertLocked(t, m, "locked")
+	}
+}
+
+func TestLockThenUnlock(t *testing.T) {
+	m := unlockedModel(t)
+	m = saveIdentity(t, m, testIdentity())
+	m = processMsg(t, m, tea.KeyMsg{Type: tea.KeyCtrlL})
+
+	m = processMsg(t, m, passwordSubmitMsg{password: "testpass"})
+	if m.active != viewMenu {
+		t.Fatalf("active = %d, want viewMenu", m.active)
+	}
+	ids, err := m.identities.List()
+	if err != nil || len(ids) != 1 {
+		t.Errorf("identities = %d, %v", len(ids), err)
+	}
+}
+
+func TestIdleTick(t *testing.T) {
+	tests := []struct {
+		name       string
+		lock       LockSettings
+		idle       time.Duration
+		staleGen   bool
+		wantLocked bool
+		wantCmd    bool
+	}{
+		{"idle past default timeout", LockSettings{}, 20 * time.Minute, false, true, false},
+		{"active recently", LockSettings{}, time.Minute, false, false, true},
+		{"configured timeout", config.LockAfter(time.Minute), 2 * time.Minute, false, true, false},
+		{"stale tick", LockSettings{}, 20 * time.Minute, true, false, false},
+		{"auto-lock off", config.LockAfter(0), 24 * time.Hour, false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := unlockedModel(t)
+			m.lockConfig = tt.lock
+			m.lastActivity = time.Now().Add(-tt.idle)
+			m.active = viewList
+
+			gen := m.idleGen
+			if tt.staleGen {
+				gen--
+			}
+			result, cmd := m.Update(idleTickMsg{gen: gen})
+			rm := result.(Model)
+
+			if tt.wantLocked {
+				assertLocked(t, rm, "locked after "+lockTimeoutLabel(tt.lock.Timeout())+" idle")
+				return
+			}
+			if rm.active != viewList || rm.store == nil {
+				t.Errorf("should stay unlocked, active = %d", rm.active)
+			}
+			if (cmd != nil) != tt.wantCmd {
+				t.Errorf("cmd = %v, want rescheduled check: %v", cmd != nil, tt.wantCmd)
+			}
+		})
+	}
+}
+
+func TestKeyPressResetsIdle(t *testing.T) {
+	m := unlockedModel(t)
+	m.lastActivity = time.Now().Add(-time.Hour)
+	m = processMsg(t, m, keyMsg('j'))
+	if time.Since(m.lastActivity) > time.Minute {
+		t.Error("key press should count as activity")
+	}
+}
+
+func TestLockedDropsPendingResults(t *testing.T) {
+	m := unlockedModel(t)
+	m = processMsg(t, m, tea.KeyMsg{Type: tea.KeyCtrlL})
+
+	// results of commands started before locking must not touch the model
+	m = processMsg(t, m, saveIdentityMsg{identity: testIdentity()})
+	m = processMsg(t, m, navigateMsg{view: viewList})
+	if m.active != viewPassword {
+		t.Errorf("active = %d, want viewPassword", m.active)
+	}
+}
+
+func TestLockDeferredWhileRekeying(t *testing.T) {
+	tests := []struct {
+		name   string
+		msg    func(m Model) tea.Msg
+		notice string
+	}{
+		{"manual", func(Model) tea.Msg { return tea.KeyMsg{Type: tea.KeyCtrlL} }, "locked"},
+		{"idle", func(m Model) tea.Msg { return idleTickMsg{gen: m.idleGen} }, "locked after 15m idle"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := unlockedModel(t)
+			m.active = viewSettingsPassword
+
+			result, rekey := m.Update(changePasswordMsg{current: "testpass", next: "newpass"})
+			m = result.(Model)
+			if rekey == nil {
+				t.Fatal("change should produce rekey command")
+			}
+
+			m.lastActivity = time.Now().Add(-time.Hour)
+			m = processMsg(t, m, tt.msg(m))
+			if m.store == nil || m.active != viewSettingsPassword {
+				t.Fatal("lock should wait for the rekey to finish")
+			}
+
+			m = processMsg(t, m, rekey())
+			assertLocked(t, m, tt.notice)
+
+			m = processMsg(t, m, passwordSubmitMsg{password: "newpass"})
+			if m.store == nil {
+				t.Error("vault should unlock with the new password")
+			}
+		})
+	}
+}
+
+func TestLockDeferredWhileBuying(t *testing.T) {
+	m := unlockedModel(t)
+	id := testIdentity()
+	m = saveIdentity(t, m, id)
+
+	m = processMsg(t, m, buyNumberMsg{identity: id, number: "+447700900001"})
+	m = processMsg(t, m, tea.KeyMsg{Type: tea.KeyCtrlL})
+	if m.store == nil {
+		t.Fatal("lock should wait for the purchase to finish")
+	}
+
+	m = processMsg(t, m, numberBoughtMsg{identity: id, number: &twilio.PhoneNumber{SID: "PN1", PhoneNumber: "+447700900001"}})
+	assertLocked(t, m, "locked")
+
+	m = processMsg(t, m, passwordSubmitMsg{password: "testpass"})
+	if phone, err := m.phones.Get(id.ID); err != nil || phone.NumberSID != "PN1" {
+		t.Errorf("bought number should be recorded, got %+v, %v", phone, err)
+	}
+}
+
+func TestLockDeferredWhileBurning(t *testing.T) {
+	m := unlockedModel(t)
+	id := testIdentity()
+	m = saveIdentity(t, m, id)
+
+	result, burnCmd := m.Update(burnIdentityMsg{identity: id})
+	m = result.(Model)
+	if burnCmd == nil {
+		t.Fatal("burn should produce a command")
+	}
+
+	m.lastActivity = time.Now().Add(-time.Hour)
+	m = processMsg(t, m, idleTickMsg{gen: m.idleGen})
+	if m.store == nil {
+		t.Fatal("lock should wait for the burn to finish")
+	}
+
+	res := burnCmd().(burnResultMsg)
+	if res.result.HasErrors() {
+		t.Fatalf("burn failed: %s", res.result.Summary())
+	}
+	m = processMsg(t, m, res)
+	assertLocked(t, m, "locked after 15m idle")
+
+	m = processMsg(t, m, passwordSubmitMsg{password: "testpass"})
+	if _, err := m.identities.Get(id.ID); err == nil {
+		t.Error("burned identity should be gone")
+	}
+}
+
+func TestSetLockTimeoutPersists(t *testing.T) {
+	m := unlockedModel(t)
+	m = processMsg(t, m, navigateMsg{view: viewSettings})
+	m.settings.cursor = int(settingsLock)
+
+	_, cmd := m.Update(enterKey())
+	if cmd == nil {
+		t.Fatal("enter on auto-lock should cycle the timeout")
+	}
+	msg, ok := cmd().(setLockTimeoutMsg)
+	if !ok || msg.timeout != 30*time.Minute {
+		t.Fatalf("msg = %#v, want 30m after the 15m default", msg)
+	}
+
+	gen := m.idleGen
+	m = processMsg(t, m, msg)
+	if m.lockConfig.Timeout() != 30*time.Minute || m.settings.lock.Timeout() != 30*time.Minute {
+		t.Errorf("timeout = %v", m.lockConfig.Timeout())
+	}
+	if m.idleGen == gen {
+		t.Error("changing the timeout should restart the idle timer")
+	}
+	if got := loadConfig[LockSettings](m.configs, config.KeyLock).Timeout(); got != 30*time.Minute {
+		t.Errorf("saved timeout = %v, want 30m", got)
+	}
+}
+
+func TestLockTimeouts(t *testing.T) {
+	tests := []struct {
+		lock  LockSettings
+		want  time.Duration
+		label string
+		next  time.Duration
+	}{
+		{LockSettings{}, config.DefaultIdleTimeout, "15m", 30 * time.Minute},
+		{config.LockAfter(time.Minute), time.Minute, "1m", 5 * time.Minute},
+		{config.LockAfter(time.Hour), time.Hour, "1h", 0},
+		{config.LockAfter(0), 0, "off", time.Minute},
+		{LockSettings{IdleTimeout: "bogus"}, config.DefaultIdleTimeout, "15m", 30 * time.Minute},
+		{LockSettings{IdleTimeout: "90s"}, 90 * time.Second, "1m30s", time.Minute},
+	}
+	for _, tt := range tests {
+		got := tt.lock.Timeout()
+		if got != tt.want {
+			t.Errorf("%+v.Timeout() = %v, want %v", tt.lock, got, tt.want)
+		}
+		if l := lockTimeoutLabel(got); l != tt.label {
+			t.Errorf("label(%v) = %q, want %q", got, l, tt.label)
+		}
+		if n := nextLockTimeout(got); n != tt.next {
+			t.Errorf("next(%v) = %v, want %v", got, n, tt.next)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zarlcorp/core/pkg/zstyle"
+	"github.com/zarlcorp/zburn/internal/identity"
+)
+
+// listedMessage is a fetched message shown in a messageList.
+type listedMessage interface {
+	// topCode returns the most likely verification code, or "" if none.
+	topCode() string
+	// row renders the message as a single list line, without its code.
+	row() string
+}
+
+// messagesResult is implemented by the result messages of each source so
+// the list can accept them without knowing their other fields.
+type messagesResult[T listedMessage] interface {
+	result() ([]T, error)
+}
+
+// messageList lists the messages received by an identity from one source
+// and copies the code of the selected one. The sources differ only in how
+// messages are fetched and rendered.
+type messageList[T listedMessage] struct {
+	identity identity.Identity
+	title    string
+	messages []T
+	cursor   int
+	loading  bool
+	err      string
+	flash    string
+
+	// refresh builds the message requesting a new fetch.
+	refresh func(identity.Identity) tea.Msg
+	// showLatest puts the latest code above the list.
+	showLatest bool
+}
+
+// messageDate formats when a message arrived, or "" if unknown.
+func messageDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format("Jan 02 15:04")
+}
+
+func (m messageList[T]) Init() tea.Cmd {
+	return nil
+}
+
+func (m messageList[T]) Update(msg tea.Msg) (messageList[T], tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+
+	case messagesResult[T]:
+		msgs, err := msg.result()
+		m.loading = false
+		if err != nil {
+			m.err = err.Error()
+			return m, nil
+		}
+		m.err = ""
+		m.messages = msgs
+		if m.cursor >= len(m.messages) {
+			m.cursor = 0
+		}
+		return m, nil
+
+	case flashMsg:
+		m.flash = ""
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m messageList[T]) handleKey(msg tea.KeyMsg) (messageList[T], tea.Cmd) {
+	if key.Matches(msg, zstyle.KeyQuit) {
+		return m, tea.Quit
+	}
+
+	if key.Matches(msg, zstyle.KeyBack) {
+		return m, func() tea.Msg { return navigateMsg{view: viewDetail} }
+	}
+
+	if msg.String() == "r" {
+		if m.loading {
+			return m, nil
+		}
+		m.loading = true
+		id, refresh := m.identity, m.refresh
+		return m, func() tea.Msg { return refresh(id) }
+	}
+
+	if len(m.messages) == 0 {
+		return m, nil
+	}
+
+	if key.Matches(msg, zstyle.KeyUp) {
+		if m.cursor > 0 {
+			m.cursor--
+		}
+		return m, nil
+	}
+
+	if key.Matches(msg, zstyle.KeyDown) {
+		if m.cursor < len(m.messages)-1 {
+			m.cursor++
+		}
+		return m, nil
+	}
+
+	if key.Matches(msg, zstyle.KeyEnter) || msg.String() == "c" {
+		code := m.messages[m.cursor].topCode()
+		if code == "" {
+			m.flash = "no code in message"
+			return m, clearFlashAfter()
+		}
+		if err := copyToClipboard(code); err != nil {
+			m.flash = "copy: " + err.Error()
+			return m, clearFlashAfter()
+		}
+		m.flash = "code copied"
+		return m, clearFlashAfter()
+	}
+
+	return m, nil
+}
+
+// latestCode returns the best code from the most recent message that has one.
+func (m messageList[T]) latestCode() string {
+	for _, msg := range m.messages {
+		if code := msg.topCode(); code != "" {
+			return code
+		}
+	}
+	return ""
+}
+
+func (m messageList[T]) View() string {
+	accentStyle := lipgloss.NewStyle().Foreground(zstyle.ZburnAccent).Bold(true)
+
+	s := "\n  " + zstyle.Subtitle.Render(m.title) + "\n\n"
+
+	switch {
+	case m.loading:
+		s += "  " + zstyle.MutedText.Render("loading...") + "\n"
+		return s
+	case m.err != "":
+		s += "  " + zstyle.StatusErr.Render(m.err) + "\n"
+		return s
+	case len(m.messages) == 0:
+		s += "  " + zstyle.MutedText.Render("no messages") + "\n"
+		s += "\n"
+		s += "\n"
+		return s
+	}
+
+	if code := m.latestCode(); m.showLatest && code != "" {
+		label := zstyle.MutedText.Render(fmt.Sprintf("%-12s", "latest code"))
+		s += "  " + label + " " + accentStyle.Render(code) + "\n\n"
+	}
+
+	for i, msg := range m.messages {
+		line := msg.row()
+		if code := msg.topCode(); code != "" {
+			line += " " + zstyle.Highlight.Render(code)
+		}
+
+		if i == m.cursor {
+			s += "  " + accentStyle.Render("▸") + " " + line + "\n"
+		} else {
+			s += "    " + line + "\n"
+		}
+	}
+
+	s += "\n"
+
+	// always reserve a line for flash to prevent layout shift
+	if m.flash != "" {
+		s += "  " + zstyle.StatusOK.Render(m.flash) + "\n"
+	} else {
+		s += "\n"
+	}
+
+	return s
+}
@@ -26,6 +26,7 @@ type passwordModel struct {
 	focused  pwField
 	firstRun bool
 	errMsg   string
+	notice   string // why the store was locked
 }
 
 // passwordSubmitMsg is sent when the user submits a password.
@@ -167,6 +168,9 @@ func (m passwordModel) View() string {
 		b.WriteString(fmt.Sprintf("  %s\n\n", title))
 		desc := zstyle.MutedText.Render("enter your master password.")
 		b.WriteString(fmt.Sprintf("  %s\n\n", desc))
+		if m.notice != "" {
+			b.WriteString(fmt.Sprintf("  %s\n\n", zstyle.MutedText.Render(m.notice)))
+		}
 	}
 
 	// password field
@@ -0,0 +1,278 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zarlcorp/core/pkg/zstyle"
+	"github.com/zarlcorp/zburn/internal/identity"
+	"github.com/zarlcorp/zburn/internal/twilio"
+)
+
+// provisionPerCountry caps how many numbers are offered per country.
+const provisionPerCountry = 10
+
+type provisionPhase int
+
+const (
+	provisionSearching provisionPhase = iota
+	provisionPick
+	provisionConfirm
+	provisionBuying
+)
+
+// provisionPhoneMsg requests the phone provisioning flow for an identity.
+type provisionPhoneMsg struct {
+	identity identity.Identity
+}
+
+// provisionSearchMsg carries numbers available for purchase.
+type provisionSearchMsg struct {
+	numbers []availableNumber
+	err     error
+}
+
+// buyNumberMsg requests purchase of a number for an identity.
+type buyNumberMsg struct {
+	identity identity.Identity
+	number   string
+}
+
+// numberBoughtMsg carries the outcome of a purchase.
+type numberBoughtMsg struct {
+	identity identity.Identity
+	number   *twilio.PhoneNumber
+	err      error
+}
+
+// availableNumber is a purchasable number tagged with its country.
+type availableNumber struct {
+	country string
+	number  twilio.AvailableNumber
+}
+
+// numberProvisioner abstracts the Twilio search and purchase calls for testing.
+type numberProvisioner interface {
+	SearchNumbers(ctx context.Context, country string) ([]twilio.AvailableNumber, error)
+	BuyNumber(ctx context.Context, phoneNumber string) (*twilio.PhoneNumber, error)
+}
+
+// provisionModel lets the user pick and buy a number for an identity.
+type provisionModel struct {
+	identity identity.Identity
+	numbers  []availableNumber
+	cursor   int
+	phase    provisionPhase
+	err      string
+}
+
+func newProvisionModel(id identity.Identity) provisionModel {
+	return provisionModel{identity: id, phase: provisionSearching}
+}
+
+func (m provisionModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m provisionModel) Update(msg tea.Msg) (provisionModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+
+	case provisionSearchMsg:
+		m.phase = provisionPick
+		if msg.err != nil {
+			m.err = msg.err.Error()
+			return m, nil
+		}
+		m.numbers = msg.numbers
+		m.cursor = 0
+		return m, nil
+
+	case numberBoughtMsg:
+		// success is handled by the root model; only failures land here
+		m.phase = provisionPick
+		if msg.err != nil {
+			m.err = "buy: " + msg.err.Error()
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m provisionModel) handleKey(msg tea.KeyMsg) (provisionModel, tea.Cmd) {
+	if key.Matches(msg, zstyle.KeyQuit) {
+		return m, tea.Quit
+	}
+
+	switch m.phase {
+	case provisionPick:
+		return m.handlePickKey(msg)
+	case provisionConfirm:
+		return m.handleConfirmKey(msg)
+	case provisionBuying:
+		// stay put until the purchase lands so its result is recorded here
+		return m, nil
+	}
+
+	if key.Matches(msg, zstyle.KeyBack) {
+		return m, func() tea.Msg { return navigateMsg{view: viewDetail} }
+	}
+	return m, nil
+}
+
+func (m provisionModel) handlePickKey(msg tea.KeyMsg) (provisionModel, tea.Cmd) {
+	if key.Matches(msg, zstyle.KeyBack) {
+		return m, func() tea.Msg { return navigateMsg{view: viewDetail} }
+	}
+
+	if len(m.numbers) == 0 {
+		return m, nil
+	}
+
+	if key.Matches(msg, zstyle.KeyUp) {
+		if m.cursor > 0 {
+			m.cursor--
+		}
+		return m, nil
+	}
+
+	if key.Matches(msg, zstyle.KeyDown) {
+		if m.cursor < len(m.numbers)-1 {
+			m.cursor++
+		}
+		return m, nil
+	}
+
+	if key.Matches(msg, zstyle.KeyEnter) {
+		m.err = ""
+		m.phase = provisionConfirm
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m provisionModel) handleConfirmKey(msg tea.KeyMsg) (provisionModel, tea.Cmd) {
+	switch msg.String() {
+	case "y":
+		m.phase = provisionBuying
+		id := m.identity
+		number := m.numbers[m.cursor].number.PhoneNumber
+		return m, func() tea.Msg { return buyNumberMsg{identity: id, number: number} }
+	default:
+		m.phase = provisionPick
+		return m, nil
+	}
+}
+
+func (m provisionModel) View() string {
+	accentStyle := lipgloss.NewStyle().Foreground(zstyle.ZburnAccent).Bold(true)
+
+	name := m.identity.FirstName + " " + m.identity.LastName
+	s := "\n  " + zstyle.Subtitle.Render("phone for "+name) + "\n\n"
+
+	switch m.phase {
+	case provisionSearching:
+		s += "  " + zstyle.MutedText.Render("searching for numbers...") + "\n"
+		return s
+	case provisionBuying:
+		number := m.numbers[m.cursor].number.PhoneNumber
+		s += "  " + zstyle.MutedText.Render("buying "+number+"...") + "\n"
+		return s
+	}
+
+	if len(m.numbers) == 0 {
+		if m.err != "" {
+			s += "  " + zstyle.StatusErr.Render(m.err) + "\n"
+		} else {
+			s += "  " + zstyle.MutedText.Render("no sms-capable numbers available") + "\n"
+		}
+		return s
+	}
+
+	for i, n := range m.numbers {
+		line := fmt.Sprintf("%-4s %-18s %s", n.country, n.number.PhoneNumber, n.number.FriendlyName)
+		if i == m.cursor {
+			s += "  " + accentStyle.Render("▸") + " " + line + "\n"
+		} else {
+			s += "    " + line + "\n"
+		}
+	}
+
+	s += "\n"
+
+	switch {
+	case m.phase == provisionConfirm:
+		number := m.numbers[m.cursor].number.PhoneNumber
+		s += "  " + zstyle.StatusWarn.Render(fmt.Sprintf("buy %s? this is billed to your twilio account. (y/n)", number)) + "\n"
+	case m.err != "":
+		s += "  " + zstyle.StatusErr.Render(m.err) + "\n"
+	default:
+		s += "\n"
+	}
+
+	return s
+}
+
+// searchNumbersCmd returns a tea.Cmd that searches all preferred countries.
+func searchNumbersCmd(cfg twilio.Config, countries []string) tea.Cmd {
+	return func() tea.Msg {
+		numbers, err := searchNumbers(context.Background(), twilio.NewClient(cfg), countries)
+		return provisionSearchMsg{numbers: numbers, err: err}
+	}
+}
+
+// searchNumbers queries each country and keeps SMS-capable numbers. A
+// failing country is skipped unless every country fails.
+func searchNumbers(ctx context.Context, p numberProvisioner, countries []string) ([]availableNumber, error) {
+	if len(countries) == 0 {
+		return nil, fmt.Errorf("no preferred countries set")
+	}
+
+	sorted := append([]string(nil), countries...)
+	sort.Strings(sorted)
+
+	var result []availableNumber
+	var errs []error
+	for _, country := range sorted {
+		found, err := p.SearchNumbers(ctx, country)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", country, err))
+			continue
+		}
+
+		n := 0
+		for _, an := range found {
+			if !an.Capabilities.SMS {
+				continue
+			}
+			result = append(result, availableNumber{country: country, number: an})
+			n++
+			if n == provisionPerCountry {
+				break
+			}
+		}
+	}
+
+	if len(errs) == len(sorted) {
+		return nil, errors.Join(errs...)
+	}
+
+	return result, nil
+}
+
+// buyNumberCmd returns a tea.Cmd that purchases a number for an identity.
+func buyNumberCmd(cfg twilio.Config, id identity.Identity, number string) tea.Cmd {
+	return func() tea.Msg {
+		c := twilio.NewClient(cfg)
+		pn, err := c.BuyNumber(context.Background(), number)
+		return numberBoughtMsg{identity: id, number: pn, err: err}
+	}
+}
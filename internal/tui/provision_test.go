@@ -0,0 +1,285 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/zarlcorp/zburn/internal/burn"
+	"github.com/zarlcorp/zburn/internal/twilio"
+)
+
+// fakeProvisioner serves canned numbers per country and records purchases.
+type fakeProvisioner struct {
+	numbers map[string][]twilio.AvailableNumber
+	errs    map[string]error
+	bought  string
+}
+
+func (f *fakeProvisioner) SearchNumbers(_ context.Context, country string) ([]twilio.AvailableNumber, error) {
+	if err := f.errs[country]; err != nil {
+		return nil, err
+	}
+	return f.numbers[country], nil
+}
+
+func (f *fakeProvisioner) BuyNumber(_ context.Context, phoneNumber string) (*twilio.PhoneNumber, error) {
+	f.bought = phoneNumber
+	return &twilio.PhoneNumber{SID: "PN1", PhoneNumber: phoneNumber}, nil
+}
+
+func smsNumber(number string) twilio.AvailableNumber {
+	n := twilio.AvailableNumber{PhoneNumber: number, FriendlyName: number}
+	n.Capabilities.SMS = true
+	return n
+}
+
+func testAvailableNumbers() []availableNumber {
+	return []availableNumber{
+		{country: "GB", number: smsNumber("+447700900001")},
+		{country: "US", number: smsNumber("+15550100001")},
+	}
+}
+
+func TestSearchNumbers(t *testing.T) {
+	voiceOnly := twilio.AvailableNumber{PhoneNumber: "+15550100009"}
+	p := &fakeProvisioner{numbers: map[string][]twilio.AvailableNumber{
+		"US": {smsNumber("+15550100001"), voiceOnly},
+		"GB": {smsNumber("+447700900001")},
+	}}
+
+	got, err := searchNumbers(context.Background(), p, []string{"US", "GB"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("got %d numbers, want 2", len(got))
+	}
+	// countries are searched in sorted order
+	if got[0].country != "GB" || got[1].country != "US" {
+		t.Errorf("countries = %q, %q, want GB, US", got[0].country, got[1].country)
+	}
+	if got[1].number.PhoneNumber != "+15550100001" {
+		t.Errorf("number = %q, want SMS-capable number", got[1].number.PhoneNumber)
+	}
+}
+
+func TestSearchNumbersPartialFailure(t *testing.T) {
+	p := &fakeProvisioner{
+		numbers: map[string][]twilio.AvailableNumber{"US": {smsNumber("+15550100001")}},
+		errs:    map[string]error{"GB": fmt.Errorf("status 404")},
+	}
+
+	got, err := searchNumbers(context.Background(), p, []string{"US", "GB"})
+	if err != nil {
+		t.Fatalf("one failing country should not fail the search: %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("got %d numbers, want 1", len(got))
+	}
+}
+
+func TestSearchNumbersAllFail(t *testing.T) {
+	p := &fakeProvisioner{errs: map[string]error{"US": fmt.Errorf("status 401")}}
+
+	if _, err := searchNumbers(context.Background(), p, []string{"US"}); err == nil {
+		t.Fatal("expected error when every country fails")
+	}
+}
+
+func TestSearchNumbersNoCountries(t *testing.T) {
+	if _, err := searchNumbers(context.Background(), &fakeProvisioner{}, nil); err == nil {
+		t.Fatal("expected error without countries")
+	}
+}
+
+func TestProvisionViewSearching(t *testing.T) {
+	m := newProvisionModel(testIdentity())
+	if !strings.Contains(m.View(), "searching") {
+		t.Error("new provision view should show searching")
+	}
+}
+
+func TestProvisionConfirmAndBuy(t *testing.T) {
+	m := newProvisionModel(testIdentity())
+	m, _ = m.Update(provisionSearchMsg{numbers: testAvailableNumbers()})
+
+	m, _ = m.Update(keyMsg('j'))
+	m, _ = m.Update(enterKey())
+	if m.phase != provisionConfirm {
+		t.Fatalf("phase = %d, want provisionConfirm", m.phase)
+	}
+	if !strings.Contains(m.View(), "billed") {
+		t.Error("confirm should warn about billing")
+	}
+
+	m, cmd := m.Update(keyMsg('y'))
+	if cmd == nil {
+		t.Fatal("y should produce command")
+	}
+	if m.phase != provisionBuying {
+		t.Errorf("phase = %d, want provisionBuying", m.phase)
+	}
+	buy, ok := cmd().(buyNumberMsg)
+	if !ok {
+		t.Fatal("should emit buyNumberMsg")
+	}
+	if buy.number != "+15550100001" {
+		t.Errorf("number = %q, want %q", buy.number, "+15550100001")
+	}
+}
+
+func TestProvisionConfirmCancel(t *testing.T) {
+	m := newProvisionModel(testIdentity())
+	m, _ = m.Update(provisionSearchMsg{numbers: testAvailableNumbers()})
+	m, _ = m.Update(enterKey())
+
+	m, cmd := m.Update(keyMsg('n'))
+	if cmd != nil {
+		t.Error("cancel should not produce command")
+	}
+	if m.phase != provisionPick {
+		t.Errorf("phase = %d, want provisionPick", m.phase)
+	}
+}
+
+func TestProvisionBuyError(t *testing.T) {
+	m := newProvisionModel(testIdentity())
+	m, _ = m.Update(provisionSearchMsg{numbers: testAvailableNumbers()})
+	m.phase = provisionBuying
+
+	m, _ = m.Update(numberBoughtMsg{err: fmt.Errorf("status 400")})
+	if m.phase != provisionPick {
+		t.Errorf("phase = %d, want provisionPick", m.phase)
+	}
+	if !strings.Contains(m.View(), "status 400") {
+		t.Error("view should show buy error")
+	}
+}
+
+func TestProvisionEscIgnoredWhileBuying(t *testing.T) {
+	m := newProvisionModel(testIdentity())
+	m, _ = m.Update(provisionSearchMsg{numbers: testAvailableNumbers()})
+	m.phase = provisionBuying
+
+	m, cmd := m.Update(escKey())
+	if cmd != nil {
+		t.Error("esc should not leave while a purchase is in flight")
+	}
+	if m.phase != provisionBuying {
+		t.Errorf("phase = %d, want provisionBuying", m.phase)
+	}
+}
+
+func TestDetailPNavigatesToProvision(t *testing.T) {
+	m := newDetailModel(testIdentity())
+	_, cmd := m.Update(keyMsg('p'))
+	if cmd == nil {
+		t.Fatal("p should produce command")
+	}
+	if _, ok := cmd().(provisionPhoneMsg); !ok {
+		t.Error("should emit provisionPhoneMsg")
+	}
+}
+
+func TestRootProvisionRequiresTwilio(t *testing.T) {
+	m := New("1.0", t.TempDir(), nil, false)
+	m.active = viewDetail
+	m.detail = newDetailModel(testIdentity())
+
+	result, _ := m.Update(provisionPhoneMsg{identity: testIdentity()})
+	rm := result.(Model)
+
+	if rm.active != viewDetail {
+		t.Errorf("active = %d, want viewDetail", rm.active)
+	}
+	if rm.detail.flash != "twilio not configured" {
+		t.Errorf("flash = %q, want %q", rm.detail.flash, "twilio not configured")
+	}
+}
+
+func TestRootNumberBoughtPersists(t *testing.T) {
+	m := setupModel(t)
+	id := testIdentity()
+	m = saveIdentity(t, m, id)
+	m.active = viewProvision
+	m.provision = newProvisionModel(id)
+
+	result, _ := m.Update(numberBoughtMsg{
+		identity: id,
+		number:   &twilio.PhoneNumber{SID: "PN1", PhoneNumber: "+447700900001"},
+	})
+	rm := result.(Model)
+
+	if rm.active != viewDetail {
+		t.Errorf("active = %d, want viewDetail", rm.active)
+	}
+	if rm.detail.identity.Phone != "+447700900001" {
+		t.Errorf("detail phone = %q, want %q", rm.detail.identity.Phone, "+447700900001")
+	}
+
+	saved, err := rm.identities.Get(id.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Phone != "+447700900001" {
+		t.Errorf("saved phone = %q, want %q", saved.Phone, "+447700900001")
+	}
+
+	phone := rm.phoneFor(id.ID)
+	if phone == nil || phone.NumberSID != "PN1" {
+		t.Fatalf("phoneFor = %+v, want SID PN1", phone)
+	}
+
+	// a second provision attempt is refused
+	rm.twConfig = TwilioSettings{AccountSID: "AC123", AuthToken: "tok"}
+	result, _ = rm.Update(provisionPhoneMsg{identity: saved})
+	rm = result.(Model)
+	if rm.active != viewDetail {
+		t.Errorf("active = %d, want viewDetail", rm.active)
+	}
+	if !strings.Contains(rm.detail.flash, "already provisioned") {
+		t.Errorf("flash = %q, want already provisioned", rm.detail.flash)
+	}
+}
+
+func TestRootNumberBoughtElsewhereStays(t *testing.T) {
+	m := setupModel(t)
+	id := testIdentity()
+	m = saveIdentity(t, m, id)
+	m.active = viewList
+
+	result, _ := m.Update(numberBoughtMsg{
+		identity: id,
+		number:   &twilio.PhoneNumber{SID: "PN1", PhoneNumber: "+447700900001"},
+	})
+	rm := result.(Model)
+
+	if rm.active != viewList {
+		t.Errorf("active = %d, want viewList", rm.active)
+	}
+	if phone := rm.phoneFor(id.ID); phone == nil || phone.NumberSID != "PN1" {
+		t.Errorf("phoneFor = %+v, want SID PN1", phone)
+	}
+}
+
+func TestPhoneForPrefersStore(t *testing.T) {
+	m := setupModel(t)
+	if err := m.phones.Put("abc12345", burn.PhoneConfig{NumberSID: "PN1", PhoneNumber: "+447700900001"}); err != nil {
+		t.Fatal(err)
+	}
+	m.SetExternalServices(ExternalServices{
+		PhoneForIdentity: func(string) *burn.PhoneConfig {
+			return &burn.PhoneConfig{NumberSID: "PN2"}
+		},
+	})
+
+	if got := m.phoneFor("abc12345"); got == nil || got.NumberSID != "PN1" {
+		t.Errorf("phoneFor = %+v, want stored PN1", got)
+	}
+	if got := m.phoneFor("other"); got == nil || got.NumberSID != "PN2" {
+		t.Errorf("phoneFor = %+v, want external PN2", got)
+	}
+}
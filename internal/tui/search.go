@@ -0,0 +1,177 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zarlcorp/core/pkg/zstyle"
+)
+
+// searchModel is the incremental search box shared by the list views.
+// While active it takes all typing; the list filters on every change.
+type searchModel struct {
+	input  textinput.Model
+	active bool
+}
+
+func newSearchModel() searchModel {
+	ti := textinput.New()
+	ti.Prompt = "/ "
+	ti.CharLimit = 64
+	ti.Width = 40
+	return searchModel{input: ti}
+}
+
+// start focuses the search box.
+func (s searchModel) start() (searchModel, tea.Cmd) {
+	s.active = true
+	return s, s.input.Focus()
+}
+
+// stop leaves the search box, keeping the query.
+func (s searchModel) stop() searchModel {
+	s.active = false
+	s.input.Blur()
+	return s
+}
+
+// clear empties and leaves the search box.
+func (s searchModel) clear() searchModel {
+	s.input.SetValue("")
+	return s.stop()
+}
+
+func (s searchModel) query() string {
+	return strings.TrimSpace(s.input.Value())
+}
+
+func (s searchModel) update(msg tea.Msg) (searchModel, tea.Cmd) {
+	var cmd tea.Cmd
+	s.input, cmd = s.input.Update(msg)
+	return s, cmd
+}
+
+// View renders the search line, or nothing when there is no search.
+func (s searchModel) View(matches, total int) string {
+	if !s.active && s.query() == "" {
+		return ""
+	}
+	count := zstyle.MutedText.Render(fmt.Sprintf("  %d/%d", matches, total))
+	if s.active {
+		return "  " + s.input.View() + count + "\n\n"
+	}
+	accent := lipgloss.NewStyle().Foreground(zstyle.ZburnAccent)
+	return "  " + accent.Render("/ "+s.query()) + count + "\n\n"
+}
+
+// searchMatch is an item that matched a query.
+type searchMatch struct {
+	index    int // position in the searched slice
+	score    int
+	site     int  // best-matching related site, or -1
+	siteOnly bool // every term matched a related site and none the item itself
+}
+
+// fuzzySearch ranks the items matching every space-separated term of query,
+// best first. fields returns the searchable text of item i: its own fields,
+// and the sites of related items such as credentials, which are searched too
+// but reported separately. An empty query matches everything in order.
+func fuzzySearch(query string, n int, fields func(i int) (own, sites []string)) []searchMatch {
+	terms := strings.Fields(strings.ToLower(query))
+
+	var out []searchMatch
+	for i := range n {
+		m := searchMatch{index: i, site: -1, siteOnly: len(terms) > 0}
+		ok := true
+		own, sites := fields(i)
+		siteBest := 0
+		for _, term := range terms {
+			ownBest := bestField(term, own)
+			site := bestField(term, sites)
+			if ownBest.index < 0 && site.index < 0 {
+				ok = false
+				break
+			}
+			m.score += max(ownBest.score, site.score)
+			if ownBest.index >= 0 {
+				m.siteOnly = false
+			}
+			if site.index >= 0 && site.score > siteBest {
+				siteBest, m.site = site.score, site.index
+			}
+		}
+		if ok {
+			out = append(out, m)
+		}
+	}
+
+	sort.SliceStable(out, func(a, b int) bool { return out[a].score > out[b].score })
+	return out
+}
+
+// fieldScore is the best match of a term among some fields.
+type fieldScore struct {
+	index int // -1 if no field matched
+	score int
+}
+
+// bestField returns the field term matches best.
+func bestField(term string, fields []string) fieldScore {
+	best := fieldScore{index: -1}
+	for i, f := range fields {
+		if sc, hit := fuzzyScore(term, f); hit && sc > best.score {
+			best = fieldScore{index: i, score: sc}
+		}
+	}
+	return best
+}
+
+// fuzzyScore reports whether the letters of term appear in order in text,
+// ignoring case, and scores the match: consecutive letters and letters at
+// the start of a word score higher, so "gh" ranks "GitHub" above "gosh".
+func fuzzyScore(term, text string) (int, bool) {
+	if term == "" {
+		return 1, true
+	}
+	orig := []rune(text)
+	t := []rune(strings.ToLower(text))
+	q := []rune(term)
+
+	score, qi, prev := 0, 0, -2
+	for i := 0; i < len(t) && qi < len(q); i++ {
+		if t[i] != q[qi] {
+			continue
+		}
+		score++
+		if i == prev+1 {
+			score += 3
+		}
+		if wordStart(orig, i) {
+			score += 2
+		}
+		prev = i
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	return score, true
+}
+
+// wordStart reports whether text[i] begins a word: it follows a separator
+// or is an upper case letter after a lower case one, as in "GitHub".
+func wordStart(text []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev := text[i-1]
+	if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+		return true
+	}
+	return unicode.IsUpper(text[i]) && unicode.IsLower(prev)
+}
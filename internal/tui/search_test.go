@@ -0,0 +1,272 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zarlcorp/zburn/internal/credential"
+	"github.com/zarlcorp/zburn/internal/identity"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		term, text string
+		match      bool
+	}{
+		{"", "anything", true},
+		{"gh", "GitHub", true},
+		{"ghb", "github", true},
+		{"hg", "github", false},
+		{"jane", "Jane Doe", true},
+		{"xyz", "Jane Doe", false},
+	}
+	for _, tt := range tests {
+		if _, ok := fuzzyScore(tt.term, tt.text); ok != tt.match {
+			t.Errorf("fuzzyScore(%q, %q) match = %v, want %v", tt.term, tt.text, ok, tt.match)
+		}
+	}
+
+	prefix, _ := fuzzyScore("gh", "GitHub")
+	scattered, _ := fuzzyScore("gh", "gosh")
+	if prefix <= scattered {
+		t.Errorf("GitHub scored %d, gosh scored %d; want GitHub higher", prefix, scattered)
+	}
+}
+
+func TestFuzzySearch(t *testing.T) {
+	items := [][]string{
+		{"Gosh Corp", "gosh@example.com"},
+		{"GitHub", "jane@example.com"},
+		{"Netflix", "bob@example.com"},
+	}
+	fields := func(i int) ([]string, []string) { return items[i], nil }
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"", []int{0, 1, 2}},
+		{"gh", []int{1, 0}},
+		{"GH", []int{1, 0}},
+		{"gh jane", []int{1}},
+		{"bob net", []int{2}},
+		{"zzz", nil},
+	}
+	for _, tt := range tests {
+		var got []int
+		for _, m := range fuzzySearch(tt.query, len(items), fields) {
+			got = append(got, m.index)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.query, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%q: got %v, want %v", tt.query, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func typeSearch(m listModel, query string) listModel {
+	m, _ = m.Update(keyMsg('/'))
+	for _, r := range query {
+		m, _ = m.Update(keyMsg(r))
+	}
+	return m
+}
+
+func searchIdentities() []identity.Identity {
+	return []identity.Identity{
+		testIdentity(),
+		{ID: "second", FirstName: "Bob", LastName: "Smith", Email: "bob@zburn.id", Tags: []string{"shopping"}, CreatedAt: time.Now()},
+	}
+}
+
+func TestListSearchFilters(t *testing.T) {
+	m := typeSearch(newListModel(searchIdentities()), "bob")
+
+	if len(m.matches) != 1 || m.identities[m.matches[0].index].ID != "second" {
+		t.Fatalf("matches = %v, want only second", m.matches)
+	}
+	view := m.View()
+	if strings.Contains(view, "Jane Doe") {
+		t.Error("filtered view should hide non-matching identities")
+	}
+	if !strings.Contains(view, "1/2") {
+		t.Error("view should show match count")
+	}
+}
+
+func TestListSearchByTag(t *testing.T) {
+	m := typeSearch(newListModel(searchIdentities()), "shop")
+	if len(m.matches) != 1 || m.identities[m.matches[0].index].ID != "second" {
+		t.Fatalf("matches = %v, want only second", m.matches)
+	}
+}
+
+func TestListSearchTypesQ(t *testing.T) {
+	m := typeSearch(newListModel(searchIdentities()), "q")
+	if m.search.query() != "q" {
+		t.Errorf("query = %q, want q", m.search.query())
+	}
+}
+
+func TestListSearchEnterOpens(t *testing.T) {
+	m := typeSearch(newListModel(searchIdentities()), "smith")
+	m, cmd := m.Update(enterKey())
+	if cmd == nil {
+		t.Fatal("enter should open the match")
+	}
+	msg, ok := cmd().(viewIdentityMsg)
+	if !ok || msg.identity.ID != "second" {
+		t.Errorf("got %#v, want viewIdentityMsg for second", msg)
+	}
+	if m.search.active {
+		t.Error("search should stop after enter")
+	}
+}
+
+func TestListSearchEscClears(t *testing.T) {
+	m := typeSearch(newListModel(searchIdentities()), "bob")
+
+	m, cmd := m.Update(escKey())
+	if cmd != nil {
+		t.Error("esc while searching should not navigate")
+	}
+	if m.search.query() != "" || len(m.matches) != 2 {
+		t.Errorf("query = %q, matches = %d; want cleared", m.search.query(), len(m.matches))
+	}
+}
+
+func TestListEscClearsKeptFilter(t *testing.T) {
+	m := typeSearch(newListModel(searchIdentities()), "bob")
+	m.search = m.search.stop()
+
+	m, cmd := m.Update(escKey())
+	if cmd != nil {
+		t.Error("esc with a filter should clear it, not navigate")
+	}
+	if len(m.matches) != 2 {
+		t.Errorf("matches = %d, want 2", len(m.matches))
+	}
+
+	_, cmd = m.Update(escKey())
+	if cmd == nil {
+		t.Fatal("second esc should navigate back")
+	}
+	if msg, ok := cmd().(navigateMsg); !ok || msg.view != viewMenu {
+		t.Errorf("got %#v, want navigateMsg to menu", msg)
+	}
+}
+
+func TestListSearchCredentialSite(t *testing.T) {
+	m := newListModel(searchIdentities()).withCredSites(map[string][]string{
+		"abc12345": {"GitHub", "https://github.com"},
+	})
+	m = typeSearch(m, "github")
+
+	if len(m.matches) != 1 || m.identities[m.matches[0].index].ID != "abc12345" {
+		t.Fatalf("matches = %v, want only abc12345", m.matches)
+	}
+	if !strings.Contains(m.View(), "via ") {
+		t.Error("view should name the matching credential")
+	}
+}
+
+func TestFuzzySearchSiteOnly(t *testing.T) {
+	own := []string{"Jane Doe", "jane@zburn.id"}
+	sites := []string{"GitHub", "Netflix"}
+	fields := func(int) ([]string, []string) { return own, sites }
+
+	tests := []struct {
+		query    string
+		siteOnly bool
+		site     int
+	}{
+		{"", false, -1},
+		{"jane", false, -1},
+		{"netflix", true, 1},
+		{"jane github", false, 0},
+		{"github netflix", true, 1},
+	}
+	for _, tt := range tests {
+		ms := fuzzySearch(tt.query, 1, fields)
+		if len(ms) != 1 {
+			t.Fatalf("%q: %d matches, want 1", tt.query, len(ms))
+		}
+		if ms[0].siteOnly != tt.siteOnly || ms[0].site != tt.site {
+			t.Errorf("%q: siteOnly = %v, site = %d; want %v, %d", tt.query, ms[0].siteOnly, ms[0].site, tt.siteOnly, tt.site)
+		}
+	}
+}
+
+func TestListSearchNameAndSiteNoHint(t *testing.T) {
+	m := newListModel(searchIdentities()).withCredSites(map[string][]string{
+		"abc12345": {"GitHub"},
+	})
+	m = typeSearch(m, "jane github")
+
+	if len(m.matches) != 1 {
+		t.Fatalf("matches = %v, want one", m.matches)
+	}
+	if strings.Contains(m.View(), "via ") {
+		t.Error("a match on the identity itself should not name the credential")
+	}
+}
+
+func TestListSearchNoMatches(t *testing.T) {
+	m := typeSearch(newListModel(searchIdentities()), "zzz")
+	if !strings.Contains(m.View(), "no matches") {
+		t.Error("view should say no matches")
+	}
+
+	if _, cmd := m.Update(enterKey()); cmd != nil {
+		t.Error("enter with no matches should be a no-op")
+	}
+}
+
+func TestCredentialListSearch(t *testing.T) {
+	creds := []credential.Credential{testCredential(), testCredentialNoTOTP()}
+	m := newCredentialListModel(testIdentity(), creds)
+
+	m, _ = m.Update(keyMsg('/'))
+	for _, r := range "netf" {
+		m, _ = m.Update(keyMsg(r))
+	}
+	if len(m.matches) != 1 {
+		t.Fatalf("matches = %d, want 1", len(m.matches))
+	}
+
+	_, cmd := m.Update(enterKey())
+	if cmd == nil {
+		t.Fatal("enter should view the match")
+	}
+	msg, ok := cmd().(viewCredentialMsg)
+	if !ok || msg.credential.Label != "Netflix" {
+		t.Errorf("got %#v, want viewCredentialMsg for Netflix", msg)
+	}
+}
+
+func TestCredentialListSearchDeletesMatch(t *testing.T) {
+	creds := []credential.Credential{testCredential(), testCredentialNoTOTP()}
+	m := newCredentialListModel(testIdentity(), creds)
+
+	m, _ = m.Update(keyMsg('/'))
+	for _, r := range "netflix" {
+		m, _ = m.Update(keyMsg(r))
+	}
+	m.search = m.search.stop()
+
+	m, _ = m.Update(keyMsg('d'))
+	_, cmd := m.Update(keyMsg('y'))
+	if cmd == nil {
+		t.Fatal("y should delete")
+	}
+	if msg := cmd().(deleteCredentialMsg); msg.id != "cred-002" {
+		t.Errorf("deleted %q, want cred-002", msg.id)
+	}
+}
@@ -13,6 +13,8 @@ const (
 	settingsGmail
 	settingsTwilio
 	settingsForwarding
+	settingsPassword
+	settingsLock
 	settingsBack
 )
 
@@ -21,6 +23,8 @@ var settingsItems = []string{
 	"gmail",
 	"twilio",
 	"forwarding",
+	"master password",
+	"auto-lock",
 	"back",
 }
 
@@ -30,6 +34,8 @@ type settingsModel struct {
 	namecheap NamecheapSettings
 	gmail     GmailSettings
 	twilio    TwilioSettings
+	lock      LockSettings
+	flash     string
 }
 
 func newSettingsModel(nc NamecheapSettings, gm GmailSettings, tw TwilioSettings) settingsModel {
@@ -40,6 +46,12 @@ func newSettingsModel(nc NamecheapSettings, gm GmailSettings, tw TwilioSettings)
 	}
 }
 
+// withLock sets the auto-lock setting shown and cycled by the menu.
+func (m settingsModel) withLock(l LockSettings) settingsModel {
+	m.lock = l
+	return m
+}
+
 func (m settingsModel) Init() tea.Cmd {
 	return nil
 }
@@ -72,6 +84,10 @@ func (m settingsModel) Update(msg tea.Msg) (settingsModel, tea.Cmd) {
 		if key.Matches(msg, zstyle.KeyEnter) {
 			return m, m.selectItem()
 		}
+
+	case flashMsg:
+		m.flash = ""
+		return m, nil
 	}
 
 	return m, nil
@@ -87,6 +103,11 @@ func (m settingsModel) selectItem() tea.Cmd {
 		return func() tea.Msg { return navigateMsg{view: viewSettingsTwilio} }
 	case settingsForwarding:
 		return func() tea.Msg { return navigateMsg{view: viewForwarding} }
+	case settingsPassword:
+		return func() tea.Msg { return navigateMsg{view: viewSettingsPassword} }
+	case settingsLock:
+		next := nextLockTimeout(m.lock.Timeout())
+		return func() tea.Msg { return setLockTimeoutMsg{timeout: next} }
 	case settingsBack:
 		return func() tea.Msg { return navigateMsg{view: viewMenu} }
 	}
@@ -119,7 +140,9 @@ func (m settingsModel) View() string {
 
 		// build count/status string for service items
 		var countStr string
-		if choice != settingsForwarding && choice != settingsBack {
+		if choice == settingsLock {
+			countStr = zstyle.MutedText.Render(lockTimeoutLabel(m.lock.Timeout()))
+		} else if choice != settingsForwarding && choice != settingsPassword && choice != settingsBack {
 			status := m.statusFor(choice)
 			statusStyle := zstyle.StatusErr
 			if status == "configured" {
@@ -139,5 +162,9 @@ func (m settingsModel) View() string {
 		s += line + "\n"
 	}
 
+	if m.flash != "" {
+		s += "\n  " + zstyle.StatusErr.Render(m.flash) + "\n"
+	}
+
 	return s
 }
@@ -7,6 +7,8 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/zarlcorp/core/pkg/zstyle"
+	"github.com/zarlcorp/zburn/internal/config"
+	"github.com/zarlcorp/zburn/internal/forwarding"
 	"github.com/zarlcorp/zburn/internal/namecheap"
 )
 
@@ -23,6 +25,14 @@ type forwardingStatusMsg struct {
 	statuses []domainForwardingStatus
 }
 
+// setForwardingModeMsg requests switching between catch-all and per-mailbox forwarding.
+type setForwardingModeMsg struct {
+	mode string
+}
+
+// reconcileForwardingMsg requests a per-mailbox forwarding reconciliation.
+type reconcileForwardingMsg struct{}
+
 // forwardingGetter abstracts the GetForwarding call for testing.
 type forwardingGetter interface {
 	GetForwarding(ctx context.Context, domain string) ([]namecheap.ForwardingRule, error)
@@ -30,13 +40,15 @@ type forwardingGetter interface {
 
 // forwardingModel displays per-domain forwarding status.
 type forwardingModel struct {
-	statuses []domainForwardingStatus
-	loading  bool
-	warning  string // auth warning message
+	statuses   []domainForwardingStatus
+	loading    bool
+	warning    string // auth warning message
+	perMailbox bool
+	flash      string
 }
 
 func newForwardingModel(nc NamecheapSettings, gm GmailSettings) forwardingModel {
-	m := forwardingModel{}
+	m := forwardingModel{perMailbox: nc.PerMailbox()}
 
 	switch {
 	case !nc.Configured() && !gm.Configured():
@@ -65,10 +77,35 @@ func (m forwardingModel) Update(msg tea.Msg) (forwardingModel, tea.Cmd) {
 			return m, func() tea.Msg { return navigateMsg{view: viewSettings} }
 		}
 
+		if m.warning != "" || m.loading {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "m":
+			mode := config.ForwardMailbox
+			if m.perMailbox {
+				mode = config.ForwardCatchAll
+			}
+			m.loading = true
+			return m, func() tea.Msg { return setForwardingModeMsg{mode: mode} }
+		case "r":
+			if !m.perMailbox {
+				m.flash = "reconcile only applies to per-mailbox mode"
+				return m, clearFlashAfter()
+			}
+			m.loading = true
+			return m, func() tea.Msg { return reconcileForwardingMsg{} }
+		}
+
 	case forwardingStatusMsg:
 		m.loading = false
 		m.statuses = msg.statuses
 		return m, nil
+
+	case flashMsg:
+		m.flash = ""
+		return m, nil
 	}
 
 	return m, nil
@@ -82,6 +119,12 @@ func (m forwardingModel) View() string {
 		return s
 	}
 
+	mode := "catch-all"
+	if m.perMailbox {
+		mode = "per-mailbox"
+	}
+	s += "  " + zstyle.MutedText.Render(fmt.Sprintf("%-22s", "mode")) + mode + "\n\n"
+
 	if m.loading {
 		s += "  " + zstyle.MutedText.Render("loading...") + "\n"
 		return s
@@ -93,12 +136,52 @@ func (m forwardingModel) View() string {
 	}
 
 	for _, st := range m.statuses {
-		s += formatDomainStatus(st)
+		if m.perMailbox {
+			s += formatMailboxStatus(st)
+		} else {
+			s += formatDomainStatus(st)
+		}
+	}
+
+	s += "\n"
+
+	// always reserve a line for flash to prevent layout shift
+	if m.flash != "" {
+		s += "  " + zstyle.StatusOK.Render(m.flash) + "\n"
+	} else {
+		s += "\n"
 	}
 
 	return s
 }
 
+// formatMailboxStatus summarises a domain's rules in per-mailbox mode.
+func formatMailboxStatus(st domainForwardingStatus) string {
+	domain := fmt.Sprintf("  %-22s", st.domain)
+
+	if st.excluded {
+		return domain + zstyle.MutedText.Render("excluded") + "\n"
+	}
+
+	if st.err != nil {
+		return domain + zstyle.StatusErr.Render("error") + "\n"
+	}
+
+	n := 0
+	for _, r := range st.rules {
+		if r.Mailbox != forwarding.CatchAll {
+			n++
+		}
+	}
+	line := domain + zstyle.MutedText.Render(fmt.Sprintf("%d mailboxes", n))
+
+	if target := catchAllTarget(st.rules); target != "" {
+		line += " " + zstyle.StatusWarn.Render("(catch-all still set)")
+	}
+
+	return line + "\n"
+}
+
 func formatDomainStatus(st domainForwardingStatus) string {
 	domain := fmt.Sprintf("  %-22s", st.domain)
 
@@ -121,7 +204,7 @@ func formatDomainStatus(st domainForwardingStatus) string {
 // catchAllTarget returns the forwarding address for the wildcard mailbox, if any.
 func catchAllTarget(rules []namecheap.ForwardingRule) string {
 	for _, r := range rules {
-		if r.Mailbox == "*" {
+		if r.Mailbox == forwarding.CatchAll {
 			return r.ForwardTo
 		}
 	}
@@ -138,12 +221,11 @@ func fetchForwardingStatusCmd(cfg namecheap.Config, domains []string) tea.Cmd {
 
 // fetchForwardingStatus queries forwarding status for each domain.
 func fetchForwardingStatus(ctx context.Context, getter forwardingGetter, domains []string) forwardingStatusMsg {
-	excluded := buildExclusionSet(excludedDomains)
 	statuses := make([]domainForwardingStatus, len(domains))
 
 	for i, d := range domains {
 		st := domainForwardingStatus{domain: d}
-		if excluded[d] {
+		if forwarding.Excluded(d) {
 			st.excluded = true
 		} else {
 			rules, err := getter.GetForwarding(ctx, d)
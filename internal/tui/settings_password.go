@@ -0,0 +1,208 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zarlcorp/core/pkg/zstyle"
+	"github.com/zarlcorp/zburn/internal/rekey"
+)
+
+type mpField int
+
+const (
+	mpCurrent mpField = iota
+	mpNew
+	mpConfirm
+	mpFieldCount
+)
+
+var mpLabels = [mpFieldCount]string{
+	"current",
+	"new",
+	"confirm",
+}
+
+// changePasswordMsg requests re-keying the vault under a new password.
+type changePasswordMsg struct {
+	current string
+	next    string
+}
+
+// rekeyResultMsg carries the result of re-keying the vault.
+type rekeyResultMsg struct {
+	password string
+	result   rekey.Result
+	err      error
+}
+
+// masterPasswordModel is the form for changing the master password.
+type masterPasswordModel struct {
+	inputs []textinput.Model
+	focus  int
+	flash  string
+	errMsg string
+	saving bool
+}
+
+func newMasterPasswordModel() masterPasswordModel {
+	inputs := make([]textinput.Model, mpFieldCount)
+
+	for i := range inputs {
+		ti := textinput.New()
+		ti.CharLimit = 256
+		ti.Width = 50
+		ti.EchoMode = textinput.EchoPassword
+		ti.EchoCharacter = '•'
+		ti.Placeholder = mpLabels[i] + " password"
+		inputs[i] = ti
+	}
+
+	inputs[0].Focus()
+
+	return masterPasswordModel{inputs: inputs}
+}
+
+func (m masterPasswordModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m masterPasswordModel) Update(msg tea.Msg) (masterPasswordModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.saving {
+			return m, nil
+		}
+
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+
+		if msg.Type == tea.KeyEsc {
+			return m, func() tea.Msg { return navigateMsg{view: viewSettings} }
+		}
+
+		m.errMsg = ""
+
+		if key.Matches(msg, zstyle.KeyTab) || msg.Type == tea.KeyDown {
+			return m.nextField(), nil
+		}
+
+		if msg.Type == tea.KeyUp || msg.Type == tea.KeyShiftTab {
+			return m.prevField(), nil
+		}
+
+		if key.Matches(msg, zstyle.KeyEnter) {
+			// enter on last field submits; otherwise advance
+			if m.focus == int(mpFieldCount)-1 {
+				return m.submit()
+			}
+			return m.nextField(), nil
+		}
+
+	case rekeyResultMsg:
+		m.saving = false
+		m.flash = ""
+		if msg.err != nil {
+			m.errMsg = msg.err.Error()
+			m.inputs[mpCurrent].SetValue("")
+			return m.focusField(mpCurrent), nil
+		}
+		m = newMasterPasswordModel()
+		m.flash = "master password changed"
+		if msg.result.Leftover != "" {
+			m.errMsg = "old vault left at " + msg.result.Leftover + "; delete it by hand"
+		}
+		return m, clearFlashAfter()
+
+	case flashMsg:
+		m.flash = ""
+		return m, nil
+	}
+
+	return m.updateInput(msg)
+}
+
+func (m masterPasswordModel) submit() (masterPasswordModel, tea.Cmd) {
+	current := m.inputs[mpCurrent].Value()
+	next := m.inputs[mpNew].Value()
+
+	switch {
+	case current == "":
+		m.errMsg = "current password is required"
+		return m.focusField(mpCurrent), nil
+	case next == "":
+		m.errMsg = "new password cannot be empty"
+		return m.focusField(mpNew), nil
+	case next != m.inputs[mpConfirm].Value():
+		m.errMsg = "passwords do not match"
+		m.inputs[mpConfirm].SetValue("")
+		return m.focusField(mpConfirm), nil
+	}
+
+	m.saving = true
+	m.flash = "re-encrypting vault..."
+	return m, func() tea.Msg { return changePasswordMsg{current: current, next: next} }
+}
+
+func (m masterPasswordModel) focusField(f mpField) masterPasswordModel {
+	m.inputs[m.focus].Blur()
+	m.focus = int(f)
+	m.inputs[m.focus].Focus()
+	return m
+}
+
+func (m masterPasswordModel) nextField() masterPasswordModel {
+	return m.focusField(mpField((m.focus + 1) % int(mpFieldCount)))
+}
+
+func (m masterPasswordModel) prevField() masterPasswordModel {
+	return m.focusField(mpField((m.focus + int(mpFieldCount) - 1) % int(mpFieldCount)))
+}
+
+func (m masterPasswordModel) updateInput(msg tea.Msg) (masterPasswordModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+	return m, cmd
+}
+
+func (m masterPasswordModel) View() string {
+	accentStyle := lipgloss.NewStyle().Foreground(zstyle.ZburnAccent).Bold(true)
+
+	s := "\n"
+	s += "  " + zstyle.MutedText.Render("every entry is re-encrypted under the new password.") + "\n\n"
+
+	for i, input := range m.inputs {
+		label := zstyle.MutedText.Render(fmt.Sprintf("  %-10s", mpLabels[i]))
+		if i == m.focus {
+			s += accentStyle.Render("▸") + " " + label + input.View() + "\n"
+		} else {
+			s += "  " + label + input.View() + "\n"
+		}
+	}
+
+	s += "\n"
+
+	if m.flash != "" {
+		s += "  " + zstyle.StatusOK.Render(m.flash) + "\n"
+	}
+	if m.errMsg != "" {
+		s += "  " + zstyle.StatusErr.Render(m.errMsg) + "\n"
+	}
+	if m.flash == "" && m.errMsg == "" {
+		s += "\n"
+	}
+
+	return s
+}
+
+// rekeyCmd re-encrypts the vault in dir off the UI goroutine.
+func rekeyCmd(dir, current, next string) tea.Cmd {
+	return func() tea.Msg {
+		res, err := rekey.Rekey(dir, current, next)
+		return rekeyResultMsg{password: next, result: res, err: err}
+	}
+}
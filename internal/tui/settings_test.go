@@ -306,7 +306,7 @@ func TestSettingsSelectTwilio(t *testing.T) {
 
 func TestSettingsSelectBack(t *testing.T) {
 	m := newSettingsModel(NamecheapSettings{}, GmailSettings{}, TwilioSettings{})
-	m.cursor = 4 // back
+	m.cursor = int(settingsBack)
 	_, cmd := m.Update(enterKey())
 	if cmd == nil {
 		t.Fatal("enter should produce command")
@@ -1251,6 +1251,87 @@ func TestRootNavigateToForwardingFetchesWhenConfigured(t *testing.T) {
 	}
 }
 
+// master password form tests
+
+func TestMasterPasswordFormSubmit(t *testing.T) {
+	m := newMasterPasswordModel()
+	m.inputs[mpCurrent].SetValue("old")
+	m.inputs[mpNew].SetValue("new")
+	m.inputs[mpConfirm].SetValue("new")
+	m.focus = int(mpConfirm)
+
+	m, cmd := m.Update(enterKey())
+	if cmd == nil {
+		t.Fatal("enter on confirm should submit")
+	}
+	msg, ok := cmd().(changePasswordMsg)
+	if !ok || msg.current != "old" || msg.next != "new" {
+		t.Errorf("msg = %#v, want changePasswordMsg{old, new}", msg)
+	}
+	if !m.saving {
+		t.Error("form should be saving while the vault is re-encrypted")
+	}
+	if _, cmd := m.Update(keyMsg('x')); cmd != nil {
+		t.Error("keys should be ignored while saving")
+	}
+}
+
+func TestMasterPasswordFormValidates(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		next    string
+		confirm string
+		want    string
+	}{
+		{"no current", "", "new", "new", "current password is required"},
+		{"empty new", "old", "", "", "new password cannot be empty"},
+		{"mismatch", "old", "new", "neww", "passwords do not match"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMasterPasswordModel()
+			m.inputs[mpCurrent].SetValue(tt.current)
+			m.inputs[mpNew].SetValue(tt.next)
+			m.inputs[mpConfirm].SetValue(tt.confirm)
+			m.focus = int(mpConfirm)
+
+			m, cmd := m.Update(enterKey())
+			if cmd != nil {
+				t.Error("invalid form should not submit")
+			}
+			if m.errMsg != tt.want {
+				t.Errorf("errMsg = %q, want %q", m.errMsg, tt.want)
+			}
+		})
+	}
+}
+
+func TestMasterPasswordFormError(t *testing.T) {
+	m := newMasterPasswordModel()
+	m.inputs[mpCurrent].SetValue("wrong")
+	m.saving = true
+
+	m, _ = m.Update(rekeyResultMsg{err: zstore.ErrWrongPassword})
+	if m.saving || m.errMsg != zstore.ErrWrongPassword.Error() {
+		t.Errorf("saving = %v, errMsg = %q", m.saving, m.errMsg)
+	}
+	if m.inputs[mpCurrent].Value() != "" || m.focus != int(mpCurrent) {
+		t.Error("current password should be cleared and focused")
+	}
+}
+
+func TestMasterPasswordEscGoesBack(t *testing.T) {
+	m := newMasterPasswordModel()
+	_, cmd := m.Update(escKey())
+	if cmd == nil {
+		t.Fatal("esc should produce command")
+	}
+	if nav, ok := cmd().(navigateMsg); !ok || nav.view != viewSettings {
+		t.Errorf("msg = %#v, want navigateMsg to settings", nav)
+	}
+}
+
 // fakeForwardingGetter is a test double for the forwardingGetter interface.
 type fakeForwardingGetter struct {
 	rules map[string][]namecheap.ForwardingRule
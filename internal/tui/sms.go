@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zarlcorp/zburn/internal/codes"
+	"github.com/zarlcorp/zburn/internal/identity"
+	"github.com/zarlcorp/zburn/internal/twilio"
+)
+
+// smsLimit caps how many messages are fetched per refresh.
+const smsLimit = 20
+
+// viewSMSMsg requests the SMS inbox for an identity.
+type viewSMSMsg struct {
+	identity identity.Identity
+}
+
+// smsResultMsg carries messages fetched for the SMS view.
+type smsResultMsg struct {
+	messages []smsMessage
+	err      error
+}
+
+func (r smsResultMsg) result() ([]smsMessage, error) {
+	return r.messages, r.err
+}
+
+// smsMessage pairs a received SMS with the codes found in its body.
+type smsMessage struct {
+	message twilio.SMSMessage
+	codes   []codes.Code
+}
+
+// topCode returns the most likely verification code, or "" if none.
+func (sm smsMessage) topCode() string {
+	if len(sm.codes) == 0 {
+		return ""
+	}
+	return sm.codes[0].Value
+}
+
+// row renders the date, sender and body of the message on one line.
+func (sm smsMessage) row() string {
+	body := strings.Join(strings.Fields(sm.message.Body), " ")
+	return fmt.Sprintf("%-12s %-16s %-40s",
+		messageDate(sm.message.DateSent), truncate(sm.message.From, 16), truncate(body, 40))
+}
+
+// smsReader abstracts the Twilio message listing for testing.
+type smsReader interface {
+	ListMessages(ctx context.Context, to string, limit int) ([]twilio.SMSMessage, error)
+}
+
+// smsModel lists SMS sent to an identity's provisioned number.
+type smsModel = messageList[smsMessage]
+
+func newSMSModel(id identity.Identity, number string) smsModel {
+	return smsModel{
+		identity:   id,
+		title:      number,
+		loading:    true,
+		refresh:    func(id identity.Identity) tea.Msg { return viewSMSMsg{identity: id} },
+		showLatest: true,
+	}
+}
+
+// fetchSMSCmd returns a tea.Cmd that loads recent SMS for a number.
+func fetchSMSCmd(cfg twilio.Config, number string) tea.Cmd {
+	return func() tea.Msg {
+		msgs, err := fetchSMS(context.Background(), twilio.NewClient(cfg), number, smsLimit)
+		return smsResultMsg{messages: msgs, err: err}
+	}
+}
+
+// fetchSMS lists messages sent to number and extracts codes from each body.
+func fetchSMS(ctx context.Context, r smsReader, number string, limit int) ([]smsMessage, error) {
+	raw, err := r.ListMessages(ctx, number, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make([]smsMessage, len(raw))
+	for i, sm := range raw {
+		msgs[i] = smsMessage{
+			message: sm,
+			codes:   codes.Extract(sm.Body),
+		}
+	}
+	return msgs, nil
+}
@@ -0,0 +1,159 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zarlcorp/zburn/internal/burn"
+	"github.com/zarlcorp/zburn/internal/twilio"
+)
+
+// fakeSMSReader serves canned messages and records the requested number.
+type fakeSMSReader struct {
+	to       string
+	messages []twilio.SMSMessage
+	err      error
+}
+
+func (f *fakeSMSReader) ListMessages(_ context.Context, to string, _ int) ([]twilio.SMSMessage, error) {
+	f.to = to
+	return f.messages, f.err
+}
+
+func TestFetchSMS(t *testing.T) {
+	r := &fakeSMSReader{messages: []twilio.SMSMessage{
+		{SID: "SM1", From: "+15550001", Body: "Your Acme verification code is 739104"},
+		{SID: "SM2", From: "+15550002", Body: "Thanks for signing up"},
+	}}
+
+	msgs, err := fetchSMS(context.Background(), r, "+447700900123", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.to != "+447700900123" {
+		t.Errorf("to = %q, want %q", r.to, "+447700900123")
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("got %d messages, want 2", len(msgs))
+	}
+	if got := msgs[0].topCode(); got != "739104" {
+		t.Errorf("top code = %q, want %q", got, "739104")
+	}
+	if got := msgs[1].topCode(); got != "" {
+		t.Errorf("top code = %q, want empty", got)
+	}
+}
+
+func TestFetchSMSError(t *testing.T) {
+	r := &fakeSMSReader{err: fmt.Errorf("twilio: unauthorized")}
+	if _, err := fetchSMS(context.Background(), r, "+447700900123", 10); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestSMSViewHighlightsLatestCode(t *testing.T) {
+	msgs, _ := fetchSMS(context.Background(), &fakeSMSReader{messages: []twilio.SMSMessage{
+		{From: "+15550002", Body: "Welcome aboard", DateSent: time.Now()},
+		{From: "+15550001", Body: "Your code is 551022", DateSent: time.Now().Add(-time.Minute)},
+	}}, "+447700900123", 10)
+
+	m := newSMSModel(testIdentity(), "+447700900123")
+	m, _ = m.Update(smsResultMsg{messages: msgs})
+
+	view := m.View()
+	if !strings.Contains(view, "+447700900123") {
+		t.Error("view should show the number")
+	}
+	if !strings.Contains(view, "latest code") || !strings.Contains(view, "551022") {
+		t.Error("view should highlight the latest code")
+	}
+	if m.latestCode() != "551022" {
+		t.Errorf("latest code = %q, want %q", m.latestCode(), "551022")
+	}
+}
+
+func TestSMSViewEmpty(t *testing.T) {
+	m := newSMSModel(testIdentity(), "+447700900123")
+	m, _ = m.Update(smsResultMsg{})
+
+	if !strings.Contains(m.View(), "no messages") {
+		t.Error("empty sms view should say no messages")
+	}
+}
+
+func TestSMSIgnoresInboxResult(t *testing.T) {
+	m := newSMSModel(testIdentity(), "+447700900123")
+	m, _ = m.Update(inboxResultMsg{messages: testInboxMessages()})
+
+	if !m.loading || len(m.messages) != 0 {
+		t.Error("sms view should only accept sms results")
+	}
+}
+
+func TestSMSBackToDetail(t *testing.T) {
+	m := newSMSModel(testIdentity(), "+447700900123")
+	_, cmd := m.Update(escKey())
+	if cmd == nil {
+		t.Fatal("esc should produce command")
+	}
+	nav, ok := cmd().(navigateMsg)
+	if !ok || nav.view != viewDetail {
+		t.Error("esc should navigate to detail")
+	}
+}
+
+func TestDetailMNavigatesToSMS(t *testing.T) {
+	m := newDetailModel(testIdentity())
+	_, cmd := m.Update(keyMsg('m'))
+	if cmd == nil {
+		t.Fatal("m should produce command")
+	}
+	if _, ok := cmd().(viewSMSMsg); !ok {
+		t.Error("should emit viewSMSMsg")
+	}
+}
+
+func TestRootSMSRequiresPhone(t *testing.T) {
+	m := New("1.0", t.TempDir(), nil, false)
+	m.active = viewDetail
+	m.detail = newDetailModel(testIdentity())
+	m.twConfig = TwilioSettings{AccountSID: "AC123", AuthToken: "tok"}
+
+	result, _ := m.Update(viewSMSMsg{identity: testIdentity()})
+	rm := result.(Model)
+
+	if rm.active != viewDetail {
+		t.Errorf("active = %d, want viewDetail", rm.active)
+	}
+	if rm.detail.flash != "no phone number provisioned" {
+		t.Errorf("flash = %q, want %q", rm.detail.flash, "no phone number provisioned")
+	}
+}
+
+func TestRootSMSOpensView(t *testing.T) {
+	m := New("1.0", t.TempDir(), nil, false)
+	m.active = viewDetail
+	m.twConfig = TwilioSettings{AccountSID: "AC123", AuthToken: "tok"}
+	m.SetExternalServices(ExternalServices{
+		PhoneForIdentity: func(string) *burn.PhoneConfig {
+			return &burn.PhoneConfig{NumberSID: "PN1", PhoneNumber: "+447700900123"}
+		},
+	})
+
+	result, cmd := m.Update(viewSMSMsg{identity: testIdentity()})
+	rm := result.(Model)
+
+	if rm.active != viewSMS {
+		t.Errorf("active = %d, want viewSMS", rm.active)
+	}
+	if rm.sms.title != "+447700900123" {
+		t.Errorf("number = %q, want %q", rm.sms.title, "+447700900123")
+	}
+	if cmd == nil {
+		t.Error("should return fetch command")
+	}
+}
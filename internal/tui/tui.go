@@ -3,18 +3,25 @@ package tui
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"sort"
+	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/zarlcorp/core/pkg/zfilesystem"
 	"github.com/zarlcorp/core/pkg/zstore"
 	"github.com/zarlcorp/core/pkg/zstyle"
 	"github.com/zarlcorp/zburn/internal/burn"
+	"github.com/zarlcorp/zburn/internal/config"
 	"github.com/zarlcorp/zburn/internal/credential"
+	"github.com/zarlcorp/zburn/internal/forwarding"
 	"github.com/zarlcorp/zburn/internal/identity"
+	"github.com/zarlcorp/zburn/internal/namecheap"
+	"github.com/zarlcorp/zburn/internal/twilio"
 )
 
 type viewID int
@@ -34,11 +41,17 @@ const (
 	viewSettingsTwilio
 	viewBurn
 	viewForwarding
+	viewInbox
+	viewSMS
+	viewProvision
+	viewIdentityEdit
+	viewSettingsPassword
 )
 
 // ExternalServices holds optional integrations for burn cascade.
 type ExternalServices struct {
-	Releaser burn.PhoneReleaser
+	Releaser   burn.PhoneReleaser
+	Forwarding burn.ForwardingRemover
 	// PhoneForIdentity returns provisioned phone config for an identity, or nil.
 	PhoneForIdentity func(identityID string) *burn.PhoneConfig
 }
@@ -52,6 +65,9 @@ type Model struct {
 	identities  *zstore.Collection[identity.Identity]
 	credentials *zstore.Collection[credential.Credential]
 	configs     *zstore.Collection[configEnvelope]
+	phones      *zstore.Collection[burn.PhoneConfig]
+	templates   *zstore.Collection[identity.Template]
+	presets     *zstore.Collection[identity.PasswordPreset]
 	firstRun    bool
 	external    ExternalServices
 
@@ -64,13 +80,18 @@ type Model struct {
 	credentialList   credentialListModel
 	credentialDetail credentialDetailModel
 	credentialForm   credentialFormModel
+	identityEdit     identityEditModel
 	burn             burnModel
+	inbox            inboxModel
+	sms              smsModel
+	provision        provisionModel
 
 	// settings views
 	settings          settingsModel
 	settingsNamecheap namecheapModel
 	settingsGmail     gmailModel
 	settingsTwilio    twilioModel
+	settingsPassword  masterPasswordModel
 	forwarding        forwardingModel
 
 	// cached config state
@@ -78,10 +99,25 @@ type Model struct {
 	gmConfig GmailSettings
 	twConfig TwilioSettings
 
+	// idle auto-lock
+	lockConfig   LockSettings
+	lastActivity time.Time
+	idleGen      int
+
+	// commands in flight that must finish before the vault can lock
+	rekeying    bool   // the master password is being changed
+	buying      bool   // a phone number is being bought
+	burning     bool   // a burn cascade is running
+	pendingLock string // notice of a lock deferred until they finish
+
 	// domain rotation
 	domains   []string
 	domainIdx int
 
+	// template rotation; templateIdx 0 means no template
+	templateList []identity.Template
+	templateIdx  int
+
 	// terminal dimensions
 	width  int
 	height int
@@ -118,6 +154,26 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case passwordSubmitMsg:
 		return m.openStore(msg.password)
+	}
+
+	// while the password prompt is up only the prompt runs, so results of
+	// commands started before the vault was locked are dropped
+	if m.active == viewPassword {
+		return m.updateActive(msg)
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		m.lastActivity = time.Now()
+		if key.Matches(msg, keyLock) {
+			return m.requestLock("locked")
+		}
+
+	case idleTickMsg:
+		return m.handleIdleTick(msg)
+
+	case setLockTimeoutMsg:
+		return m.handleSetLockTimeout(msg.timeout)
 
 	case navigateMsg:
 		return m.navigate(msg.view)
@@ -134,13 +190,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case viewCredentialsMsg:
 		return m.loadCredentialList(msg.identity)
 
+	case editIdentityMsg:
+		m.identityEdit = newIdentityEditModel(msg.identity)
+		m.active = viewIdentityEdit
+		return m, m.identityEdit.Init()
+
+	case updateIdentityMsg:
+		return m.handleUpdateIdentity(msg.identity)
+
 	case viewCredentialMsg:
 		m.credentialDetail = newCredentialDetailModel(msg.credential)
 		m.active = viewCredentialDetail
 		return m, m.credentialDetail.Init()
 
 	case addCredentialMsg:
-		m.credentialForm = newCredentialFormModel(msg.identity, nil)
+		m.credentialForm = newCredentialFormModel(msg.identity, nil).withPresets(m.loadPresets())
 		m.active = viewCredentialForm
 		return m, m.credentialForm.Init()
 
@@ -168,15 +232,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case disconnectGmailMsg:
 		return m.handleDisconnectGmail()
 
+	case changePasswordMsg:
+		m.rekeying = true
+		return m, rekeyCmd(m.dataDir, msg.current, msg.next)
+
+	case rekeyResultMsg:
+		m.rekeying = false
+		return resumeLock(m.handleRekeyResult(msg))
+
 	case burnStartMsg:
 		return m.startBurn(msg.identity)
 
 	case burnIdentityMsg:
+		m.burning = true
 		return m.executeBurn(msg.identity)
 
 	case cycleDomainMsg:
 		return m.handleCycleDomain()
 
+	case cycleTemplateMsg:
+		return m.handleCycleTemplate()
+
 	case forwardingStatusMsg:
 		m.forwarding, _ = m.forwarding.Update(msg)
 		return m, nil
@@ -184,9 +260,50 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case forwardingResultMsg:
 		return m.handleForwardingResult(msg)
 
+	case setForwardingModeMsg:
+		return m.handleSetForwardingMode(msg.mode)
+
+	case reconcileForwardingMsg:
+		return m, m.reconcileForwarding(m.ncConfig, m.gmConfig.Email)
+
+	case reconcileResultMsg:
+		return m.handleReconcileResult(msg)
+
+	case mailboxForwardingMsg:
+		return m.handleMailboxForwarding(msg)
+
 	case burnResultMsg:
+		m.burning = false
 		m.burn, _ = m.burn.Update(msg)
-		return m, clearFlashAfter3s()
+		return resumeLock(m, clearFlashAfter3s())
+
+	case viewInboxMsg:
+		return m.loadInbox(msg.identity)
+
+	case inboxResultMsg:
+		return m.handleInboxResult(msg)
+
+	case viewSMSMsg:
+		return m.loadSMS(msg.identity)
+
+	case smsResultMsg:
+		m.sms, _ = m.sms.Update(msg)
+		return m, nil
+
+	case provisionPhoneMsg:
+		return m.startProvision(msg.identity)
+
+	case provisionSearchMsg:
+		m.provision, _ = m.provision.Update(msg)
+		return m, nil
+
+	case buyNumberMsg:
+		m.buying = true
+		return m, buyNumberCmd(m.twConfig.TwilioConfig(), msg.identity, msg.number)
+
+	case numberBoughtMsg:
+		m.buying = false
+		return resumeLock(m.handleNumberBought(msg))
 	}
 
 	return m.updateActive(msg)
@@ -211,6 +328,8 @@ func (m Model) View() string {
 		content = m.credentialDetail.View()
 	case viewCredentialForm:
 		content = m.credentialForm.View()
+	case viewIdentityEdit:
+		content = m.identityEdit.View()
 	case viewSettings:
 		content = m.settings.View()
 	case viewSettingsNamecheap:
@@ -219,10 +338,18 @@ func (m Model) View() string {
 		content = m.settingsGmail.View()
 	case viewSettingsTwilio:
 		content = m.settingsTwilio.View()
+	case viewSettingsPassword:
+		content = m.settingsPassword.View()
 	case viewBurn:
 		content = m.burn.View()
 	case viewForwarding:
 		content = m.forwarding.View()
+	case viewInbox:
+		content = m.inbox.View()
+	case viewSMS:
+		content = m.sms.View()
+	case viewProvision:
+		content = m.provision.View()
 	}
 
 	header := zstyle.RenderHeader("zburn", viewTitle(m.active), zstyle.ZburnAccent)
@@ -251,6 +378,8 @@ func viewTitle(id viewID) string {
 		return "credential"
 	case viewCredentialForm:
 		return "credential form"
+	case viewIdentityEdit:
+		return "edit identity"
 	case viewSettings:
 		return "settings"
 	case viewSettingsNamecheap:
@@ -259,10 +388,18 @@ func viewTitle(id viewID) string {
 		return "gmail"
 	case viewSettingsTwilio:
 		return "twilio"
+	case viewSettingsPassword:
+		return "master password"
 	case viewBurn:
 		return "burn"
 	case viewForwarding:
 		return "forwarding"
+	case viewInbox:
+		return "inbox"
+	case viewSMS:
+		return "sms"
+	case viewProvision:
+		return "provision phone"
 	}
 	return ""
 }
@@ -279,6 +416,7 @@ func helpFor(id viewID) []zstyle.HelpPair {
 	case viewMenu:
 		return []zstyle.HelpPair{
 			{Key: "enter", Desc: "select"},
+			{Key: "ctrl+l", Desc: "lock"},
 			{Key: "q", Desc: "quit"},
 		}
 	case viewGenerate:
@@ -287,12 +425,14 @@ func helpFor(id viewID) []zstyle.HelpPair {
 			{Key: "c", Desc: "copy all"},
 			{Key: "enter", Desc: "copy field"},
 			{Key: "n", Desc: "new"},
+			{Key: "t", Desc: "template"},
 			{Key: "esc", Desc: "back"},
 			{Key: "q", Desc: "quit"},
 		}
 	case viewList:
 		return []zstyle.HelpPair{
 			{Key: "enter", Desc: "view"},
+			{Key: "/", Desc: "search"},
 			{Key: "d", Desc: "burn"},
 			{Key: "esc", Desc: "back"},
 			{Key: "q", Desc: "quit"},
@@ -301,7 +441,11 @@ func helpFor(id viewID) []zstyle.HelpPair {
 		return []zstyle.HelpPair{
 			{Key: "enter", Desc: "copy field"},
 			{Key: "c", Desc: "copy all"},
+			{Key: "e", Desc: "edit"},
 			{Key: "w", Desc: "credentials"},
+			{Key: "i", Desc: "inbox"},
+			{Key: "m", Desc: "sms"},
+			{Key: "p", Desc: "phone"},
 			{Key: "d", Desc: "burn"},
 			{Key: "esc", Desc: "back"},
 			{Key: "q", Desc: "quit"},
@@ -309,6 +453,7 @@ func helpFor(id viewID) []zstyle.HelpPair {
 	case viewCredentialList:
 		return []zstyle.HelpPair{
 			{Key: "enter", Desc: "view"},
+			{Key: "/", Desc: "search"},
 			{Key: "a", Desc: "add"},
 			{Key: "d", Desc: "delete"},
 			{Key: "esc", Desc: "back"},
@@ -323,11 +468,19 @@ func helpFor(id viewID) []zstyle.HelpPair {
 			{Key: "esc", Desc: "back"},
 			{Key: "q", Desc: "quit"},
 		}
+	case viewIdentityEdit:
+		return []zstyle.HelpPair{
+			{Key: "tab", Desc: "next"},
+			{Key: "shift+tab", Desc: "prev"},
+			{Key: "enter", Desc: "save"},
+			{Key: "esc", Desc: "cancel"},
+		}
 	case viewCredentialForm:
 		return []zstyle.HelpPair{
 			{Key: "tab", Desc: "next"},
 			{Key: "shift+tab", Desc: "prev"},
 			{Key: "space", Desc: "cycle"},
+			{Key: "ctrl+p", Desc: "policy"},
 			{Key: "enter", Desc: "save"},
 			{Key: "esc", Desc: "cancel"},
 		}
@@ -359,6 +512,12 @@ func helpFor(id viewID) []zstyle.HelpPair {
 			{Key: "esc", Desc: "back"},
 			{Key: "q", Desc: "quit"},
 		}
+	case viewSettingsPassword:
+		return []zstyle.HelpPair{
+			{Key: "tab", Desc: "next"},
+			{Key: "enter", Desc: "change"},
+			{Key: "esc", Desc: "back"},
+		}
 	case viewBurn:
 		return []zstyle.HelpPair{
 			{Key: "y", Desc: "confirm"},
@@ -367,6 +526,21 @@ func helpFor(id viewID) []zstyle.HelpPair {
 		}
 	case viewForwarding:
 		return []zstyle.HelpPair{
+			{Key: "m", Desc: "mode"},
+			{Key: "r", Desc: "reconcile"},
+			{Key: "esc", Desc: "back"},
+			{Key: "q", Desc: "quit"},
+		}
+	case viewInbox, viewSMS:
+		return []zstyle.HelpPair{
+			{Key: "enter", Desc: "copy code"},
+			{Key: "r", Desc: "refresh"},
+			{Key: "esc", Desc: "back"},
+			{Key: "q", Desc: "quit"},
+		}
+	case viewProvision:
+		return []zstyle.HelpPair{
+			{Key: "enter", Desc: "buy"},
 			{Key: "esc", Desc: "back"},
 			{Key: "q", Desc: "quit"},
 		}
@@ -394,6 +568,8 @@ func (m Model) updateActive(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.credentialDetail, cmd = m.credentialDetail.Update(msg)
 	case viewCredentialForm:
 		m.credentialForm, cmd = m.credentialForm.Update(msg)
+	case viewIdentityEdit:
+		m.identityEdit, cmd = m.identityEdit.Update(msg)
 	case viewSettings:
 		m.settings, cmd = m.settings.Update(msg)
 	case viewSettingsNamecheap:
@@ -402,58 +578,111 @@ func (m Model) updateActive(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.settingsGmail, cmd = m.settingsGmail.Update(msg)
 	case viewSettingsTwilio:
 		m.settingsTwilio, cmd = m.settingsTwilio.Update(msg)
+	case viewSettingsPassword:
+		m.settingsPassword, cmd = m.settingsPassword.Update(msg)
 	case viewBurn:
 		m.burn, cmd = m.burn.Update(msg)
 	case viewForwarding:
 		m.forwarding, cmd = m.forwarding.Update(msg)
+	case viewInbox:
+		m.inbox, cmd = m.inbox.Update(msg)
+	case viewSMS:
+		m.sms, cmd = m.sms.Update(msg)
+	case viewProvision:
+		m.provision, cmd = m.provision.Update(msg)
 	}
 
 	return m, cmd
 }
 
 func (m Model) openStore(password string) (tea.Model, tea.Cmd) {
-	if err := os.MkdirAll(m.dataDir, 0o700); err != nil {
-		m.password, _ = m.password.Update(passwordErrMsg{
-			err: fmt.Errorf("create data dir: %w", err),
-		})
+	if err := m.unlock(password); err != nil {
+		m.password, _ = m.password.Update(passwordErrMsg{err: err})
 		return m, nil
 	}
+	m.active = viewMenu
+	return m.startIdleTimer()
+}
+
+// unlock opens the store in the data dir with password and loads its
+// collections and cached config.
+func (m *Model) unlock(password string) error {
+	if err := os.MkdirAll(m.dataDir, 0o700); err != nil {
+		return fmt.Errorf("create data dir: %w", err)
+	}
 
 	fsys := zfilesystem.NewOSFileSystem(m.dataDir)
 	s, err := zstore.Open(fsys, []byte(password))
 	if err != nil {
-		m.password, _ = m.password.Update(passwordErrMsg{err: err})
-		return m, nil
+		return err
 	}
 
 	idCol, err := zstore.NewCollection[identity.Identity](s, "identities")
 	if err != nil {
 		s.Close()
-		m.password, _ = m.password.Update(passwordErrMsg{err: err})
-		return m, nil
+		return err
 	}
 
 	credCol, err := zstore.NewCollection[credential.Credential](s, "credentials")
 	if err != nil {
 		s.Close()
-		m.password, _ = m.password.Update(passwordErrMsg{err: err})
-		return m, nil
+		return err
 	}
 
 	cfgCol, err := zstore.NewCollection[configEnvelope](s, "config")
 	if err != nil {
 		s.Close()
-		m.password, _ = m.password.Update(passwordErrMsg{err: err})
-		return m, nil
+		return err
+	}
+
+	phoneCol, err := zstore.NewCollection[burn.PhoneConfig](s, "phones")
+	if err != nil {
+		s.Close()
+		return err
+	}
+
+	tplCol, err := zstore.NewCollection[identity.Template](s, "templates")
+	if err != nil {
+		s.Close()
+		return err
+	}
+
+	presetCol, err := zstore.NewCollection[identity.PasswordPreset](s, "presets")
+	if err != nil {
+		s.Close()
+		return err
 	}
 
 	m.store = s
 	m.identities = idCol
 	m.credentials = credCol
 	m.configs = cfgCol
+	m.phones = phoneCol
+	m.templates = tplCol
+	m.presets = presetCol
 	m.loadConfigs()
-	m.active = viewMenu
-	return m, nil
+	m.loadTemplates()
+	return nil
+}
+
+// handleRekeyResult reopens the store after the master password changed,
+// as the open store still holds keys derived from the old one.
+func (m Model) handleRekeyResult(msg rekeyResultMsg) (tea.Model, tea.Cmd) {
+	if msg.err == nil {
+		m.Close()
+		if err := m.unlock(msg.password); err != nil {
+			m.store = nil
+			m.pendingLock = ""
+			m.password = newPasswordModel(false)
+			m.password.errMsg = "reopen store: " + err.Error()
+			m.active = viewPassword
+			return m, m.password.Init()
+		}
+	}
+
+	var cmd tea.Cmd
+	m.settingsPassword, cmd = m.settingsPassword.Update(msg)
+	return m, cmd
 }
 
 func (m Model) navigate(view viewID) (tea.Model, tea.Cmd) {
@@ -471,8 +700,12 @@ func (m Model) navigate(view viewID) (tea.Model, tea.Cmd) {
 
 	case viewGenerate:
 		domain := m.currentDomain()
-		id := m.gen.Generate(domain)
-		m.generate = newGenerateModel(id, domain)
+		var opts []identity.Option
+		if t, ok := m.currentTemplate(); ok {
+			opts = append(opts, identity.WithTemplate(t))
+		}
+		id := m.gen.Generate(domain, opts...)
+		m.generate = m.newGenerate(id, domain)
 		m.active = viewGenerate
 		return m, tea.ClearScreen
 
@@ -495,7 +728,7 @@ func (m Model) navigate(view viewID) (tea.Model, tea.Cmd) {
 		return m, tea.Batch(cmd, tea.ClearScreen)
 
 	case viewSettings:
-		m.settings = newSettingsModel(m.ncConfig, m.gmConfig, m.twConfig)
+		m.settings = newSettingsModel(m.ncConfig, m.gmConfig, m.twConfig).withLock(m.lockConfig)
 		m.active = viewSettings
 		return m, tea.ClearScreen
 
@@ -514,6 +747,11 @@ func (m Model) navigate(view viewID) (tea.Model, tea.Cmd) {
 		m.active = viewSettingsTwilio
 		return m, tea.ClearScreen
 
+	case viewSettingsPassword:
+		m.settingsPassword = newMasterPasswordModel()
+		m.active = viewSettingsPassword
+		return m, tea.Batch(tea.ClearScreen, m.settingsPassword.Init())
+
 	case viewForwarding:
 		m.forwarding = newForwardingModel(m.ncConfig, m.gmConfig)
 		m.active = viewForwarding
@@ -547,25 +785,34 @@ func (m Model) loadList() (tea.Model, tea.Cmd) {
 		return ids[i].CreatedAt.After(ids[j].CreatedAt)
 	})
 
-	m.list = newListModel(ids)
-	m.list.credCounts = m.bulkCredCounts()
+	counts, sites := m.bulkCredIndex()
+	m.list = newListModel(ids).withCredSites(sites)
+	m.list.credCounts = counts
 	m.active = viewList
 	return m, nil
 }
 
-func (m Model) bulkCredCounts() map[string]int {
+// bulkCredIndex returns the credential count and the credential labels
+// and URLs of every identity, keyed by identity ID.
+func (m Model) bulkCredIndex() (map[string]int, map[string][]string) {
 	if m.credentials == nil {
-		return nil
+		return nil, nil
 	}
 	all, err := m.credentials.List()
 	if err != nil {
-		return nil
+		return nil, nil
 	}
 	counts := make(map[string]int)
+	sites := make(map[string][]string)
 	for _, c := range all {
 		counts[c.IdentityID]++
+		for _, f := range []string{c.Label, c.URL} {
+			if f != "" {
+				sites[c.IdentityID] = append(sites[c.IdentityID], f)
+			}
+		}
 	}
-	return counts
+	return counts, sites
 }
 
 func (m Model) handleCycleDomain() (tea.Model, tea.Cmd) {
@@ -576,10 +823,74 @@ func (m Model) handleCycleDomain() (tea.Model, tea.Cmd) {
 	domain := m.domains[m.domainIdx]
 	id := m.generate.identity
 	id.Email = m.gen.Email(id.FirstName, id.LastName, domain)
-	m.generate = newGenerateModel(id, domain)
+	m.generate = m.newGenerate(id, domain)
+	return m, nil
+}
+
+// handleCycleTemplate switches the generated identity to the next saved
+// template, regenerating only the template fields.
+func (m Model) handleCycleTemplate() (tea.Model, tea.Cmd) {
+	if len(m.templateList) == 0 {
+		return m, nil
+	}
+	m.templateIdx = (m.templateIdx + 1) % (len(m.templateList) + 1)
+
+	id := m.generate.identity
+	if t, ok := m.currentTemplate(); ok {
+		id = m.gen.ApplyTemplate(id, t)
+	} else {
+		id.Template = ""
+		id.Fields = nil
+	}
+	m.generate = m.newGenerate(id, m.generate.domain)
 	return m, nil
 }
 
+// newGenerate builds the generate view for id.
+func (m Model) newGenerate(id identity.Identity, domain string) generateModel {
+	g := newGenerateModel(id, domain)
+	g.hasTemplates = len(m.templateList) > 0
+	return g
+}
+
+// loadTemplates reads the saved identity templates, sorted by name.
+func (m *Model) loadTemplates() {
+	m.templateList = nil
+	m.templateIdx = 0
+	if m.templates == nil {
+		return
+	}
+	tpls, err := m.templates.List()
+	if err != nil {
+		return
+	}
+	sort.Slice(tpls, func(i, j int) bool {
+		return strings.ToLower(tpls[i].Name) < strings.ToLower(tpls[j].Name)
+	})
+	m.templateList = tpls
+}
+
+// loadPresets reads the saved password presets, sorted by site.
+func (m Model) loadPresets() []identity.PasswordPreset {
+	if m.presets == nil {
+		return nil
+	}
+	presets, err := m.presets.List()
+	if err != nil {
+		return nil
+	}
+	sort.Slice(presets, func(i, j int) bool { return presets[i].Site < presets[j].Site })
+	return presets
+}
+
+// currentTemplate returns the selected template, if any.
+func (m Model) currentTemplate() (identity.Template, bool) {
+	if m.templateIdx == 0 || m.templateIdx > len(m.templateList) {
+		return identity.Template{}, false
+	}
+	return m.templateList[m.templateIdx-1], true
+}
+
 func (m Model) handleSave(id identity.Identity) (tea.Model, tea.Cmd) {
 	if err := m.identities.Put(id.ID, id); err != nil {
 		m.generate.flash = "save: " + err.Error()
@@ -587,7 +898,15 @@ func (m Model) handleSave(id identity.Identity) (tea.Model, tea.Cmd) {
 	}
 
 	m.generate, _ = m.generate.Update(identitySavedMsg{})
-	return m, clearFlashAfter()
+
+	cmds := []tea.Cmd{clearFlashAfter()}
+	if m.ncConfig.PerMailbox() && m.gmConfig.Email != "" {
+		if mailbox, domain, ok := forwarding.Managed(id.Email, m.ncConfig.CachedDomains); ok {
+			cmds = append(cmds, addMailboxForwardingCmd(m.ncConfig.NamecheapConfig(), domain, mailbox, m.gmConfig.Email))
+		}
+	}
+
+	return m, tea.Batch(cmds...)
 }
 
 func (m Model) handleDelete(id string) (tea.Model, tea.Cmd) {
@@ -624,6 +943,23 @@ func (m Model) handleViewIdentity(id identity.Identity) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleUpdateIdentity stores an edited identity and returns to its detail view.
+func (m Model) handleUpdateIdentity(id identity.Identity) (tea.Model, tea.Cmd) {
+	if m.identities == nil {
+		return m, nil
+	}
+
+	if err := m.identities.Put(id.ID, id); err != nil {
+		m.identityEdit.flash = "save: " + err.Error()
+		return m, clearFlashAfter()
+	}
+
+	next, _ := m.handleViewIdentity(id)
+	m = next.(Model)
+	m.detail.flash = "saved"
+	return m, clearFlashAfter()
+}
+
 func (m Model) countCredentials(identityID string) (int, error) {
 	all, err := m.credentials.List()
 	if err != nil {
@@ -707,41 +1043,25 @@ func (m *Model) loadConfigs() {
 	m.ncConfig = loadConfig[NamecheapSettings](m.configs, "namecheap")
 	m.gmConfig = loadConfig[GmailSettings](m.configs, "gmail")
 	m.twConfig = loadConfig[TwilioSettings](m.configs, "twilio")
+	m.lockConfig = loadConfig[LockSettings](m.configs, config.KeyLock)
 	m.domains = m.ncConfig.CachedDomains
 	m.domainIdx = 0
 }
 
 // loadConfig reads a typed config from the envelope collection.
 func loadConfig[T any](col *zstore.Collection[configEnvelope], key string) T {
-	var zero T
-	if col == nil {
-		return zero
-	}
-
-	env, err := col.Get(key)
-	if err != nil {
-		return zero
-	}
-
-	var v T
-	if err := json.Unmarshal(env.Data, &v); err != nil {
-		return zero
-	}
-
-	return v
+	return config.Load[T](col, key)
 }
 
 // saveConfig persists a typed config into the envelope collection.
 func saveConfig[T any](col *zstore.Collection[configEnvelope], key string, v T) error {
-	data, err := json.Marshal(v)
-	if err != nil {
-		return fmt.Errorf("marshal config: %w", err)
-	}
-
-	return col.Put(key, configEnvelope{Data: data})
+	return config.Save(col, key, v)
 }
 
 func (m Model) handleSaveNamecheap(s NamecheapSettings) (tea.Model, tea.Cmd) {
+	// the credentials form does not edit the forwarding mode
+	s.ForwardingMode = m.ncConfig.ForwardingMode
+
 	if err := saveConfig(m.configs, "namecheap", s); err != nil {
 		m.settingsNamecheap.flash = "save: " + err.Error()
 		return m, clearFlashAfter()
@@ -753,9 +1073,9 @@ func (m Model) handleSaveNamecheap(s NamecheapSettings) (tea.Model, tea.Cmd) {
 
 	cmds := []tea.Cmd{clearFlashAfter()}
 
-	// trigger catch-all forwarding if Gmail is already configured
+	// trigger forwarding setup if Gmail is already configured
 	if m.gmConfig.Configured() && len(s.CachedDomains) > 0 {
-		cmds = append(cmds, forwardingCmd(s.NamecheapConfig(), s.CachedDomains, m.gmConfig.Email))
+		cmds = append(cmds, m.setupForwarding(s, m.gmConfig.Email))
 	}
 
 	return m, tea.Batch(cmds...)
@@ -773,9 +1093,9 @@ func (m Model) handleSaveGmail(s GmailSettings) (tea.Model, tea.Cmd) {
 
 	cmds := []tea.Cmd{clearFlashAfter()}
 
-	// trigger catch-all forwarding if Namecheap is already configured
+	// trigger forwarding setup if Namecheap is already configured
 	if m.ncConfig.Configured() && len(m.ncConfig.CachedDomains) > 0 && s.Email != "" {
-		cmds = append(cmds, forwardingCmd(m.ncConfig.NamecheapConfig(), m.ncConfig.CachedDomains, s.Email))
+		cmds = append(cmds, m.setupForwarding(m.ncConfig, s.Email))
 	}
 
 	return m, tea.Batch(cmds...)
@@ -793,13 +1113,65 @@ func (m Model) handleSaveTwilio(s TwilioSettings) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) handleForwardingResult(msg forwardingResultMsg) (tea.Model, tea.Cmd) {
+	// setting a catch-all replaces every rule on the domain with zburn's own
+	err := m.recordForwarding(func(l forwarding.Ledger) forwarding.Ledger {
+		for _, d := range msg.domains {
+			l = l.Set(d, []string{forwarding.CatchAll})
+		}
+		return l
+	})
 	flash := forwardingFlash(msg)
+	if err != nil {
+		flash += "; " + err.Error()
+	}
+	return m.forwardingFlashed(flash)
+}
+
+func (m Model) handleReconcileResult(msg reconcileResultMsg) (tea.Model, tea.Cmd) {
+	if msg.owned != nil {
+		if err := m.recordForwarding(func(forwarding.Ledger) forwarding.Ledger { return *msg.owned }); err != nil {
+			msg.err = errors.Join(msg.err, err)
+		}
+	}
+	return m.forwardingFlashed(reconcileFlash(msg))
+}
 
+func (m Model) handleMailboxForwarding(msg mailboxForwardingMsg) (tea.Model, tea.Cmd) {
+	err := msg.err
+	if err == nil {
+		err = m.recordForwarding(func(l forwarding.Ledger) forwarding.Ledger { return l.Add(msg.domain, msg.mailbox) })
+	}
+	if err != nil && m.active == viewGenerate {
+		m.generate.flash = "forwarding " + msg.mailbox + "@" + msg.domain + ": " + err.Error()
+		return m, clearFlashAfter()
+	}
+	return m, nil
+}
+
+// recordForwarding updates the ledger of forwarding rules zburn created, so
+// reconciling later removes only those.
+func (m Model) recordForwarding(update func(forwarding.Ledger) forwarding.Ledger) error {
+	if m.configs == nil {
+		return nil
+	}
+	l := loadConfig[forwarding.Ledger](m.configs, config.KeyForwarded)
+	if err := saveConfig(m.configs, config.KeyForwarded, update(l)); err != nil {
+		return fmt.Errorf("record forwarding: %w", err)
+	}
+	return nil
+}
+
+// forwardingFlashed shows a forwarding outcome on the active settings view,
+// refreshing the status list when the forwarding view is open.
+func (m Model) forwardingFlashed(flash string) (tea.Model, tea.Cmd) {
 	switch m.active {
 	case viewSettingsNamecheap:
 		m.settingsNamecheap.flash = flash
 	case viewSettingsGmail:
 		m.settingsGmail.flash = flash
+	case viewForwarding:
+		m.forwarding.flash = flash
+		return m, tea.Batch(clearFlashAfter(), fetchForwardingStatusCmd(m.ncConfig.NamecheapConfig(), m.ncConfig.CachedDomains))
 	case viewSettings:
 		// no flash field on settings menu; ignore
 	}
@@ -807,6 +1179,161 @@ func (m Model) handleForwardingResult(msg forwardingResultMsg) (tea.Model, tea.C
 	return m, clearFlashAfter()
 }
 
+// setupForwarding returns the command that installs forwarding for the
+// configured mode: a catch-all per domain, or one rule per saved identity.
+func (m Model) setupForwarding(nc NamecheapSettings, forwardTo string) tea.Cmd {
+	if nc.PerMailbox() {
+		return m.reconcileForwarding(nc, forwardTo)
+	}
+	return forwardingCmd(nc.NamecheapConfig(), nc.CachedDomains, forwardTo)
+}
+
+func (m Model) reconcileForwarding(nc NamecheapSettings, forwardTo string) tea.Cmd {
+	var ids []identity.Identity
+	if m.identities != nil {
+		list, err := m.identities.List()
+		if err != nil {
+			return func() tea.Msg { return reconcileResultMsg{err: fmt.Errorf("list identities: %w", err)} }
+		}
+		ids = list
+	}
+	created := loadConfig[forwarding.Ledger](m.configs, config.KeyForwarded)
+	return reconcileCmd(nc.NamecheapConfig(), nc.CachedDomains, ids, created, forwardTo)
+}
+
+func (m Model) handleSetForwardingMode(mode string) (tea.Model, tea.Cmd) {
+	nc := m.ncConfig
+	nc.ForwardingMode = mode
+
+	if err := saveConfig(m.configs, "namecheap", nc); err != nil {
+		m.forwarding.loading = false
+		m.forwarding.flash = "save: " + err.Error()
+		return m, clearFlashAfter()
+	}
+
+	m.ncConfig = nc
+	m.forwarding.perMailbox = nc.PerMailbox()
+	return m, m.setupForwarding(nc, m.gmConfig.Email)
+}
+
+func (m Model) loadInbox(id identity.Identity) (tea.Model, tea.Cmd) {
+	if !m.gmConfig.Configured() {
+		m.detail.flash = "gmail not connected"
+		return m, clearFlashAfter()
+	}
+
+	if m.active != viewInbox || m.inbox.identity.ID != id.ID {
+		m.inbox = newInboxModel(id)
+	}
+	m.inbox.loading = true
+	m.active = viewInbox
+	return m, fetchInboxCmd(m.gmConfig, id.Email)
+}
+
+func (m Model) handleInboxResult(msg inboxResultMsg) (tea.Model, tea.Cmd) {
+	// persist a refreshed access token so the next fetch can reuse it
+	if msg.token != nil {
+		m.gmConfig.Token = msg.token
+		_ = saveConfig(m.configs, "gmail", m.gmConfig)
+	}
+
+	m.inbox, _ = m.inbox.Update(msg)
+	return m, nil
+}
+
+func (m Model) loadSMS(id identity.Identity) (tea.Model, tea.Cmd) {
+	if !m.twConfig.Configured() {
+		m.detail.flash = "twilio not configured"
+		return m, clearFlashAfter()
+	}
+
+	phone := m.phoneFor(id.ID)
+	if phone == nil {
+		m.detail.flash = "no phone number provisioned"
+		return m, clearFlashAfter()
+	}
+
+	if m.active != viewSMS || m.sms.identity.ID != id.ID {
+		m.sms = newSMSModel(id, phone.PhoneNumber)
+	}
+	m.sms.loading = true
+	m.active = viewSMS
+	return m, fetchSMSCmd(m.twConfig.TwilioConfig(), phone.PhoneNumber)
+}
+
+// phoneFor returns the provisioned phone for an identity, or nil.
+// Numbers bought through zburn are checked before the external lookup.
+func (m Model) phoneFor(identityID string) *burn.PhoneConfig {
+	if m.phones != nil {
+		if p, err := m.phones.Get(identityID); err == nil {
+			return &p
+		}
+	}
+	if m.external.PhoneForIdentity == nil {
+		return nil
+	}
+	return m.external.PhoneForIdentity(identityID)
+}
+
+func (m Model) startProvision(id identity.Identity) (tea.Model, tea.Cmd) {
+	if !m.twConfig.Configured() {
+		m.detail.flash = "twilio not configured"
+		return m, clearFlashAfter()
+	}
+
+	if phone := m.phoneFor(id.ID); phone != nil {
+		m.detail.flash = "phone already provisioned: " + phone.PhoneNumber
+		return m, clearFlashAfter()
+	}
+
+	m.provision = newProvisionModel(id)
+	m.active = viewProvision
+	return m, tea.Batch(tea.ClearScreen, searchNumbersCmd(m.twConfig.TwilioConfig(), m.twConfig.PreferredCountries))
+}
+
+// handleNumberBought records a purchased number against the identity and
+// replaces its generated phone with the real one.
+func (m Model) handleNumberBought(msg numberBoughtMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.provision, _ = m.provision.Update(msg)
+		return m, nil
+	}
+
+	phone := burn.PhoneConfig{
+		NumberSID:   msg.number.SID,
+		PhoneNumber: msg.number.PhoneNumber,
+	}
+
+	if m.phones == nil {
+		m.provision, _ = m.provision.Update(numberBoughtMsg{err: fmt.Errorf("store not open (bought %s, sid %s)", phone.PhoneNumber, phone.NumberSID)})
+		return m, nil
+	}
+
+	if err := m.phones.Put(msg.identity.ID, phone); err != nil {
+		// the number is paid for; surface the SID so it can be released by hand
+		m.provision, _ = m.provision.Update(numberBoughtMsg{err: fmt.Errorf("save %s (sid %s): %w", phone.PhoneNumber, phone.NumberSID, err)})
+		return m, nil
+	}
+
+	id := msg.identity
+	id.Phone = phone.PhoneNumber
+	if err := m.identities.Put(id.ID, id); err != nil {
+		m.provision, _ = m.provision.Update(numberBoughtMsg{err: fmt.Errorf("save identity: %w", err)})
+		return m, nil
+	}
+
+	// the number is recorded either way; only jump to it from the flow
+	// that bought it
+	if m.active != viewProvision || m.provision.identity.ID != id.ID {
+		return m, nil
+	}
+
+	next, _ := m.handleViewIdentity(id)
+	m = next.(Model)
+	m.detail.flash = "provisioned " + phone.PhoneNumber
+	return m, tea.Batch(tea.ClearScreen, clearFlashAfter())
+}
+
 func (m Model) handleDisconnectGmail() (tea.Model, tea.Cmd) {
 	m.gmConfig.Token = nil
 	m.gmConfig.Email = ""
@@ -860,17 +1387,55 @@ func (m Model) buildBurnRequest(id identity.Identity) burn.Request {
 		Identities:  identityStoreOrEmpty(m.identities),
 	}
 
-	// phone release — configured when we have a releaser and a lookup func
-	if m.external.Releaser != nil && m.external.PhoneForIdentity != nil {
-		if phone := m.external.PhoneForIdentity(id.ID); phone != nil {
-			req.Phone = phone
-			req.Releaser = m.external.Releaser
+	// a provisioned number without a releaser keeps the identity, so the
+	// number is not forgotten while still billed
+	if phone := m.phoneFor(id.ID); phone != nil {
+		req.Phone = phone
+		req.Releaser = m.releaser()
+		// only numbers bought through zburn have a mapping to clean up
+		if m.phones != nil {
+			if _, err := m.phones.Get(id.ID); err == nil {
+				req.Phones = m.phones
+			}
+		}
+	}
+
+	// in catch-all mode the domain's "*" rule keeps forwarding; there is no
+	// per-identity rule to remove. Rules zburn did not create are left alone.
+	if mailbox, domain, ok := forwarding.Managed(id.Email, m.ncConfig.CachedDomains); ok && m.ncConfig.PerMailbox() {
+		if loadConfig[forwarding.Ledger](m.configs, config.KeyForwarded).Has(domain, mailbox) {
+			req.Forwarding = m.forwardingRemover()
+			req.Ledger = config.Forwarded{Col: m.configs}
 		}
 	}
 
 	return req
 }
 
+// forwardingRemover returns the injected remover, falling back to a
+// Namecheap client built from the stored settings.
+func (m Model) forwardingRemover() burn.ForwardingRemover {
+	if m.external.Forwarding != nil {
+		return m.external.Forwarding
+	}
+	if m.ncConfig.Configured() {
+		return namecheap.NewClient(m.ncConfig.NamecheapConfig())
+	}
+	return nil
+}
+
+// releaser returns the injected phone releaser, falling back to a Twilio
+// client built from the stored settings.
+func (m Model) releaser() burn.PhoneReleaser {
+	if m.external.Releaser != nil {
+		return m.external.Releaser
+	}
+	if m.twConfig.Configured() {
+		return twilio.NewClient(m.twConfig.TwilioConfig())
+	}
+	return nil
+}
+
 // credentialStoreOrEmpty returns the collection as a burn.CredentialStore,
 // or a no-op store if the collection is nil (store not yet opened).
 func credentialStoreOrEmpty(col *zstore.Collection[credential.Credential]) burn.CredentialStore {
@@ -651,6 +651,74 @@ func TestDetailFlashClears(t *testing.T) {
 	}
 }
 
+func TestDetailEditEmitsEditIdentityMsg(t *testing.T) {
+	m := newDetailModel(testIdentity())
+	_, cmd := m.Update(keyMsg('e'))
+	if cmd == nil {
+		t.Fatal("e should produce command")
+	}
+	if msg, ok := cmd().(editIdentityMsg); !ok || msg.identity.ID != "abc12345" {
+		t.Errorf("expected editIdentityMsg, got %T", cmd())
+	}
+}
+
+func TestIdentityEditSave(t *testing.T) {
+	id := testIdentity()
+	id.Tags = []string{"old"}
+	m := newIdentityEditModel(id)
+
+	if m.inputs[metaTags].Value() != "old" {
+		t.Errorf("tags input = %q, want existing tags", m.inputs[metaTags].Value())
+	}
+
+	for _, r := range "Acme trial" {
+		m, _ = m.Update(keyMsg(r))
+	}
+	m, _ = m.Update(tabKey())
+	for _, r := range ", Acme trial" {
+		m, _ = m.Update(keyMsg(r))
+	}
+
+	_, cmd := m.Update(enterKey())
+	msg, ok := cmd().(updateIdentityMsg)
+	if !ok {
+		t.Fatal("enter should emit updateIdentityMsg")
+	}
+	if msg.identity.Label != "Acme trial" || strings.Join(msg.identity.Tags, ",") != "acme,old,trial" {
+		t.Errorf("saved identity = %+v", msg.identity)
+	}
+}
+
+func TestIdentityEditEscReturnsToDetail(t *testing.T) {
+	m := newIdentityEditModel(testIdentity())
+	_, cmd := m.Update(escKey())
+	if _, ok := cmd().(viewIdentityMsg); !ok {
+		t.Error("esc should return to the identity detail")
+	}
+}
+
+func TestIdentityFieldsMeta(t *testing.T) {
+	id := testIdentity()
+	id.Label = "acme trial"
+	id.Tags = []string{"acme", "trial"}
+
+	fields := identityFields(id)
+	last := fields[len(fields)-2:]
+	if last[0].label != "label" || !last[0].section || last[1].value != "acme, trial" {
+		t.Errorf("meta fields = %+v", last)
+	}
+}
+
+func TestListViewShowsLabelAndTags(t *testing.T) {
+	id := testIdentity()
+	id.Label = "acme trial"
+	id.Tags = []string{"acme"}
+	m := newListModel([]identity.Identity{id})
+	if !strings.Contains(m.View(), "acme trial #acme") {
+		t.Errorf("list should show label and tags:\n%s", m.View())
+	}
+}
+
 // root model navigation tests
 
 func TestRootStartsAtPassword(t *testing.T) {
@@ -818,6 +886,8 @@ func TestTruncate(t *testing.T) {
 		{"short", 10, "short"},
 		{"exactly10!", 10, "exactly10!"},
 		{"this is too long", 10, "this is t…"},
+		{"café", 4, "café"},
+		{"zürich-münchen", 8, "zürich-…"},
 	}
 
 	for _, tt := range tests {
@@ -854,6 +924,30 @@ func TestIdentityFields(t *testing.T) {
 	}
 }
 
+func TestIdentityFieldsForeignCountry(t *testing.T) {
+	id := testIdentity()
+	id.City, id.State, id.Zip, id.Country = "Leeds", "West Yorkshire", "LS1 4AB", "GB"
+
+	fields := identityFields(id)
+	if fields[4].value != "Leeds, West Yorkshire LS1 4AB, GB" {
+		t.Errorf("address = %q, want country suffix", fields[4].value)
+	}
+}
+
+func TestIdentityFieldsTemplate(t *testing.T) {
+	id := testIdentity()
+	id.Template = "signup"
+	id.Fields = []identity.Field{{Name: "company", Value: "Bluepeak Labs"}}
+
+	fields := identityFields(id)
+	if len(fields) != 7 {
+		t.Fatalf("fields length = %d, want 7", len(fields))
+	}
+	if fields[6].label != "company" || fields[6].value != "Bluepeak Labs" {
+		t.Errorf("field[6] = %v, want company=Bluepeak Labs", fields[6])
+	}
+}
+
 // burn view tests
 
 func TestBurnConfirmViewShowsPlan(t *testing.T) {
@@ -1201,6 +1295,65 @@ func TestNavigateToGenerateWithDomainIdxPreserved(t *testing.T) {
 	}
 }
 
+// template rotation tests
+
+func TestCycleTemplateMsg(t *testing.T) {
+	m := New("1.0", t.TempDir(), identity.New(), false)
+	m.templateList = []identity.Template{
+		{Name: "signup", Fields: []identity.TemplateField{{Name: "source", Value: "qa"}}},
+	}
+	m.active = viewGenerate
+	m.generate = m.newGenerate(testIdentity(), "")
+
+	result, _ := m.Update(cycleTemplateMsg{})
+	rm := result.(Model)
+	if rm.generate.identity.Template != "signup" {
+		t.Errorf("template = %q, want signup", rm.generate.identity.Template)
+	}
+	if v, _ := rm.generate.identity.FieldValue("source"); v != "qa" {
+		t.Errorf("source = %q, want qa", v)
+	}
+	if rm.generate.identity.ID != testIdentity().ID {
+		t.Error("cycling template should keep the identity")
+	}
+	if !strings.Contains(rm.generate.View(), "template: signup") {
+		t.Error("view should show the active template")
+	}
+
+	// wraps back to no template
+	result, _ = rm.Update(cycleTemplateMsg{})
+	rm = result.(Model)
+	if rm.generate.identity.Template != "" || rm.generate.identity.Fields != nil {
+		t.Errorf("wrap should clear template fields: %+v", rm.generate.identity)
+	}
+}
+
+func TestNavigateToGenerateUsesTemplate(t *testing.T) {
+	m := New("1.0", t.TempDir(), identity.New(), false)
+	m.templateList = []identity.Template{
+		{Name: "signup", Fields: []identity.TemplateField{{Name: "handle", Generator: "username"}}},
+	}
+	m.templateIdx = 1
+	m.active = viewMenu
+
+	result, _ := m.Update(navigateMsg{view: viewGenerate})
+	rm := result.(Model)
+	if v, ok := rm.generate.identity.FieldValue("handle"); !ok || v == "" {
+		t.Errorf("generated identity should have the template field: %+v", rm.generate.identity.Fields)
+	}
+}
+
+func TestTKeyProducesCycleTemplateMsg(t *testing.T) {
+	m := newGenerateModel(testIdentity(), "")
+	_, cmd := m.Update(keyMsg('t'))
+	if cmd == nil {
+		t.Fatal("t should produce command")
+	}
+	if _, ok := cmd().(cycleTemplateMsg); !ok {
+		t.Error("should emit cycleTemplateMsg")
+	}
+}
+
 func TestSaveNamecheapRefreshesDomains(t *testing.T) {
 	m := New("1.0", t.TempDir(), identity.New(), false)
 	m.domains = nil